@@ -17,13 +17,30 @@ type Account struct {
 	CompanyName      string   `xml:"company_name,omitempty"`
 	VATNumber        string   `xml:"vat_number,omitempty"`
 	TaxExempt        NullBool `xml:"tax_exempt,omitempty"`
+	EntityUseCode    string   `xml:"entity_use_code,omitempty"` // Avalara AvaTax exemption code, such as "G" for resale
 	BillingInfo      *Billing `xml:"billing_info,omitempty"`
-	Address          Address  `xml:"address,omitempty"`
+	Address          Address  `xml:"address,omitempty"` // Used as the tax address when it differs from the billing address
 	AcceptLanguage   string   `xml:"accept_language,omitempty"`
+	PreferredLocale  string   `xml:"preferred_locale,omitempty"`
+	CCEmails         string   `xml:"cc_emails,omitempty"` // Comma separated
 	HostedLoginToken string   `xml:"hosted_login_token,omitempty"`
 	CreatedAt        NullTime `xml:"created_at,omitempty"`
+
+	// Parent/child account hierarchies. Set ParentAccountCode when creating
+	// or updating an account to make it a child of that account.
+	ParentAccountCode string `xml:"parent_account_code,omitempty"`
+	BillTo            string `xml:"bill_to,omitempty"`
 }
 
+// Bill to constants for child accounts.
+const (
+	// AccountBillToSelf bills the child account directly.
+	AccountBillToSelf = "self"
+
+	// AccountBillToParent bills the child account's charges to its parent.
+	AccountBillToParent = "parent"
+)
+
 // AccountBalance is used for getting the account balance.
 type AccountBalance struct {
 	XMLName     xml.Name `xml:"account_balance"`
@@ -32,6 +49,38 @@ type AccountBalance struct {
 	Balance     int      `xml:"balance_in_cents>USD"`
 }
 
+// Account acquisition channels.
+const (
+	AcquisitionChannelReferral         = "referral"
+	AcquisitionChannelSocialMedia      = "social_media"
+	AcquisitionChannelEmail            = "email"
+	AcquisitionChannelPaidSearch       = "paid_search"
+	AcquisitionChannelOrganicSearch    = "organic_search"
+	AcquisitionChannelDirectTraffic    = "direct_traffic"
+	AcquisitionChannelMarketingContent = "marketing_content"
+	AcquisitionChannelBlog             = "blog"
+	AcquisitionChannelEvents           = "events"
+	AcquisitionChannelOutboundSales    = "outbound_sales"
+	AcquisitionChannelAdvertising      = "advertising"
+	AcquisitionChannelPublicRelations  = "public_relations"
+	AcquisitionChannelOther            = "other"
+)
+
+// AccountAcquisition holds the cost and marketing channel used to acquire
+// an account.
+// https://dev.recurly.com/docs/account-acquisition
+type AccountAcquisition struct {
+	XMLName     xml.Name `xml:"account_acquisition"`
+	AccountCode string   `xml:"-"`
+	CostInCents int      `xml:"cost_in_cents,omitempty"`
+	Currency    string   `xml:"currency,omitempty"`
+	Channel     string   `xml:"channel,omitempty"`
+	Subchannel  string   `xml:"subchannel,omitempty"`
+	Campaign    string   `xml:"campaign,omitempty"`
+	CreatedAt   NullTime `xml:"created_at,omitempty"` // Read only
+	UpdatedAt   NullTime `xml:"updated_at,omitempty"` // Read only
+}
+
 // Address is used for embedded addresses within other structs.
 type Address struct {
 	Address  string `xml:"address1,omitempty"`
@@ -1,7 +1,6 @@
 package recurly
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 )
@@ -14,6 +13,27 @@ const (
 	AccountStateClosed = "closed"
 )
 
+// Account search params.
+const (
+	// AccountSearchEmail filters accounts by exact email address.
+	AccountSearchEmail = "email"
+
+	// AccountSearchQuery filters accounts using a free-text query.
+	AccountSearchQuery = "q"
+)
+
+// accountSearchParams holds the params accepted by Search. Filters are
+// checked locally so that a typo doesn't silently list every account.
+var accountSearchParams = map[string]bool{
+	AccountSearchEmail: true,
+	AccountSearchQuery: true,
+	"state":            true,
+	"per_page":         true,
+	"cursor":           true,
+	"sort":             true,
+	"order":            true,
+}
+
 var _ AccountsService = &accountsImpl{}
 
 // accountsImpl handles communication with the accounts related methods
@@ -30,17 +50,30 @@ func (s *accountsImpl) List(params Params) (*Response, []Account, error) {
 		return nil, nil, err
 	}
 
-	var a struct {
-		XMLName  xml.Name  `xml:"accounts"`
-		Accounts []Account `xml:"account"`
+	resp, accounts, err := doList[Account](s.client, req, "accounts", "account")
+	for i := range accounts {
+		accounts[i].BillingInfo = nil
 	}
-	resp, err := s.client.do(req, &a)
 
-	for i := range a.Accounts {
-		a.Accounts[i].BillingInfo = nil
+	return resp, accounts, err
+}
+
+// Search returns the accounts matching an email (AccountSearchEmail) or
+// free-text query (AccountSearchQuery). At least one of the two must be
+// provided. Any other filter returns an error without making an API call,
+// though pagination and sorting params are passed through.
+// https://dev.recurly.com/docs/list-accounts
+func (s *accountsImpl) Search(params Params) (*Response, []Account, error) {
+	if params[AccountSearchEmail] == nil && params[AccountSearchQuery] == nil {
+		return nil, nil, fmt.Errorf("recurly: account search requires %q or %q", AccountSearchEmail, AccountSearchQuery)
+	}
+	for k := range params {
+		if !accountSearchParams[k] {
+			return nil, nil, fmt.Errorf("recurly: unsupported account search param %q", k)
+		}
 	}
 
-	return resp, a.Accounts, err
+	return s.List(params)
 }
 
 // Get returns information about a single account.
@@ -146,11 +179,129 @@ func (s *accountsImpl) ListNotes(code string) (*Response, []Note, error) {
 		return nil, nil, err
 	}
 
-	var n struct {
-		XMLName xml.Name `xml:"notes"`
-		Notes   []Note   `xml:"note"`
+	return doList[Note](s.client, req, "notes", "note")
+}
+
+// ListChildAccounts returns a list of the child accounts of a parent account.
+// https://dev.recurly.com/docs/list-child-accounts
+func (s *accountsImpl) ListChildAccounts(parentCode string, params Params) (*Response, []Account, error) {
+	action := fmt.Sprintf("accounts/%s/child_accounts", parentCode)
+	req, err := s.client.newRequest("GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, accounts, err := doList[Account](s.client, req, "accounts", "account")
+	for i := range accounts {
+		accounts[i].BillingInfo = nil
+	}
+
+	return resp, accounts, err
+}
+
+// ListTransactions returns a list of the transactions on an account. It is
+// the same as Transactions.ListAccount; params are passed through for
+// filtering and pagination.
+// https://dev.recurly.com/docs/list-accounts-transactions
+func (s *accountsImpl) ListTransactions(code string, params Params) (*Response, []Transaction, error) {
+	action := fmt.Sprintf("accounts/%s/transactions", code)
+	req, err := s.client.newRequest("GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doList[Transaction](s.client, req, "transactions", "transaction")
+}
+
+// ListInvoices returns a list of the invoices on an account. It is the same
+// as Invoices.ListAccount; params are passed through for filtering and
+// pagination.
+// https://dev.recurly.com/docs/list-an-accounts-invoices
+func (s *accountsImpl) ListInvoices(code string, params Params) (*Response, []Invoice, error) {
+	action := fmt.Sprintf("accounts/%s/invoices", code)
+	req, err := s.client.newRequest("GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doList[Invoice](s.client, req, "invoices", "invoice")
+}
+
+// ListRedemptions returns all coupon redemptions on an account, including
+// expired and inactive redemptions.
+func (s *accountsImpl) ListRedemptions(code string, params Params) (*Response, []CouponRedemption, error) {
+	action := fmt.Sprintf("accounts/%s/redemptions", code)
+	req, err := s.client.newRequest("GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doList[CouponRedemption](s.client, req, "redemptions", "redemption")
+}
+
+// GetAcquisition returns the acquisition details for an account.
+// https://dev.recurly.com/docs/lookup-account-acquisition
+func (s *accountsImpl) GetAcquisition(code string) (*Response, *AccountAcquisition, error) {
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := s.client.newRequest("GET", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst AccountAcquisition
+	resp, err := s.client.do(req, &dst)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, err
 	}
-	resp, err := s.client.do(req, &n)
+	dst.AccountCode = code
+
+	return resp, &dst, err
+}
+
+// CreateAcquisition adds acquisition details to an account.
+// https://dev.recurly.com/docs/create-account-acquisition
+func (s *accountsImpl) CreateAcquisition(code string, a AccountAcquisition) (*Response, *AccountAcquisition, error) {
+	return s.writeAcquisition("POST", code, a)
+}
+
+// UpdateAcquisition updates the acquisition details of an account.
+// https://dev.recurly.com/docs/update-account-acquisition
+func (s *accountsImpl) UpdateAcquisition(code string, a AccountAcquisition) (*Response, *AccountAcquisition, error) {
+	return s.writeAcquisition("PUT", code, a)
+}
 
-	return resp, n.Notes, err
+// writeAcquisition creates or updates acquisition details, sending only
+// the writeable fields.
+func (s *accountsImpl) writeAcquisition(method string, code string, a AccountAcquisition) (*Response, *AccountAcquisition, error) {
+	clean := AccountAcquisition{
+		CostInCents: a.CostInCents,
+		Currency:    a.Currency,
+		Channel:     a.Channel,
+		Subchannel:  a.Subchannel,
+		Campaign:    a.Campaign,
+	}
+
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := s.client.newRequest(method, action, nil, clean)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst AccountAcquisition
+	resp, err := s.client.do(req, &dst)
+	dst.AccountCode = code
+
+	return resp, &dst, err
+}
+
+// DeleteAcquisition removes the acquisition details from an account.
+// https://dev.recurly.com/docs/clear-account-acquisition
+func (s *accountsImpl) DeleteAcquisition(code string) (*Response, error) {
+	action := fmt.Sprintf("accounts/%s/acquisition", code)
+	req, err := s.client.newRequest("DELETE", action, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.do(req, nil)
 }
@@ -32,7 +32,13 @@ func TestAccounts_Encoding(t *testing.T) {
 		{v: recurly.Account{VATNumber: "123456789"}, expected: "<account><vat_number>123456789</vat_number></account>"},
 		{v: recurly.Account{TaxExempt: recurly.NewBool(true)}, expected: "<account><tax_exempt>true</tax_exempt></account>"},
 		{v: recurly.Account{TaxExempt: recurly.NewBool(false)}, expected: "<account><tax_exempt>false</tax_exempt></account>"},
+		{v: recurly.Account{EntityUseCode: "G"}, expected: "<account><entity_use_code>G</entity_use_code></account>"},
+		{v: recurly.Account{TaxExempt: recurly.NewBool(true), EntityUseCode: "E", Address: recurly.Address{Address: "1 Main St.", City: "Austin", State: "TX", Zip: "78701", Country: "US"}}, expected: "<account><tax_exempt>true</tax_exempt><entity_use_code>E</entity_use_code><address><address1>1 Main St.</address1><city>Austin</city><state>TX</state><zip>78701</zip><country>US</country></address></account>"},
 		{v: recurly.Account{AcceptLanguage: "en_US"}, expected: "<account><accept_language>en_US</accept_language></account>"},
+		{v: recurly.Account{PreferredLocale: "fr-FR"}, expected: "<account><preferred_locale>fr-FR</preferred_locale></account>"},
+		{v: recurly.Account{CCEmails: "billing@example.com,finance@example.com"}, expected: "<account><cc_emails>billing@example.com,finance@example.com</cc_emails></account>"},
+		{v: recurly.Account{VATNumber: "FR40303265045", PreferredLocale: "fr-FR", CCEmails: "billing@example.com"}, expected: "<account><vat_number>FR40303265045</vat_number><preferred_locale>fr-FR</preferred_locale><cc_emails>billing@example.com</cc_emails></account>"},
+		{v: recurly.Account{Code: "child", ParentAccountCode: "parent", BillTo: recurly.AccountBillToParent}, expected: "<account><account_code>child</account_code><parent_account_code>parent</parent_account_code><bill_to>parent</bill_to></account>"},
 		{v: recurly.Account{FirstName: "Larry", Address: recurly.Address{Address: "123 Main St.", City: "San Francisco", State: "CA", Zip: "94105", Country: "US"}}, expected: "<account><first_name>Larry</first_name><address><address1>123 Main St.</address1><city>San Francisco</city><state>CA</state><zip>94105</zip><country>US</country></address></account>"},
 		{v: recurly.Account{Code: "test@example.com", BillingInfo: &recurly.Billing{Token: "507c7f79bcf86cd7994f6c0e"}}, expected: "<account><account_code>test@example.com</account_code><billing_info><token_id>507c7f79bcf86cd7994f6c0e</token_id></billing_info></account>"},
 		{v: recurly.Address{}, expected: ""},
@@ -160,6 +166,71 @@ func TestAccounts_List_Pagination(t *testing.T) {
 	}
 }
 
+func TestAccounts_Search(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if email := r.URL.Query().Get("email"); email != "verena@example.com" {
+			t.Fatalf("unexpected email: %s", email)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<accounts>
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1">
+			  <billing_info href="https://your-subdomain.recurly.com/v2/accounts/1/billing_info"/>
+			  <account_code>1</account_code>
+			  <state>active</state>
+			  <email>verena@example.com</email>
+			</account>
+		</accounts>`)
+	})
+
+	resp, accounts, err := client.Accounts.Search(recurly.Params{
+		recurly.AccountSearchEmail: "verena@example.com",
+		"per_page":                 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected search accounts to return OK")
+	} else if pp := resp.Request.URL.Query().Get("per_page"); pp != "1" {
+		t.Fatalf("unexpected per_page: %s", pp)
+	} else if !reflect.DeepEqual(accounts, []recurly.Account{{
+		XMLName: xml.Name{Local: "account"},
+		Code:    "1",
+		State:   "active",
+		Email:   "verena@example.com",
+	}}) {
+		t.Fatalf("unexpected accounts: %v", accounts)
+	}
+}
+
+func TestAccounts_Search_Err(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected API call")
+	})
+
+	tests := []recurly.Params{
+		nil,
+		{"per_page": 20},
+		{recurly.AccountSearchEmail: "verena@example.com", "emial": "verena@example.com"},
+	}
+
+	for i, params := range tests {
+		if resp, accounts, err := client.Accounts.Search(params); err == nil {
+			t.Fatalf("(%d): expected error", i)
+		} else if resp != nil || accounts != nil {
+			t.Fatalf("(%d): unexpected result: %v %v", i, resp, accounts)
+		}
+	}
+}
+
 func TestAccounts_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -414,3 +485,413 @@ func TestAccounts_ListNotes(t *testing.T) {
 		t.Fatalf("unexpected notes: %v", notes)
 	}
 }
+
+func TestAccounts_GetAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<account_acquisition href="https://your-subdomain.recurly.com/v2/accounts/1/acquisition">
+			  <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			  <cost_in_cents type="integer">199</cost_in_cents>
+			  <currency>USD</currency>
+			  <channel>blog</channel>
+			  <subchannel>Whitepaper Blog Post</subchannel>
+			  <campaign>mailchimp67a904de95.0914d8f4b4</campaign>
+			  <created_at type="datetime">2016-08-03T15:44:05Z</created_at>
+			  <updated_at type="datetime">2016-08-03T15:44:05Z</updated_at>
+			</account_acquisition>`)
+	})
+
+	resp, a, err := client.Accounts.GetAcquisition("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected get acquisition to return OK")
+	}
+
+	ts := recurly.NewTime(time.Date(2016, time.August, 3, 15, 44, 5, 0, time.UTC))
+	if !reflect.DeepEqual(a, &recurly.AccountAcquisition{
+		XMLName:     xml.Name{Local: "account_acquisition"},
+		AccountCode: "1",
+		CostInCents: 199,
+		Currency:    "USD",
+		Channel:     recurly.AcquisitionChannelBlog,
+		Subchannel:  "Whitepaper Blog Post",
+		Campaign:    "mailchimp67a904de95.0914d8f4b4",
+		CreatedAt:   ts,
+		UpdatedAt:   ts,
+	}) {
+		t.Fatalf("unexpected acquisition: %#v", a)
+	}
+}
+
+func TestAccounts_GetAcquisition_ErrNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, a, err := client.Accounts.GetAcquisition("1")
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a != nil {
+		t.Fatalf("expected acquisition to be nil: %#v", a)
+	}
+}
+
+func TestAccounts_CreateAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected := "<account_acquisition><cost_in_cents>199</cost_in_cents><currency>USD</currency><channel>blog</channel><subchannel>Whitepaper Blog Post</subchannel><campaign>mailchimp67a904de95.0914d8f4b4</campaign></account_acquisition>"; given.String() != expected {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account_acquisition><channel>blog</channel></account_acquisition>`)
+	})
+
+	resp, a, err := client.Accounts.CreateAcquisition("1", recurly.AccountAcquisition{
+		CostInCents: 199,
+		Currency:    "USD",
+		Channel:     recurly.AcquisitionChannelBlog,
+		Subchannel:  "Whitepaper Blog Post",
+		Campaign:    "mailchimp67a904de95.0914d8f4b4",
+		CreatedAt:   recurly.NewTime(time.Now()), // Read only, not sent
+	})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 201 {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if a.AccountCode != "1" || a.Channel != recurly.AcquisitionChannelBlog {
+		t.Fatalf("unexpected acquisition: %#v", a)
+	}
+}
+
+func TestAccounts_UpdateAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected := "<account_acquisition><channel>email</channel></account_acquisition>"; given.String() != expected {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account_acquisition><channel>email</channel></account_acquisition>`)
+	})
+
+	resp, _, err := client.Accounts.UpdateAcquisition("1", recurly.AccountAcquisition{Channel: recurly.AcquisitionChannelEmail})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected update acquisition to return OK")
+	}
+}
+
+func TestAccounts_DeleteAcquisition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/acquisition", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(204)
+	})
+
+	resp, err := client.Accounts.DeleteAcquisition("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected delete acquisition to return OK")
+	}
+}
+
+func TestAccounts_Decode_Localization(t *testing.T) {
+	var a recurly.Account
+	if err := xml.Unmarshal([]byte(`<account href="https://your-subdomain.recurly.com/v2/accounts/1">
+		<account_code>1</account_code>
+		<vat_number>FR40303265045</vat_number>
+		<accept_language>fr</accept_language>
+		<preferred_locale>fr-FR</preferred_locale>
+		<cc_emails>billing@example.com,finance@example.com</cc_emails>
+	</account>`), &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(a, recurly.Account{
+		XMLName:         xml.Name{Local: "account"},
+		Code:            "1",
+		VATNumber:       "FR40303265045",
+		AcceptLanguage:  "fr",
+		PreferredLocale: "fr-FR",
+		CCEmails:        "billing@example.com,finance@example.com",
+	}) {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+}
+
+func TestAccounts_ListChildAccounts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/parent/child_accounts", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("per_page") != "20" {
+			t.Fatalf("unexpected per_page: %s", r.URL.Query().Get("per_page"))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<accounts type="array">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/franchise-1">
+					<account_code>franchise-1</account_code>
+					<state>active</state>
+					<email>franchise-1@example.com</email>
+					<parent_account_code>parent</parent_account_code>
+					<bill_to>parent</bill_to>
+				</account>
+				<account href="https://your-subdomain.recurly.com/v2/accounts/franchise-2">
+					<account_code>franchise-2</account_code>
+					<state>active</state>
+					<email>franchise-2@example.com</email>
+					<parent_account_code>parent</parent_account_code>
+					<bill_to>self</bill_to>
+				</account>
+			</accounts>`)
+	})
+
+	resp, accounts, err := client.Accounts.ListChildAccounts("parent", recurly.Params{"per_page": 20})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected list child accounts to return OK")
+	} else if !reflect.DeepEqual(accounts, []recurly.Account{
+		{
+			XMLName:           xml.Name{Local: "account"},
+			Code:              "franchise-1",
+			State:             "active",
+			Email:             "franchise-1@example.com",
+			ParentAccountCode: "parent",
+			BillTo:            recurly.AccountBillToParent,
+		},
+		{
+			XMLName:           xml.Name{Local: "account"},
+			Code:              "franchise-2",
+			State:             "active",
+			Email:             "franchise-2@example.com",
+			ParentAccountCode: "parent",
+			BillTo:            recurly.AccountBillToSelf,
+		},
+	}) {
+		t.Fatalf("unexpected accounts: %#v", accounts)
+	}
+}
+
+func TestAccounts_ListTransactions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("per_page") != "20" || r.URL.Query().Get("cursor") != "1304958672" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transactions type="array">
+				<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+					<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+					<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+					<action>purchase</action>
+					<amount_in_cents type="integer">1000</amount_in_cents>
+					<currency>USD</currency>
+					<status>success</status>
+				</transaction>
+			</transactions>`)
+	})
+
+	resp, transactions, err := client.Accounts.ListTransactions("1", recurly.Params{"per_page": 20, "cursor": "1304958672"})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected list transactions to return OK")
+	} else if len(transactions) != 1 || transactions[0].UUID != "a13acd8fe4294916b79aec87b7ea441f" || transactions[0].AmountInCents != 1000 {
+		t.Fatalf("unexpected transactions: %#v", transactions)
+	}
+}
+
+func TestAccounts_ListInvoices(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/1/invoices", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("per_page") != "20" {
+			t.Fatalf("unexpected per_page: %s", r.URL.Query().Get("per_page"))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoices type="array">
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1005">
+					<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+					<uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>
+					<state>paid</state>
+					<invoice_number type="integer">1005</invoice_number>
+					<total_in_cents type="integer">1200</total_in_cents>
+					<currency>USD</currency>
+				</invoice>
+			</invoices>`)
+	})
+
+	resp, invoices, err := client.Accounts.ListInvoices("1", recurly.Params{"per_page": 20})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected list invoices to return OK")
+	} else if len(invoices) != 1 || invoices[0].InvoiceNumber != 1005 || invoices[0].TotalInCents != 1200 {
+		t.Fatalf("unexpected invoices: %#v", invoices)
+	}
+}
+
+func TestAccounts_ListRedemptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/redemptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("per_page") != "50" {
+			t.Fatalf("unexpected per_page: %s", r.URL.Query().Get("per_page"))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+        <redemptions type="array">
+            <redemption href="https://your-subdomain.recurly.com/v2/accounts/1/redemptions/34ab5a0b4f4aaa0e7e7e7e4c0aa46f82">
+                <coupon href="https://your-subdomain.recurly.com/v2/coupons/special"/>
+                <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+                <single_use type="boolean">true</single_use>
+                <total_discounted_in_cents type="integer">500</total_discounted_in_cents>
+                <currency>USD</currency>
+                <state>inactive</state>
+                <created_at type="datetime">2011-06-27T12:34:56Z</created_at>
+            </redemption>
+            <redemption href="https://your-subdomain.recurly.com/v2/accounts/1/redemptions/34ab5a0b4f4aaa0e7e7e7e4c0aa46f83">
+                <coupon href="https://your-subdomain.recurly.com/v2/coupons/loyalty"/>
+                <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+                <single_use type="boolean">false</single_use>
+                <total_discounted_in_cents type="integer">0</total_discounted_in_cents>
+                <currency>USD</currency>
+                <state>active</state>
+                <created_at type="datetime">2011-07-01T12:34:56Z</created_at>
+            </redemption>
+        </redemptions>`)
+	})
+
+	r, redemptions, err := client.Accounts.ListRedemptions("1", recurly.Params{"per_page": 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected list redemptions to return OK")
+	}
+
+	if !reflect.DeepEqual(redemptions, []recurly.CouponRedemption{
+		{
+			CouponCode:             "special",
+			AccountCode:            "1",
+			SingleUse:              recurly.NewBool(true),
+			TotalDiscountedInCents: 500,
+			Currency:               "USD",
+			State:                  "inactive",
+			CreatedAt:              recurly.NewTime(time.Date(2011, time.June, 27, 12, 34, 56, 0, time.UTC)),
+		},
+		{
+			CouponCode:             "loyalty",
+			AccountCode:            "1",
+			SingleUse:              recurly.NewBool(false),
+			TotalDiscountedInCents: 0,
+			Currency:               "USD",
+			State:                  "active",
+			CreatedAt:              recurly.NewTime(time.Date(2011, time.July, 1, 12, 34, 56, 0, time.UTC)),
+		},
+	}) {
+		t.Fatalf("unexpected redemptions: %v", redemptions)
+	}
+}
+
+func TestAccounts_Create_ChildAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected := "<account><account_code>franchise-1</account_code><parent_account_code>parent</parent_account_code><bill_to>parent</bill_to></account>"; given.String() != expected {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>franchise-1</account_code><parent_account_code>parent</parent_account_code><bill_to>parent</bill_to></account>`)
+	})
+
+	resp, a, err := client.Accounts.Create(recurly.Account{
+		Code:              "franchise-1",
+		ParentAccountCode: "parent",
+		BillTo:            recurly.AccountBillToParent,
+	})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 201 {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if a.ParentAccountCode != "parent" || a.BillTo != recurly.AccountBillToParent {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+}
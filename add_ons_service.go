@@ -1,7 +1,6 @@
 package recurly
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 )
@@ -17,19 +16,18 @@ type addOnsImpl struct {
 // List returns a list of add ons for a plan.
 // https://docs.recurly.com/api/plans/add-ons#list-addons
 func (s *addOnsImpl) List(planCode string, params Params) (*Response, []AddOn, error) {
+	return s.list(planCode, params)
+}
+
+// list returns a list of add ons for a plan, applying opts to the request.
+func (s *addOnsImpl) list(planCode string, params Params, opts ...RequestOption) (*Response, []AddOn, error) {
 	action := fmt.Sprintf("plans/%s/add_ons", planCode)
 	req, err := s.client.newRequest("GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var p struct {
-		XMLName xml.Name `xml:"add_ons"`
-		AddOns  []AddOn  `xml:"add_on"`
-	}
-	resp, err := s.client.do(req, &p)
-
-	return resp, p.AddOns, err
+	return doList[AddOn](s.client, req, "add_ons", "add_on", opts...)
 }
 
 // Get returns information about an add on.
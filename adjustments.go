@@ -2,10 +2,20 @@ package recurly
 
 import "encoding/xml"
 
+const (
+	// AdjustmentOriginPlan is the origin of line items charging for a plan.
+	AdjustmentOriginPlan = "plan"
+
+	// AdjustmentOriginAddOn is the origin of line items charging for a
+	// subscription add-on.
+	AdjustmentOriginAddOn = "add_on"
+)
+
 // Adjustment works with charges and credits on a given account.
 type Adjustment struct {
 	AccountCode            string
 	InvoiceNumber          int
+	SubscriptionUUID       string // Read only
 	UUID                   string
 	State                  string
 	Description            string
@@ -63,8 +73,9 @@ func (a Adjustment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 func (a *Adjustment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var v struct {
 		XMLName                xml.Name    `xml:"adjustment"`
-		AccountCode            hrefString  `xml:"account,omitempty"` // Read only
-		InvoiceNumber          hrefInt     `xml:"invoice,omitempty"` // Read only
+		AccountCode            hrefString  `xml:"account,omitempty"`      // Read only
+		InvoiceNumber          hrefInt     `xml:"invoice,omitempty"`      // Read only
+		SubscriptionUUID       hrefString  `xml:"subscription,omitempty"` // Read only
 		UUID                   string      `xml:"uuid,omitempty"`
 		State                  string      `xml:"state,omitempty"`
 		Description            string      `xml:"description,omitempty"`
@@ -95,6 +106,7 @@ func (a *Adjustment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	*a = Adjustment{
 		AccountCode:            string(v.AccountCode),
 		InvoiceNumber:          int(v.InvoiceNumber),
+		SubscriptionUUID:       string(v.SubscriptionUUID),
 		UUID:                   v.UUID,
 		State:                  v.State,
 		Description:            v.Description,
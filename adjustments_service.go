@@ -23,13 +23,7 @@ func (s *adjustmentsImpl) List(accountCode string, params Params) (*Response, []
 		return nil, nil, err
 	}
 
-	var a struct {
-		XMLName     xml.Name     `xml:"adjustments"`
-		Adjustments []Adjustment `xml:"adjustment"`
-	}
-	resp, err := s.client.do(req, &a)
-
-	return resp, a.Adjustments, err
+	return doList[Adjustment](s.client, req, "adjustments", "adjustment")
 }
 
 // Get returns information about a single adjustment.
@@ -54,9 +48,11 @@ func (s *adjustmentsImpl) Get(uuid string) (*Response, *Adjustment, error) {
 // collected immediately. Non-invoiced charges will automatically be invoices
 // when the account's subscription renews, or you trigger a collection by
 // posting an invoice. Charges may be removed from an account if they have
-// not been invoiced.
+// not been invoiced. If a.Currency is empty, the client's DefaultCurrency
+// is used.
 // https://docs.recurly.com/api/adjustments#create-adjustment
 func (s *adjustmentsImpl) Create(accountCode string, a Adjustment) (*Response, *Adjustment, error) {
+	a.Currency = s.client.currency(a.Currency)
 	action := fmt.Sprintf("accounts/%s/adjustments", accountCode)
 	req, err := s.client.newRequest("POST", action, nil, a)
 	if err != nil {
@@ -69,6 +65,41 @@ func (s *adjustmentsImpl) Create(accountCode string, a Adjustment) (*Response, *
 	return resp, &dst, err
 }
 
+// Preview returns an estimate of a one-time charge on an account, including
+// tax calculated from the account's address, without creating the charge.
+// Recurly does not provide an adjustment preview endpoint, so the charge is
+// previewed as a purchase and the resulting line item is returned. The
+// adjustment's currency is used for the purchase. If the preview returns no
+// line items, the adjustment will be nil.
+// https://dev.recurly.com/docs/purchase-preview
+func (s *adjustmentsImpl) Preview(accountCode string, a Adjustment) (*Response, *Adjustment, error) {
+	a.Currency = s.client.currency(a.Currency)
+	data := struct {
+		XMLName     xml.Name     `xml:"purchase"`
+		Currency    string       `xml:"currency"`
+		AccountCode string       `xml:"account>account_code"`
+		Adjustments []Adjustment `xml:"adjustments>adjustment"`
+	}{
+		Currency:    a.Currency,
+		AccountCode: accountCode,
+		Adjustments: []Adjustment{a},
+	}
+	req, err := s.client.newRequest("POST", "purchases/preview", nil, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst InvoiceCollection
+	resp, err := s.client.do(req, &dst)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, err
+	} else if dst.ChargeInvoice == nil || len(dst.ChargeInvoice.LineItems) == 0 {
+		return resp, nil, err
+	}
+
+	return resp, &dst.ChargeInvoice.LineItems[0], err
+}
+
 // Delete removes a non-invoiced adjustment from an account.
 // https://docs.recurly.com/api/adjustments#delete-adjustment
 func (s *adjustmentsImpl) Delete(uuid string) (*Response, error) {
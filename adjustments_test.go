@@ -97,6 +97,7 @@ func TestAdjustments_List(t *testing.T) {
 		{
 			AccountCode:            "100",
 			InvoiceNumber:          1108,
+			SubscriptionUUID:       "17caaca1716f33572edc8146e0aaefde",
 			UUID:                   "626db120a84102b1809909071c701c60",
 			State:                  "invoiced",
 			Description:            "One-time Charged Fee",
@@ -284,6 +285,74 @@ func TestAdjustments_Create(t *testing.T) {
 	}
 }
 
+func TestAdjustments_Preview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/purchases/preview", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Body.Close()
+		if !bytes.Equal(b, []byte("<purchase><currency>USD</currency><account><account_code>1</account_code></account><adjustments><adjustment><description>Setup fee</description><unit_amount_in_cents>1000</unit_amount_in_cents><currency>USD</currency></adjustment></adjustments></purchase>")) {
+			t.Fatalf("unexpected input: %s", string(b))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoice_collection>
+				<charge_invoice>
+					<state>pending</state>
+					<currency>USD</currency>
+					<subtotal_in_cents type="integer">1000</subtotal_in_cents>
+					<tax_in_cents type="integer">88</tax_in_cents>
+					<total_in_cents type="integer">1088</total_in_cents>
+					<line_items type="array">
+						<adjustment type="charge">
+							<description>Setup fee</description>
+							<unit_amount_in_cents type="integer">1000</unit_amount_in_cents>
+							<quantity type="integer">1</quantity>
+							<tax_in_cents type="integer">88</tax_in_cents>
+							<total_in_cents type="integer">1088</total_in_cents>
+							<currency>USD</currency>
+							<tax_type>usst</tax_type>
+							<tax_region>CA</tax_region>
+							<tax_rate type="float">0.0875</tax_rate>
+						</adjustment>
+					</line_items>
+				</charge_invoice>
+				<credit_invoices type="array">
+				</credit_invoices>
+			</invoice_collection>`)
+	})
+
+	resp, adjustment, err := client.Adjustments.Preview("1", recurly.Adjustment{
+		Description:       "Setup fee",
+		UnitAmountInCents: 1000,
+		Currency:          "USD",
+	})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected preview adjustment to return OK")
+	} else if adjustment == nil {
+		t.Fatal("expected adjustment")
+	} else if adjustment.TaxInCents != 88 {
+		t.Fatalf("unexpected tax in cents: %d", adjustment.TaxInCents)
+	} else if adjustment.TotalInCents != 1088 {
+		t.Fatalf("unexpected total in cents: %d", adjustment.TotalInCents)
+	} else if adjustment.TaxRate != 0.0875 {
+		t.Fatalf("unexpected tax rate: %v", adjustment.TaxRate)
+	}
+}
+
 func TestAdjustments_Credit(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,125 @@
+package recurly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of requests a BatchRunner sends at
+// once when NewBatch is given a concurrency of 0 or less.
+const DefaultBatchConcurrency = 4
+
+// BatchRunner runs the same subscription operation on many subscriptions,
+// such as canceling them in a nightly job. At most its concurrency of
+// requests are in flight at once, and each is sent with bulk set, as
+// described in WithBulk.
+type BatchRunner struct {
+	client      *Client
+	concurrency int
+}
+
+// BatchResult is the result of a batch operation on a single subscription.
+// Err is set if the request failed or Recurly returned an error response,
+// in which case Response holds the response, if any, and its Errors.
+type BatchResult struct {
+	UUID         string
+	Response     *Response
+	Subscription *Subscription
+	Err          error
+}
+
+// NewBatch returns a BatchRunner that sends at most concurrency requests at
+// once through client.
+func NewBatch(client *Client, concurrency int) *BatchRunner {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	return &BatchRunner{client: client, concurrency: concurrency}
+}
+
+// Cancel cancels each subscription. Results are in the same order as uuids.
+// If ctx is done, the subscriptions not yet canceled have ctx's error.
+func (b *BatchRunner) Cancel(ctx context.Context, uuids []string) []BatchResult {
+	return b.run(ctx, uuids, b.client.Subscriptions.Cancel)
+}
+
+// Reactivate reactivates each canceled subscription. Results are in the same
+// order as uuids. If ctx is done, the subscriptions not yet reactivated have
+// ctx's error.
+func (b *BatchRunner) Reactivate(ctx context.Context, uuids []string) []BatchResult {
+	return b.run(ctx, uuids, b.client.Subscriptions.Reactivate)
+}
+
+// Terminate terminates each subscription immediately with refundType, one of
+// RefundTypeFull, RefundTypePartial, or RefundTypeNone. Results are in the
+// same order as uuids. If ctx is done, the subscriptions not yet terminated
+// have ctx's error.
+func (b *BatchRunner) Terminate(ctx context.Context, uuids []string, refundType string) []BatchResult {
+	var fn func(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	switch refundType {
+	case RefundTypeFull:
+		fn = b.client.Subscriptions.TerminateWithFullRefund
+	case RefundTypePartial:
+		fn = b.client.Subscriptions.TerminateWithPartialRefund
+	case RefundTypeNone:
+		fn = b.client.Subscriptions.TerminateWithoutRefund
+	default:
+		err := fmt.Errorf("recurly: invalid refund type %q", refundType)
+		results := make([]BatchResult, len(uuids))
+		for i, uuid := range uuids {
+			results[i] = BatchResult{UUID: uuid, Err: err}
+		}
+		return results
+	}
+
+	return b.run(ctx, uuids, fn)
+}
+
+// run calls fn for each uuid using a pool of b.concurrency workers.
+func (b *BatchRunner) run(ctx context.Context, uuids []string, fn func(uuid string, opts ...RequestOption) (*Response, *Subscription, error)) []BatchResult {
+	results := make([]BatchResult, len(uuids))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < b.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = batchResult(uuids[i], fn, WithBulk(true), WithContext(ctx))
+			}
+		}()
+	}
+
+send:
+	for i := range uuids {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for j := i; j < len(uuids); j++ {
+				results[j] = BatchResult{UUID: uuids[j], Err: ctx.Err()}
+			}
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// batchResult calls fn for uuid and returns its result.
+func batchResult(uuid string, fn func(uuid string, opts ...RequestOption) (*Response, *Subscription, error), opts ...RequestOption) BatchResult {
+	result := BatchResult{UUID: uuid}
+	resp, sub, err := fn(uuid, opts...)
+	result.Response = resp
+	if err != nil {
+		result.Err = err
+	} else if resp.IsError() {
+		result.Err = fmt.Errorf("recurly: subscription %s failed with status %d", uuid, resp.StatusCode)
+	} else {
+		result.Subscription = sub
+	}
+	return result
+}
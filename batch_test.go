@@ -0,0 +1,106 @@
+package recurly_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/portofinolabs/recurly"
+)
+
+func TestBatchRunner_Cancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	mux.HandleFunc("/v2/subscriptions/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if r.Method != "PUT" {
+			t.Errorf("unexpected method: %s", r.Method)
+		} else if bulk := r.URL.Query().Get("bulk"); bulk != "true" {
+			t.Errorf("unexpected bulk: %s", bulk)
+		}
+
+		uuid := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/subscriptions/"), "/cancel")
+		switch uuid {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "expired":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="subscription.state" symbol="invalid_transition">is invalid</error></errors>`)
+		default:
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>%s</uuid><state>canceled</state></subscription>`, uuid)
+		}
+	})
+
+	uuids := []string{"a1", "missing", "b2", "expired", "c3", "d4"}
+	results := recurly.NewBatch(client, 2).Cancel(context.Background(), uuids)
+	if len(results) != len(uuids) {
+		t.Fatalf("unexpected number of results: %d", len(results))
+	} else if maxInFlight > 2 {
+		t.Fatalf("unexpected concurrency: %d", maxInFlight)
+	}
+
+	for i, result := range results {
+		if result.UUID != uuids[i] {
+			t.Fatalf("(%d): unexpected uuid: %s", i, result.UUID)
+		}
+		switch result.UUID {
+		case "missing":
+			if result.Err == nil || result.Response.StatusCode != http.StatusNotFound || result.Subscription != nil {
+				t.Fatalf("(%d): unexpected result: %#v", i, result)
+			}
+		case "expired":
+			if result.Err == nil || len(result.Response.Errors) != 1 || result.Subscription != nil {
+				t.Fatalf("(%d): unexpected result: %#v", i, result)
+			}
+		default:
+			if result.Err != nil {
+				t.Fatalf("(%d): unexpected error: %v", i, result.Err)
+			} else if result.Subscription.UUID != result.UUID || result.Subscription.State != "canceled" {
+				t.Fatalf("(%d): unexpected subscription: %#v", i, result.Subscription)
+			}
+		}
+	}
+}
+
+func TestBatchRunner_Canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected API call")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, result := range recurly.NewBatch(client, 1).Reactivate(ctx, []string{"a1", "b2"}) {
+		if result.Err == nil || result.Subscription != nil {
+			t.Fatalf("unexpected result: %#v", result)
+		}
+	}
+}
+
+func TestBatchRunner_Terminate_InvalidRefundType(t *testing.T) {
+	results := recurly.NewBatch(recurly.NewClient("test", "abc", nil), 0).Terminate(context.Background(), []string{"a1"}, "some")
+	if len(results) != 1 || results[0].UUID != "a1" || results[0].Err == nil {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+}
@@ -23,14 +23,17 @@ type Billing struct {
 	IPAddressCountry string   `xml:"ip_address_country,omitempty"`
 
 	// Credit Card Info
+	// FirstSix, LastFour, and CardType are read only and safe to display.
+	// They are never sent on create/update.
 	FirstSix int    `xml:"first_six,omitempty"`
 	LastFour string `xml:"last_four,omitempty"` // String not int so that leading zeros are present
 	CardType string `xml:"card_type,omitempty"`
-	Number   int    `xml:"number,omitempty"`
-	Month    int    `xml:"month,omitempty"`
-	Year     int    `xml:"year,omitempty"`
-	// VerificationValue is only used for create/update only. A Verification
-	// Value will never be returned on read.
+
+	// Number and VerificationValue are used for create/update only. They
+	// are never read from a response and should never be logged.
+	Number            int `xml:"number,omitempty"`
+	Month             int `xml:"month,omitempty"`
+	Year              int `xml:"year,omitempty"`
 	VerificationValue int `xml:"verification_value,omitempty"`
 
 	// Paypal
@@ -50,32 +53,49 @@ type Billing struct {
 	// Token is used for create/update only. A token will never be returned
 	// on read.
 	Token string `xml:"token_id,omitempty"`
+
+	// ThreeDSecureActionResultTokenID is used for create/update only. It is
+	// the token returned by recurly.js after completing a 3D Secure challenge.
+	ThreeDSecureActionResultTokenID string `xml:"three_d_secure_action_result_token_id,omitempty"`
+}
+
+// MarshalXML marshals billing info sending only the fields recurly allows
+// for writes. Read only fields such as FirstSix, LastFour, CardType, and
+// IPAddressCountry are cleared so that omitempty drops them.
+func (b Billing) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	// billing has Billing's fields but not its methods, so encoding it does
+	// not call MarshalXML again.
+	type billing Billing
+	v := billing(b)
+	v.XMLName = xml.Name{}
+	v.IPAddressCountry = ""
+	v.FirstSix, v.LastFour, v.CardType = 0, "", ""
+	return e.Encode(v)
 }
 
 // UnmarshalXML is a customer XML unmarshaler for billing info that supports
 // unmarshaling null fields without errors.
 func (b *Billing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var v struct {
-		XMLName          xml.Name `xml:"billing_info"`
-		FirstName        string   `xml:"first_name,omitempty"`
-		LastName         string   `xml:"last_name,omitempty"`
-		Company          string   `xml:"company,omitempty"`
-		Address          string   `xml:"address1,omitempty"`
-		Address2         string   `xml:"address2,omitempty"`
-		City             string   `xml:"city,omitempty"`
-		State            string   `xml:"state,omitempty"`
-		Zip              string   `xml:"zip,omitempty"`
-		Country          string   `xml:"country,omitempty"`
-		Phone            string   `xml:"phone,omitempty"`
-		VATNumber        string   `xml:"vat_number,omitempty"`
-		IPAddress        net.IP   `xml:"ip_address,omitempty"`
-		IPAddressCountry string   `xml:"ip_address_country,omitempty"`
+		XMLName          xml.Name  `xml:"billing_info"`
+		FirstName        string    `xml:"first_name,omitempty"`
+		LastName         string    `xml:"last_name,omitempty"`
+		Company          string    `xml:"company,omitempty"`
+		Address          string    `xml:"address1,omitempty"`
+		Address2         string    `xml:"address2,omitempty"`
+		City             string    `xml:"city,omitempty"`
+		State            string    `xml:"state,omitempty"`
+		Zip              string    `xml:"zip,omitempty"`
+		Country          string    `xml:"country,omitempty"`
+		Phone            string    `xml:"phone,omitempty"`
+		VATNumber        string    `xml:"vat_number,omitempty"`
+		IPAddress        ipAddress `xml:"ip_address,omitempty"`
+		IPAddressCountry string    `xml:"ip_address_country,omitempty"`
 
 		// Credit Card Info
 		FirstSix NullInt `xml:"first_six,omitempty"`
 		LastFour string  `xml:"last_four,omitempty"`
 		CardType string  `xml:"card_type,omitempty"`
-		Number   int     `xml:"number,omitempty"`
 		Month    NullInt `xml:"month,omitempty"`
 		Year     NullInt `xml:"year,omitempty"`
 
@@ -109,13 +129,12 @@ func (b *Billing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		Country:          v.Country,
 		Phone:            v.Phone,
 		VATNumber:        v.VATNumber,
-		IPAddress:        v.IPAddress,
+		IPAddress:        net.IP(v.IPAddress),
 		IPAddressCountry: v.IPAddressCountry,
 
 		FirstSix: v.FirstSix.Int,
 		LastFour: v.LastFour,
 		CardType: v.CardType,
-		Number:   v.Number,
 		Month:    v.Month.Int,
 		Year:     v.Year.Int,
 
@@ -140,3 +159,13 @@ func (b Billing) Type() string {
 
 	return ""
 }
+
+// MaskedNumber returns the card number with all but the last four digits
+// masked, e.g. ****-****-****-1234, for display. It returns an empty string
+// if the billing info has no card.
+func (b Billing) MaskedNumber() string {
+	if b.LastFour == "" {
+		return ""
+	}
+	return "****-****-****-" + b.LastFour
+}
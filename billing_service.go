@@ -93,6 +93,8 @@ func (s *billingImpl) Update(accountCode string, b Billing) (*Response, *Billing
 		RoutingNumber:     b.RoutingNumber,
 		AccountNumber:     b.AccountNumber,
 		AccountType:       b.AccountType,
+
+		ThreeDSecureActionResultTokenID: b.ThreeDSecureActionResultTokenID,
 	}
 
 	action := fmt.Sprintf("accounts/%s/billing_info", accountCode)
@@ -139,3 +141,27 @@ func (s *billingImpl) Clear(accountCode string) (*Response, error) {
 
 	return s.client.do(req, nil)
 }
+
+// Verify runs a zero-dollar (or $1 for gateways that don't support zero
+// dollar authorizations) verification against the account's billing info.
+// The verification transaction is returned so CVV and AVS results can be
+// checked. If the card is declined, the transaction is returned with its
+// TransactionError set.
+// https://dev.recurly.com/docs/verify-billing-info
+func (s *billingImpl) Verify(accountCode string) (*Response, *Transaction, error) {
+	action := fmt.Sprintf("accounts/%s/billing_info/verify", accountCode)
+	req, err := s.client.newRequest("POST", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst Transaction
+	resp, err := s.client.do(req, &dst)
+	if err != nil {
+		return resp, nil, err
+	} else if resp.IsError() {
+		return resp, resp.transaction, nil
+	}
+
+	return resp, &dst, nil
+}
@@ -35,6 +35,8 @@ func TestBilling_Encoding(t *testing.T) {
 		{v: recurly.Billing{IPAddress: net.ParseIP("127.0.0.1")}, expected: "<billing_info><ip_address>127.0.0.1</ip_address></billing_info>"},
 		{v: recurly.Billing{Number: 4111111111111111, Month: 5, Year: 2020, VerificationValue: 111}, expected: "<billing_info><number>4111111111111111</number><month>5</month><year>2020</year><verification_value>111</verification_value></billing_info>"},
 		{v: recurly.Billing{RoutingNumber: "065400137", AccountNumber: "0123456789", AccountType: "checking"}, expected: "<billing_info><routing_number>065400137</routing_number><account_number>0123456789</account_number><account_type>checking</account_type></billing_info>"},
+		// Read only fields should never be sent.
+		{v: recurly.Billing{FirstSix: 411111, LastFour: "1111", CardType: "Visa", IPAddressCountry: "US", Month: 5, Year: 2020}, expected: "<billing_info><month>5</month><year>2020</year></billing_info>"},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +74,28 @@ func TestBilling_Type(t *testing.T) {
 	}
 }
 
+func TestBilling_MaskedNumber(t *testing.T) {
+	b := recurly.Billing{FirstSix: 411111, LastFour: "0123"}
+	if b.MaskedNumber() != "****-****-****-0123" {
+		t.Fatalf("unexpected masked number: %s", b.MaskedNumber())
+	} else if (recurly.Billing{}).MaskedNumber() != "" {
+		t.Fatalf("unexpected masked number: %s", (recurly.Billing{}).MaskedNumber())
+	}
+}
+
+func TestBilling_Decode_IgnoresNumber(t *testing.T) {
+	var b recurly.Billing
+	if err := xml.Unmarshal([]byte(`<billing_info><number>4111111111111111</number><verification_value>123</verification_value><three_d_secure_action_result_token_id>8a5Gs9uOHBDZ3OSpN3Ow</three_d_secure_action_result_token_id><last_four>1111</last_four></billing_info>`), &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if b.Number != 0 || b.VerificationValue != 0 {
+		t.Fatalf("unexpected sensitive fields: %d %d", b.Number, b.VerificationValue)
+	} else if b.ThreeDSecureActionResultTokenID != "" {
+		t.Fatalf("unexpected 3D Secure token: %s", b.ThreeDSecureActionResultTokenID)
+	} else if b.LastFour != "1111" {
+		t.Fatalf("unexpected last four: %s", b.LastFour)
+	}
+}
+
 func TestBilling_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -520,3 +544,104 @@ func TestBilling_Clear(t *testing.T) {
 		t.Fatal("expected deleting billing_info to return OK")
 	}
 }
+
+func TestBilling_Verify(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/accounts/1/billing_info/verify", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction href="https://your-subdomain.recurly.com/v2/transactions/3d1c6aa5ba3c4d8c97db0c6fa6b4e1d0" type="credit_card">
+			  <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			  <uuid>3d1c6aa5ba3c4d8c97db0c6fa6b4e1d0</uuid>
+			  <action>verify</action>
+			  <amount_in_cents type="integer">0</amount_in_cents>
+			  <currency>USD</currency>
+			  <status>success</status>
+			  <payment_method>credit_card</payment_method>
+			  <cvv_result code="M">Match</cvv_result>
+			  <avs_result code="D">Street address and postal code match.</avs_result>
+			</transaction>`)
+	})
+
+	resp, tx, err := client.Billing.Verify("1")
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected verify billing to return OK")
+	} else if tx.Action != "verify" || tx.Status != recurly.TransactionStatusSuccess {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	} else if !tx.CVVResult.IsMatch() {
+		t.Fatalf("unexpected cvv result: %#v", tx.CVVResult)
+	} else if tx.AVSResult.Code != "D" {
+		t.Fatalf("unexpected avs result: %#v", tx.AVSResult)
+	}
+}
+
+func TestBilling_Verify_Declined(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/billing_info/verify", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(422)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+			  <transaction_error>
+			    <error_code>declined</error_code>
+			    <error_category>soft</error_category>
+			    <merchant_message>The customer's bank has declined their card.</merchant_message>
+			    <customer_message>The transaction was declined. Please use a different card or contact your bank.</customer_message>
+			    <gateway_error_code nil="nil"></gateway_error_code>
+			  </transaction_error>
+			  <error field="transaction.account.base" symbol="declined">The transaction was declined. Please use a different card or contact your bank.</error>
+			  <transaction href="https://your-subdomain.recurly.com/v2/transactions/3d1c6aa5ba3c4d8c97db0c6fa6b4e1d0" type="credit_card">
+			    <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			    <uuid>3d1c6aa5ba3c4d8c97db0c6fa6b4e1d0</uuid>
+			    <action>verify</action>
+			    <amount_in_cents type="integer">0</amount_in_cents>
+			    <currency>USD</currency>
+			    <status>declined</status>
+			    <payment_method>credit_card</payment_method>
+			    <transaction_error>
+			      <error_code>declined</error_code>
+			      <error_category>soft</error_category>
+			      <merchant_message>The customer's bank has declined their card.</merchant_message>
+			      <customer_message>The transaction was declined. Please use a different card or contact your bank.</customer_message>
+			      <gateway_error_code nil="nil"></gateway_error_code>
+			    </transaction_error>
+			    <details>
+			    </details>
+			  </transaction>
+			</errors>`)
+	})
+
+	resp, tx, err := client.Billing.Verify("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 422 {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if tx == nil {
+		t.Fatal("expected transaction")
+	} else if tx.Status != "declined" {
+		t.Fatalf("unexpected status: %s", tx.Status)
+	} else if !reflect.DeepEqual(tx.TransactionError, &recurly.TransactionError{
+		XMLName:         xml.Name{Local: "transaction_error"},
+		ErrorCode:       "declined",
+		ErrorCategory:   "soft",
+		MerchantMessage: "The customer's bank has declined their card.",
+		CustomerMessage: "The transaction was declined. Please use a different card or contact your bank.",
+	}) {
+		t.Fatalf("unexpected transaction error: %#v", tx.TransactionError)
+	}
+}
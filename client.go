@@ -2,19 +2,31 @@ package recurly
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"runtime"
 	"strings"
+	"time"
 )
 
 const defaultBaseURL = "https://%s.recurly.com/"
 
+// Version is the version of this library reported in the default User-Agent.
+const Version = "2017-09-09"
+
 // Client manages communication with the Recurly API.
+// A Client is safe for concurrent use by multiple goroutines once it has been
+// configured. Its fields, including BaseURL and the services, should not be
+// modified while requests are in flight.
 type Client struct {
 	// client is the HTTP Client used to communicate with the API.
 	client *http.Client
@@ -25,16 +37,63 @@ type Client struct {
 	// apiKey is your account's API key used for authentication.
 	apiKey string
 
+	// requestHooks are called after each request completes.
+	requestHooks []func(RequestInfo)
+
 	// BaseURL is the base url for api requests.
 	BaseURL string
 
+	// DefaultCurrency, if set, is used as the currency of new subscriptions,
+	// adjustments, and transactions that don't set one.
+	DefaultCurrency string
+
+	// UserAgent is sent as the User-Agent header of every request. It
+	// defaults to DefaultUserAgent and may be overridden for a single
+	// request with WithUserAgent.
+	UserAgent string
+
+	// XMLDeclaration prepends the standard XML declaration to request
+	// bodies. Recurly doesn't require it, but some proxies and firewalls
+	// reject XML bodies without one. It is off by default and may be changed
+	// for a single request with WithXMLDeclaration.
+	XMLDeclaration bool
+
+	// Compression asks Recurly to gzip response bodies, which are
+	// decompressed before they are decoded. It reduces bandwidth for large
+	// list and invoice responses. It is off by default and may be changed
+	// for a single request with WithCompression.
+	Compression bool
+
+	// StrictCurrency rejects new subscriptions whose currency is not one
+	// IsKnownCurrency reports. By default only malformed currency codes are
+	// rejected, so currencies Recurly adds later keep working.
+	StrictCurrency bool
+
+	// KeepUnknownXML populates the Extra field of types that support it
+	// with response elements the library does not model yet. It applies to
+	// the subscriptions and transactions a method returns, including list
+	// items and change previews, but not to those nested in other resources.
+	KeepUnknownXML bool
+
+	// NotFoundError makes requests return ErrNotFound when Recurly responds
+	// with 404 Not Found. By default the error is nil and lookups such as
+	// Subscriptions.Get return a nil resource, so callers must check for it.
+	NotFoundError bool
+
+	// RetryPolicy, if set, retries requests that were rate limited or
+	// rejected while Recurly was unavailable. Requests are not retried by
+	// default.
+	RetryPolicy *RetryPolicy
+
 	// Services used for talking with different parts of the Recurly API
 	Accounts      AccountsService
 	Adjustments   AdjustmentsService
 	Billing       BillingService
 	Coupons       CouponsService
+	Exports       ExportsService
 	Redemptions   RedemptionsService
 	Invoices      InvoicesService
+	MeasuredUnits MeasuredUnitsService
 	Plans         PlansService
 	AddOns        AddOnsService
 	Subscriptions SubscriptionsService
@@ -53,14 +112,17 @@ func NewClient(subDomain, apiKey string, httpClient *http.Client) *Client {
 		subDomain: subDomain,
 		apiKey:    base64.StdEncoding.EncodeToString([]byte(apiKey)),
 		BaseURL:   fmt.Sprintf(defaultBaseURL, subDomain),
+		UserAgent: DefaultUserAgent(),
 	}
 
 	client.Accounts = &accountsImpl{client: client}
 	client.Adjustments = &adjustmentsImpl{client: client}
 	client.Billing = &billingImpl{client: client}
 	client.Coupons = &couponsImpl{client: client}
+	client.Exports = &exportsImpl{client: client}
 	client.Redemptions = &redemptionsImpl{client: client}
 	client.Invoices = &invoicesImpl{client: client}
+	client.MeasuredUnits = &measuredUnitsImpl{client: client}
 	client.Plans = &plansImpl{client: client}
 	client.AddOns = &addOnsImpl{client: client}
 	client.Subscriptions = &subscriptionsImpl{client: client}
@@ -69,6 +131,70 @@ func NewClient(subDomain, apiKey string, httpClient *http.Client) *Client {
 	return client
 }
 
+// Call sends a request to an endpoint this library doesn't model yet and
+// decodes the response into into, which may be nil or an io.Writer as with
+// the services. path is relative to the API root, such as
+// "accounts/1/custom_endpoint"; a leading "/" or "/v2/" is ignored. body, if
+// not nil, is encoded with EncodeBody. Errors are returned in the same way as
+// the services, so check resp.IsError and resp.Errors.
+//
+// Call is intended for advanced use and is not covered by the library's
+// compatibility guarantees: its signature may change, and endpoints should
+// move to a dedicated service method once one exists.
+func (c *Client) Call(ctx context.Context, method string, path string, body interface{}, into interface{}) (*Response, error) {
+	path = strings.TrimPrefix(path, "/")
+	path = strings.TrimPrefix(path, "v2/")
+	req, err := c.newRequest(method, path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req, into, WithContext(ctx))
+}
+
+// Ping makes a minimal authenticated request to check that the client's API
+// key and subdomain are valid, such as on startup. It returns ErrUnauthorized
+// if Recurly rejects the API key, and wraps the underlying error if Recurly
+// could not be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := c.newRequest("GET", "accounts", Params{"per_page": 1}, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, nil, WithContext(ctx))
+	if resp == nil {
+		return fmt.Errorf("recurly: ping failed: %w", err)
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	} else if resp.IsError() {
+		return fmt.Errorf("recurly: ping failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// currency returns currency, or the client's default currency if it is empty.
+func (c *Client) currency(currency string) string {
+	if currency == "" {
+		return c.DefaultCurrency
+	}
+	return currency
+}
+
+// DefaultUserAgent returns the User-Agent used when Client.UserAgent is not
+// changed. It identifies the library version along with the Go version and
+// platform, which helps Recurly support when debugging an integration.
+func DefaultUserAgent() string {
+	return fmt.Sprintf(
+		"portofinolabs-recurly-go/%s; Go (%s) [%s-%s]",
+		Version,
+		runtime.Version(),
+		runtime.GOARCH,
+		runtime.GOOS,
+	)
+}
+
 // newRequest creates an authenticated API request that is ready to send.
 func (c *Client) newRequest(method string, action string, params Params, body interface{}) (*http.Request, error) {
 	method = strings.ToUpper(method)
@@ -87,10 +213,14 @@ func (c *Client) newRequest(method string, action string, params Params, body in
 	// Request body
 	var buf bytes.Buffer
 	if body != nil {
-		err := xml.NewEncoder(&buf).Encode(body)
+		b, err := EncodeBody(body)
 		if err != nil {
 			return nil, err
 		}
+		if c.XMLDeclaration {
+			buf.WriteString(xml.Header)
+		}
+		buf.Write(b)
 	}
 
 	req, err := http.NewRequest(method, endpoint, &buf)
@@ -101,12 +231,9 @@ func (c *Client) newRequest(method string, action string, params Params, body in
 	// Add User-Agent tracking for Recurly statistics and potentially
 	// identifying bugs or updates needed in the library.
 	// https://github.com/blacklightcms/recurly/issues/41
-	req.Header.Set("User-Agent", fmt.Sprintf(
-		"Blacklight/2017-09-09; Go (%s) [%s-%s]",
-		runtime.Version(),
-		runtime.GOARCH,
-		runtime.GOOS,
-	))
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", c.apiKey))
 	req.Header.Set("Accept", "application/xml")
 	req.Header.Set("X-Api-Version", "2.5")
@@ -117,21 +244,70 @@ func (c *Client) newRequest(method string, action string, params Params, body in
 	return req, err
 }
 
+// EncodeBody returns the XML element the client sends as the request body for
+// v, such as a NewSubscription or UpdateSubscription. It is the canonical way
+// to preview a request body without sending it, including the effect of
+// omitempty and custom marshalers on zero values. It never includes the XML
+// declaration, which the client prepends only when Client.XMLDeclaration or
+// WithXMLDeclaration enables it.
+func EncodeBody(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // do takes a prepared API request and makes the API call to Recurly.
 // It will decode the XML into a destination struct you provide as well
 // as parse any validation errors that may have occurred.
 // It returns a Response object that provides a wrapper around http.Response
-// with some convenience methods.
-func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
+// with some convenience methods. Any opts are applied to req before it is
+// sent.
+func (c *Client) do(req *http.Request, v interface{}, opts ...RequestOption) (*Response, error) {
+	if c.Compression {
+		WithCompression(true)(req)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	reqBody, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
 	req.Close = true
-	resp, err := c.client.Do(req)
+	start := time.Now()
+	resp, retries, err := c.send(req)
+	if len(c.requestHooks) > 0 {
+		defer c.requestComplete(req, reqBody, resp, start, retries)
+	}
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if err := decompress(resp); err != nil {
+		return nil, err
+	}
+
+	response := &Response{Response: resp, Attempts: retries + 1}
+	if response.IsError() {
+		if err := newHTMLResponseError(resp); err != nil {
+			return response, err
+		}
+	}
 
-	response := &Response{Response: resp}
-	decoder := xml.NewDecoder(resp.Body)
+	var body bytes.Buffer
+	var r io.Reader = io.TeeReader(resp.Body, &body)
+	fn, streaming := v.(decodeFunc)
+	if streaming && !response.IsError() {
+		r = resp.Body // Don't hold the whole body in memory.
+	}
+	decoder := xml.NewDecoder(r)
+	if k, ok := v.(extraKeeper); ok && c.KeepUnknownXML {
+		k.keepExtra()
+	}
 	if response.IsError() { // Parse validation errors
 		if response.StatusCode == http.StatusUnprocessableEntity {
 			var ve struct {
@@ -145,7 +321,7 @@ func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
 			}
 
 			if err = decoder.Decode(&ve); err != nil {
-				return response, err
+				return response, newDecodeError(err, &ve, body.Bytes(), decoder.InputOffset())
 			}
 
 			if ve.Errors == nil {
@@ -171,9 +347,9 @@ func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
 				Description string   `xml:"description"`
 			}
 			if err = decoder.Decode(&ve); err == io.EOF {
-				return response, nil
+				return response, c.notFound(response)
 			} else if err != nil {
-				return response, err
+				return response, newDecodeError(err, &ve, body.Bytes(), decoder.InputOffset())
 			}
 
 			response.Errors = []Error{
@@ -184,16 +360,250 @@ func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
 			}
 		}
 
-		return response, nil
+		return response, c.notFound(response)
 	}
 
-	if v != nil {
+	if streaming {
+		err = fn(decoder)
+	} else if v != nil {
 		if w, ok := v.(io.Writer); ok {
 			io.Copy(w, resp.Body)
 		} else {
-			err = decoder.Decode(&v)
+			if err = decoder.Decode(&v); err != nil {
+				err = newDecodeError(err, v, body.Bytes(), decoder.InputOffset())
+			}
 		}
 	}
 
 	return response, err
 }
+
+// ErrNotFound is returned for 404 Not Found responses when
+// Client.NotFoundError is set.
+var ErrNotFound = errors.New("recurly: not found")
+
+// ErrUnauthorized is returned by Client.Ping when Recurly rejects the
+// client's API key.
+var ErrUnauthorized = errors.New("recurly: unauthorized, check the API key and subdomain")
+
+// notFound returns ErrNotFound if resp is a 404 and c.NotFoundError is set.
+func (c *Client) notFound(resp *Response) error {
+	if c.NotFoundError && resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// requestBody returns the body of req. If the body cannot already be re-read
+// through req.GetBody, such as when a RequestOption replaced it, it is read
+// into memory and req.GetBody is set so that retries and redirects send an
+// identical body.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(b))
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+	return b, nil
+}
+
+// decodeFunc can be passed to do in place of a value to decode a successful
+// response incrementally. It is called with a decoder reading directly from
+// the response body, which is not buffered for DecodeError.
+type decodeFunc func(d *xml.Decoder) error
+
+// doList sends req and decodes an array response, such as
+// <accounts type="array"><account>...</account></accounts>, returning the
+// items. wrapper is the name of the array element and element is the name,
+// or a path such as "export_date>date", of each item within it. The response
+// is decoded by do like any other, so decode errors and KeepUnknownXML behave
+// the same.
+func doList[T any](c *Client, req *http.Request, wrapper string, element string, opts ...RequestOption) (*Response, []T, error) {
+	l := &list[T]{wrapper: wrapper, path: strings.Split(element, ">")}
+	resp, err := c.do(req, l, opts...)
+
+	return resp, l.items, err
+}
+
+// list is the destination doList decodes an array response into.
+type list[T any] struct {
+	wrapper string
+	path    []string
+	items   []T
+
+	// keep is set by keepExtra so that each item keeps unknown elements.
+	keep bool
+}
+
+// keepExtra makes each item that supports it keep unknown elements.
+func (l *list[T]) keepExtra() {
+	l.keep = true
+}
+
+// UnmarshalXML checks that start is the wrapper element and decodes each item
+// within it.
+func (l *list[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Local != l.wrapper {
+		return fmt.Errorf("expected element type <%s> but have <%s>", l.wrapper, start.Name.Local)
+	}
+	return l.decodeItems(d, l.path)
+}
+
+// decodeItems reads the children of the current element until it ends. Those
+// named path[0] are decoded into items, or searched for the rest of path if
+// it has more than one name. Other children are skipped.
+func (l *list[T]) decodeItems(d *xml.Decoder, path []string) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != path[0] {
+				err = d.Skip()
+			} else if len(path) > 1 {
+				err = l.decodeItems(d, path[1:])
+			} else {
+				var v T
+				if k, ok := interface{}(&v).(extraKeeper); ok && l.keep {
+					k.keepExtra()
+				}
+				if err = d.DecodeElement(&v, &t); err == nil {
+					l.items = append(l.items, v)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// typeName names the list in decode errors by its items type, e.g.
+// []recurly.Account.
+func (l *list[T]) typeName() string {
+	return reflect.TypeOf(l.items).String()
+}
+
+// streamList returns a decodeFunc that decodes each element named element in
+// an array response and sends it on out, one at a time. It stops with ctx's
+// error if ctx is done before an item is received.
+func streamList[T any](ctx context.Context, element string, out chan<- T) decodeFunc {
+	return func(d *xml.Decoder) error {
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			start, ok := tok.(xml.StartElement)
+			if !ok || start.Name.Local != element {
+				continue
+			}
+
+			var v T
+			if err := d.DecodeElement(&v, &start); err != nil {
+				return err
+			}
+
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// decompress replaces the body of resp with a reader that decompresses it
+// if Recurly gzipped the response. Go's transport only does this itself when
+// it set the Accept-Encoding header, not when it was set by Client.Compression
+// or WithCompression.
+func decompress(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err == io.EOF { // Empty body, such as a 204 response.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{gz, resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// send sends req, retrying it according to c.RetryPolicy. It returns the
+// final response and the number of retries made. If every attempt failed
+// without a response, the returned error joins the error of each attempt.
+func (c *Client) send(req *http.Request) (*http.Response, int, error) {
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		resp, err := c.client.Do(req)
+		rewindable := req.Body == nil || req.GetBody != nil
+		if err != nil {
+			errs = append(errs, err)
+			if !rewindable || !c.RetryPolicy.shouldRetryError(attempt, req) {
+				if len(errs) == 1 {
+					return nil, attempt - 1, err
+				}
+				return nil, attempt - 1, attemptErrors(errs)
+			}
+		} else if !rewindable || !c.RetryPolicy.shouldRetry(attempt, resp) {
+			return resp, attempt - 1, nil
+		}
+
+		delay := c.RetryPolicy.delay(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, attempt - 1, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, attempt - 1, err
+			}
+		}
+	}
+}
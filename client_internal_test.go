@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestClient_NewRequest tests the internals of recurly.client.
@@ -172,3 +173,103 @@ func TestClient_Error(t *testing.T) {
 		t.Fatalf("unexpected error: %v", resp.Errors)
 	}
 }
+
+func TestClient_NormalizeRoute(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/v2/accounts", expected: "/v2/accounts"},
+		{path: "/v2/accounts/abcd@example.com", expected: "/v2/accounts/{account_code}"},
+		{path: "/v2/accounts/1/billing_info", expected: "/v2/accounts/{account_code}/billing_info"},
+		{path: "/v2/accounts/1/invoices/preview", expected: "/v2/accounts/{account_code}/invoices/preview"},
+		{path: "/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", expected: "/v2/subscriptions/{uuid}"},
+		{path: "/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel", expected: "/v2/subscriptions/{uuid}/cancel"},
+		{path: "/v2/subscriptions/preview", expected: "/v2/subscriptions/preview"},
+		{path: "/v2/invoices/1402/refund", expected: "/v2/invoices/{invoice_number}/refund"},
+		{path: "/v2/plans/gold/add_ons/ipaddresses", expected: "/v2/plans/{plan_code}/add_ons/{add_on_code}"},
+		{path: "/v2/export_dates/2016-08-01/export_files/churned_subscriptions_v2_expires.csv.gz", expected: "/v2/export_dates/{date}/export_files/{file_name}"},
+		{path: "/proxy/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", expected: "/v2/transactions/{uuid}"},
+	}
+
+	for i, tt := range tests {
+		if given := normalizeRoute(tt.path); given != tt.expected {
+			t.Fatalf("(%d) unexpected route: %s", i, given)
+		}
+	}
+}
+
+func TestRetryPolicy_FullJitterBackoff(t *testing.T) {
+	backoff := FullJitterBackoff(100*time.Millisecond, time.Second)
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: 100 * time.Millisecond},
+		{attempt: 1, max: 100 * time.Millisecond},
+		{attempt: 2, max: 200 * time.Millisecond},
+		{attempt: 3, max: 400 * time.Millisecond},
+		{attempt: 4, max: 800 * time.Millisecond},
+		{attempt: 5, max: time.Second},
+		{attempt: 100, max: time.Second},
+	}
+
+	for i, tt := range tests {
+		for n := 0; n < 1000; n++ {
+			if d := backoff(tt.attempt, nil); d < 0 || d > tt.max {
+				t.Fatalf("(%d) unexpected backoff: %v", i, d)
+			}
+		}
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	now := time.Now()
+	policy := &RetryPolicy{
+		MaxRetries: 3,
+		Backoff: func(attempt int, resp *http.Response) time.Duration {
+			return 2 * time.Second
+		},
+	}
+
+	tests := []struct {
+		retryAfter string
+		expected   time.Duration
+	}{
+		{retryAfter: "", expected: 2 * time.Second},
+		{retryAfter: "1", expected: 2 * time.Second},
+		{retryAfter: "5", expected: 5 * time.Second},
+		{retryAfter: "-5", expected: 2 * time.Second},
+		{retryAfter: "soon", expected: 2 * time.Second},
+		{retryAfter: "86400", expected: DefaultRetryMaxDelay},
+		{retryAfter: "9223372036854775807", expected: DefaultRetryMaxDelay},
+	}
+
+	for i, tt := range tests {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		if tt.retryAfter != "" {
+			resp.Header.Set("Retry-After", tt.retryAfter)
+		}
+		if d := policy.delay(1, resp); d != tt.expected {
+			t.Fatalf("(%d) unexpected delay: %v", i, d)
+		}
+	}
+
+	// Retry-After may also be an HTTP date.
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", now.Add(10*time.Second).UTC().Format(http.TimeFormat))
+	if d := retryAfter(resp, now, time.Minute); d <= 8*time.Second || d > 10*time.Second {
+		t.Fatalf("unexpected retry after: %v", d)
+	}
+
+	// Oversized headers are capped at MaxRetryAfter.
+	policy.MaxRetryAfter = 10 * time.Second
+	resp.Header.Set("Retry-After", "3600")
+	if d := policy.delay(1, resp); d != 10*time.Second {
+		t.Fatalf("unexpected capped delay: %v", d)
+	}
+	resp.Header.Set("Retry-After", now.Add(time.Hour).UTC().Format(http.TimeFormat))
+	if d := policy.delay(1, resp); d != 10*time.Second {
+		t.Fatalf("unexpected capped date delay: %v", d)
+	}
+}
@@ -1,8 +1,24 @@
 package recurly_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
 
 	"github.com/portofinolabs/recurly"
 )
@@ -33,3 +49,785 @@ func setup() {
 func teardown() {
 	server.Close()
 }
+
+// TestClient_Concurrent makes concurrent requests with a shared client.
+// Run with -race to detect data races.
+func TestClient_Concurrent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+			if err != nil {
+				errs <- err
+			} else if resp.IsError() {
+				errs <- fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			} else if sub.UUID != "44f83d7cba354d5b84812419f923ea96" {
+				errs <- fmt.Errorf("unexpected uuid: %s", sub.UUID)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_KeepUnknownXML(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><future_field>future value</future_field></subscription>`)
+	})
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><transaction><uuid>a13acd8fe4294916b79aec87b7ea441f</uuid><future_field>future value</future_field></transaction>`)
+	})
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscriptions type="array"><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><future_field>future value</future_field></subscription></subscriptions>`)
+	})
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><future_field>future value</future_field><invoice_collection><charge_invoice><total_in_cents type="integer">1000</total_in_cents></charge_invoice></invoice_collection></subscription>`)
+	})
+
+	// Unknown elements are dropped by default.
+	if _, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub.Extra != nil {
+		t.Fatalf("unexpected extra: %#v", sub.Extra)
+	}
+
+	client.KeepUnknownXML = true
+	expected := map[string]string{"future_field": "future value"}
+	if _, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(sub.Extra, expected) {
+		t.Fatalf("unexpected subscription extra: %#v", sub.Extra)
+	}
+
+	if _, tx, err := client.Transactions.Get("a13acd8fe4294916b79aec87b7ea441f"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(tx.Extra, expected) {
+		t.Fatalf("unexpected transaction extra: %#v", tx.Extra)
+	}
+
+	// Lists decode each item with the response's decoder, so items keep
+	// unknown elements too.
+	if _, subs, err := client.Subscriptions.List(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(subs) != 1 || !reflect.DeepEqual(subs[0].Extra, expected) {
+		t.Fatalf("unexpected list extra: %#v", subs)
+	}
+
+	// Change previews decode the subscription within the preview, which
+	// must keep unknown elements too.
+	if _, sub, err := client.Subscriptions.PreviewChange("44f83d7cba354d5b84812419f923ea96", recurly.UpdateSubscription{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(sub.Extra, expected) {
+		t.Fatalf("unexpected preview extra: %#v", sub.Extra)
+	}
+
+	// Decoding directly keeps unknown elements if Extra is non-nil.
+	sub := recurly.Subscription{Extra: map[string]string{}}
+	if err := xml.Unmarshal([]byte(`<subscription><future_field>future value</future_field></subscription>`), &sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(sub.Extra, expected) {
+		t.Fatalf("unexpected decoded extra: %#v", sub.Extra)
+	}
+}
+
+func TestClient_OnRequestComplete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	var infos []recurly.RequestInfo
+	client.OnRequestComplete(func(info recurly.RequestInfo) {
+		infos = append(infos, info)
+	})
+
+	if _, _, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(infos) != 1 {
+		t.Fatalf("unexpected number of calls: %d", len(infos))
+	} else if infos[0].Method != "GET" {
+		t.Fatalf("unexpected method: %s", infos[0].Method)
+	} else if infos[0].Route != "/v2/subscriptions/{uuid}" {
+		t.Fatalf("unexpected route: %s", infos[0].Route)
+	} else if infos[0].StatusCode != 200 {
+		t.Fatalf("unexpected status code: %d", infos[0].StatusCode)
+	} else if infos[0].Duration <= 0 {
+		t.Fatalf("unexpected duration: %v", infos[0].Duration)
+	} else if infos[0].Retries != 0 {
+		t.Fatalf("unexpected retries: %d", infos[0].Retries)
+	}
+}
+
+func TestClient_Call(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/entitlements", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.Header.Get("Authorization") == "" {
+			t.Fatal("expected request to be authenticated")
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected := "<entitlement><code>seats</code></entitlement>"; given.String() != expected {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><entitlement><code>seats</code><granted type="integer">5</granted></entitlement>`)
+	})
+
+	type entitlement struct {
+		XMLName xml.Name `xml:"entitlement"`
+		Code    string   `xml:"code"`
+		Granted int      `xml:"granted,omitempty"`
+	}
+
+	var e entitlement
+	resp, err := client.Call(context.Background(), "POST", "/v2/accounts/1/entitlements", entitlement{Code: "seats"}, &e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected call to return OK")
+	} else if e.Code != "seats" || e.Granted != 5 {
+		t.Fatalf("unexpected entitlement: %v", e)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	setup()
+	defer teardown()
+
+	status := http.StatusOK
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if pp := r.URL.Query().Get("per_page"); pp != "1" {
+			t.Fatalf("unexpected per_page: %s", pp)
+		} else if r.Header.Get("Authorization") == "" {
+			t.Fatal("expected authorization header")
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><accounts type="array"></accounts>`)
+		} else {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>unauthorized</symbol><description>Please provide a valid API key.</description></error>`)
+		}
+	})
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	status = http.StatusUnauthorized
+	if err := client.Ping(context.Background()); err != recurly.ErrUnauthorized {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := client.Ping(context.Background()); err == nil || err == recurly.ErrUnauthorized {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Network errors are wrapped.
+	server.Close()
+	if err := client.Ping(context.Background()); err == nil || err == recurly.ErrUnauthorized {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !strings.HasPrefix(err.Error(), "recurly: ping failed: ") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if errors.Unwrap(err) == nil {
+		t.Fatalf("expected wrapped error: %v", err)
+	}
+}
+
+func TestClient_UserAgent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var given string
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		given = r.Header.Get("User-Agent")
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	if _, _, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if given != recurly.DefaultUserAgent() {
+		t.Fatalf("unexpected User-Agent: %s", given)
+	} else if !strings.HasPrefix(given, "portofinolabs-recurly-go/"+recurly.Version) {
+		t.Fatalf("unexpected User-Agent: %s", given)
+	}
+
+	client.UserAgent = "acme-billing/1.2"
+	if _, _, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if given != "acme-billing/1.2" {
+		t.Fatalf("unexpected User-Agent: %s", given)
+	}
+
+	if _, _, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96", recurly.WithUserAgent("acme-billing/1.3")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if given != "acme-billing/1.3" {
+		t.Fatalf("unexpected User-Agent: %s", given)
+	}
+}
+
+func TestClient_DefaultCurrency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var given []string
+	record := func(root string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var body bytes.Buffer
+			body.ReadFrom(r.Body)
+			start := strings.Index(body.String(), "<currency>")
+			end := strings.Index(body.String(), "</currency>")
+			if start < 0 || end < start {
+				t.Fatalf("expected currency to be sent: %s", body.String())
+			}
+			given = append(given, body.String()[start+len("<currency>"):end])
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><%s></%s>`, root, root)
+		}
+	}
+	mux.HandleFunc("/v2/subscriptions", record("subscription"))
+	mux.HandleFunc("/v2/accounts/1/adjustments", record("adjustment"))
+	mux.HandleFunc("/v2/transactions", record("transaction"))
+
+	client.DefaultCurrency = "EUR"
+	for _, currency := range []string{"", "USD"} {
+		if _, _, err := client.Subscriptions.Create(recurly.NewSubscription{PlanCode: "gold", Currency: currency}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if _, _, err := client.Adjustments.Create("1", recurly.Adjustment{UnitAmountInCents: 100, Currency: currency}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if _, _, err := client.Transactions.Create(recurly.Transaction{AmountInCents: 100, Currency: currency}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if !reflect.DeepEqual(given, []string{"EUR", "EUR", "EUR", "USD", "USD", "USD"}) {
+		t.Fatalf("unexpected currencies: %v", given)
+	}
+}
+
+func TestClient_XMLDeclaration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var given []string
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+		body.ReadFrom(r.Body)
+		if r.ContentLength != int64(body.Len()) {
+			t.Fatalf("unexpected content length: %d", r.ContentLength)
+		}
+		given = append(given, body.String())
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	sub := recurly.NewSubscription{PlanCode: "gold", Currency: "USD"}
+	const encoded = "<subscription><plan_code>gold</plan_code><account></account><currency>USD</currency></subscription>"
+
+	client.Subscriptions.Create(sub)
+	client.Subscriptions.Create(sub, recurly.WithXMLDeclaration(true))
+	client.XMLDeclaration = true
+	client.Subscriptions.Create(sub)
+	client.Subscriptions.Create(sub, recurly.WithXMLDeclaration(false))
+
+	if !reflect.DeepEqual(given, []string{
+		encoded,
+		xml.Header + encoded,
+		xml.Header + encoded,
+		encoded,
+	}) {
+		t.Fatalf("unexpected bodies: %q", given)
+	}
+
+	// An error reading the body fails the request rather than sending it
+	// without the declaration.
+	errRead := errors.New("read failed")
+	failFirstRead := func(req *http.Request) {
+		getBody, failed := req.GetBody, false
+		req.GetBody = func() (io.ReadCloser, error) {
+			if !failed {
+				failed = true
+				return nil, errRead
+			}
+			return getBody()
+		}
+	}
+	if _, _, err := client.Subscriptions.Create(sub, failFirstRead, recurly.WithXMLDeclaration(true)); !errors.Is(err, errRead) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(given) != 4 {
+		t.Fatalf("unexpected requests: %d", len(given))
+	}
+}
+
+func TestClient_NotFoundError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<error>
+				<symbol>not_found</symbol>
+				<description lang="en-US">Couldn't find Subscription with uuid = 44f83d7cba354d5b84812419f923ea96</description>
+			</error>`)
+	})
+
+	// Default: a nil subscription and no error.
+	if resp, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub != nil {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	} else if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	client.NotFoundError = true
+	if resp, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); !errors.Is(err, recurly.ErrNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub != nil {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	} else if len(resp.Errors) != 1 || resp.Errors[0].Symbol != "not_found" {
+		t.Fatalf("unexpected errors: %#v", resp.Errors)
+	}
+}
+
+func TestClient_Compression(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Fatalf("unexpected Accept-Encoding: %q", r.Header.Get("Accept-Encoding"))
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(200)
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>active</state></subscription>`)
+		gz.Close()
+	})
+
+	if _, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96", recurly.WithCompression(true)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub.UUID != "44f83d7cba354d5b84812419f923ea96" || sub.State != "active" {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+}
+
+func TestClient_Compression_Client(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Fatalf("unexpected Accept-Encoding: %q", r.Header.Get("Accept-Encoding"))
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(200)
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+		gz.Close()
+	})
+
+	// Services whose methods don't take options use the client setting.
+	client.Compression = true
+	if resp, a, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !resp.Uncompressed {
+		t.Fatal("expected compressed response")
+	} else if a.Code != "1" {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+}
+
+func TestClient_RetryPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var attempts int32
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if given.String() != "<account><account_code>1</account_code></account>" {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		if n < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	var backoffs []int
+	client.RetryPolicy = &recurly.RetryPolicy{
+		MaxRetries: 2,
+		Backoff: func(attempt int, resp *http.Response) time.Duration {
+			backoffs = append(backoffs, attempt)
+			if resp.StatusCode != http.StatusTooManyRequests {
+				t.Fatalf("unexpected status code: %d", resp.StatusCode)
+			}
+			return 0
+		},
+	}
+
+	var infos []recurly.RequestInfo
+	client.OnRequestComplete(func(info recurly.RequestInfo) {
+		infos = append(infos, info)
+	})
+
+	if resp, _, err := client.Accounts.Create(recurly.Account{Code: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 201 {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if atomic.LoadInt32(&attempts) != 3 || resp.Attempts != 3 {
+		t.Fatalf("unexpected attempts: %d, %d", atomic.LoadInt32(&attempts), resp.Attempts)
+	} else if !reflect.DeepEqual(backoffs, []int{1, 2}) {
+		t.Fatalf("unexpected backoff attempts: %v", backoffs)
+	} else if len(infos) != 1 || infos[0].Retries != 2 {
+		t.Fatalf("unexpected request info: %#v", infos)
+	}
+
+	// Once retries are exhausted the last response is returned.
+	atomic.StoreInt32(&attempts, 0)
+	client.RetryPolicy.MaxRetries = 1
+	if resp, _, err := client.Accounts.Create(recurly.Account{Code: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if atomic.LoadInt32(&attempts) != 2 || resp.Attempts != 2 {
+		t.Fatalf("unexpected attempts: %d, %d", atomic.LoadInt32(&attempts), resp.Attempts)
+	}
+}
+
+func TestClient_RetryPolicy_ReusesBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const body = "<subscription><quantity>2</quantity></subscription>"
+	var bodies []string
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		bodies = append(bodies, given.String())
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	client.RetryPolicy = &recurly.RetryPolicy{
+		MaxRetries: 1,
+		Backoff:    func(int, *http.Response) time.Duration { return 0 },
+	}
+
+	var infos []recurly.RequestInfo
+	client.OnRequestComplete(func(info recurly.RequestInfo) {
+		infos = append(infos, info)
+	})
+
+	// Replace the body with one that can only be read once.
+	replaceBody := func(req *http.Request) {
+		req.Body = struct{ io.ReadCloser }{ioutil.NopCloser(strings.NewReader(body))}
+		req.GetBody = nil
+	}
+
+	if resp, _, err := client.Subscriptions.Update("44f83d7cba354d5b84812419f923ea96", recurly.UpdateSubscription{}, replaceBody); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 200 || resp.Attempts != 2 {
+		t.Fatalf("unexpected response: %d after %d attempts", resp.StatusCode, resp.Attempts)
+	} else if !reflect.DeepEqual(bodies, []string{body, body}) {
+		t.Fatalf("unexpected bodies: %q", bodies)
+	} else if len(infos) != 1 || string(infos[0].Body) != body {
+		t.Fatalf("unexpected request info: %#v", infos)
+	}
+}
+
+func TestClient_RetryPolicy_NetworkError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var attempts int32
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			// Drop the connection without a response.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			conn.Close()
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	client.RetryPolicy = &recurly.RetryPolicy{
+		MaxRetries: 2,
+		Backoff: func(attempt int, resp *http.Response) time.Duration {
+			if resp != nil {
+				t.Fatalf("unexpected response: %d", resp.StatusCode)
+			}
+			return 0
+		},
+	}
+
+	if resp, a, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a.Code != "1" {
+		t.Fatalf("unexpected account: %#v", a)
+	} else if atomic.LoadInt32(&attempts) != 3 || resp.Attempts != 3 {
+		t.Fatalf("unexpected attempts: %d, %d", atomic.LoadInt32(&attempts), resp.Attempts)
+	}
+
+	// Once retries are exhausted the error of each attempt is returned.
+	atomic.StoreInt32(&attempts, -10)
+	_, _, err := client.Accounts.Get("1")
+	var urlErr *url.Error
+	if err == nil {
+		t.Fatal("expected error")
+	} else if !errors.As(err, &urlErr) {
+		t.Fatalf("unexpected error: %#v", err)
+	} else if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 3 {
+		t.Fatalf("unexpected error: %v", err)
+	} else if atomic.LoadInt32(&attempts) != -7 {
+		t.Fatalf("unexpected attempts: %d", atomic.LoadInt32(&attempts))
+	}
+}
+
+func TestClient_DecodeError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<quantity type="integer">three</quantity>
+				<state>active</state>
+			</subscription>`)
+	})
+
+	_, _, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	decodeErr, ok := err.(*recurly.DecodeError)
+	if !ok {
+		t.Fatalf("unexpected error: %#v", err)
+	} else if decodeErr.Type != "recurly.Subscription" {
+		t.Fatalf("unexpected type: %s", decodeErr.Type)
+	} else if decodeErr.Element != "quantity" {
+		t.Fatalf("unexpected element: %s", decodeErr.Element)
+	} else if !strings.Contains(decodeErr.Snippet, `<quantity type="integer">three</quantity>`) {
+		t.Fatalf("unexpected snippet: %s", decodeErr.Snippet)
+	} else if _, ok := decodeErr.Err.(*strconv.NumError); !ok {
+		t.Fatalf("unexpected underlying error: %#v", decodeErr.Err)
+	} else if !strings.Contains(err.Error(), `"quantity"`) {
+		t.Fatalf("unexpected error message: %s", err.Error())
+	}
+}
+
+func TestClient_DecodeError_Truncated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><accounts type="array"><account><account_code>1</account_code>`)
+	})
+
+	_, _, err := client.Accounts.List(nil)
+	if decodeErr, ok := err.(*recurly.DecodeError); !ok {
+		t.Fatalf("unexpected error: %#v", err)
+	} else if decodeErr.Type != "[]recurly.Account" {
+		t.Fatalf("unexpected type: %s", decodeErr.Type)
+	} else if decodeErr.Element != "account_code" {
+		t.Fatalf("unexpected element: %s", decodeErr.Element)
+	}
+}
+
+func TestClient_HTMLResponseError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `<!DOCTYPE html><html><head><title>Down for maintenance</title></head><body><h1>Recurly is down for scheduled maintenance.</h1></body></html>`)
+	})
+
+	resp, a, err := client.Accounts.Get("1")
+	var htmlErr *recurly.HTMLResponseError
+	if !errors.Is(err, recurly.ErrServiceUnavailable) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !errors.As(err, &htmlErr) {
+		t.Fatalf("unexpected error type: %T", err)
+	} else if htmlErr.StatusCode != http.StatusServiceUnavailable || !strings.Contains(htmlErr.Snippet, "Down for maintenance") {
+		t.Fatalf("unexpected error: %#v", htmlErr)
+	} else if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected response: %#v", resp)
+	} else if a != nil {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+}
+
+// TestClient_DashedUUIDs ensures every method taking a uuid sanitizes it.
+func TestClient_DashedUUIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var paths []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	const uuid = "44f83d7c-ba354d5b-84812419-f923ea96" // UUID has dashes and should be sanitized
+	client.Subscriptions.Get(uuid)
+	client.Subscriptions.Update(uuid, recurly.UpdateSubscription{})
+	client.Subscriptions.UpdateNotes(uuid, recurly.SubscriptionNotes{})
+	client.Subscriptions.PreviewChange(uuid, recurly.UpdateSubscription{})
+	client.Subscriptions.PreviewRenewal(uuid)
+	client.Subscriptions.Cancel(uuid)
+	client.Subscriptions.Reactivate(uuid)
+	client.Subscriptions.TerminateWithoutRefund(uuid)
+	client.Subscriptions.Postpone(uuid, time.Now(), false)
+	client.Transactions.Get(uuid)
+	client.Transactions.Capture(uuid, 0)
+	client.Transactions.Cancel(uuid)
+	client.Transactions.ListForSubscription(uuid, nil)
+	client.Adjustments.Get(uuid)
+	client.Adjustments.Delete(uuid)
+
+	if !reflect.DeepEqual(paths, []string{
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/notes",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview_renewal",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/reactivate",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone",
+		"/v2/transactions/44f83d7cba354d5b84812419f923ea96",
+		"/v2/purchases/transaction-uuid-44f83d7cba354d5b84812419f923ea96/capture",
+		"/v2/purchases/transaction-uuid-44f83d7cba354d5b84812419f923ea96/cancel",
+		"/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", // ListForSubscription looks up the subscription first
+		"/v2/adjustments/44f83d7cba354d5b84812419f923ea96",
+		"/v2/adjustments/44f83d7cba354d5b84812419f923ea96",
+	}) {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+}
+
+func TestValidateUUID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{id: "44f83d7cba354d5b84812419f923ea96", valid: true},
+		{id: "44f83d7c-ba354d5b-84812419-f923ea96", valid: true},
+		{id: "44F83D7CBA354D5B84812419F923EA96", valid: true},
+		{id: "", valid: false},
+		{id: "44f83d7cba354d5b84812419f923ea9", valid: false},
+		{id: "44f83d7cba354d5b84812419f923ea96/cancel", valid: false},
+		{id: "44f83d7cba354d5b8481 419f923ea96", valid: false},
+		{id: "zzf83d7cba354d5b84812419f923ea96", valid: false},
+	}
+
+	for _, tt := range tests {
+		if err := recurly.ValidateUUID(tt.id); tt.valid && err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.id, err)
+		} else if !tt.valid && err == nil {
+			t.Fatalf("%q: expected error", tt.id)
+		}
+	}
+}
+
+func TestEncodeBody(t *testing.T) {
+	tests := []struct {
+		v        interface{}
+		expected string
+	}{
+		{v: recurly.UpdateSubscription{}, expected: "<subscription></subscription>"},
+		{v: recurly.UpdateSubscription{Quantity: 2}, expected: "<subscription><quantity>2</quantity></subscription>"},
+		{v: recurly.Subscription{}.MakeUpdate(), expected: "<subscription><subscription_add_ons></subscription_add_ons></subscription>"},
+	}
+
+	for i, tt := range tests {
+		if given, err := recurly.EncodeBody(tt.v); err != nil {
+			t.Fatalf("(%d) unexpected error: %v", i, err)
+		} else if string(given) != tt.expected {
+			t.Fatalf("(%d) unexpected value: %s", i, given)
+		}
+	}
+}
+
+func TestEncodeBody_MatchesRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	sub := recurly.UpdateSubscription{
+		Timeframe: recurly.SubscriptionTimeframeRenewal,
+		PlanCode:  "gold",
+		Quantity:  3,
+	}
+	expected, err := recurly.EncodeBody(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var invoked bool
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if !bytes.Equal(given.Bytes(), expected) {
+			t.Fatalf("unexpected input: %s, expected %s", given.String(), expected)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	if _, _, err := client.Subscriptions.Update("44f83d7cba354d5b84812419f923ea96", sub); !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
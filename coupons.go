@@ -1,6 +1,21 @@
 package recurly
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"errors"
+)
+
+const (
+	// CouponDiscountTypePercent coupons take DiscountPercent off the price.
+	CouponDiscountTypePercent = "percent"
+
+	// CouponDiscountTypeDollars coupons take DiscountInCents off the price.
+	CouponDiscountTypeDollars = "dollars"
+
+	// CouponDiscountTypeFreeTrial coupons extend the trial rather than
+	// discounting the price.
+	CouponDiscountTypeFreeTrial = "free_trial"
+)
 
 // Coupon represents an individual coupon on your site.
 type Coupon struct {
@@ -12,7 +27,7 @@ type Coupon struct {
 	State              string            `xml:"state,omitempty"`
 	DiscountType       string            `xml:"discount_type"`
 	DiscountPercent    int               `xml:"discount_percent,omitempty"`
-	DiscountInCents    int               `xml:"discount_in_cents,omitempty"`
+	DiscountInCents    UnitAmount        `xml:"discount_in_cents,omitempty"`
 	RedeemByDate       NullTime          `xml:"redeem_by_date,omitempty"`
 	SingleUse          NullBool          `xml:"single_use,omitempty"`
 	AppliesForMonths   NullInt           `xml:"applies_for_months,omitempty"`
@@ -20,6 +35,72 @@ type Coupon struct {
 	AppliesToAllPlans  NullBool          `xml:"applies_to_all_plans,omitempty"`
 	CreatedAt          NullTime          `xml:"created_at,omitempty"`
 	PlanCodes          *[]CouponPlanCode `xml:"plan_codes>plan_code,omitempty"`
+
+	AppliesToNonPlanCharges NullBool `xml:"applies_to_non_plan_charges,omitempty"`
+}
+
+// Validate checks that the fields controlling which plans and charges the
+// coupon applies to don't conflict. Recurly returns a 422 for these cases.
+func (c Coupon) Validate() error {
+	hasPlanCodes := c.PlanCodes != nil && len(*c.PlanCodes) > 0
+	if c.AppliesToAllPlans.Is(true) && hasPlanCodes {
+		return errors.New("recurly: coupon cannot set plan codes when it applies to all plans")
+	} else if c.AppliesToAllPlans.Is(false) && !hasPlanCodes && !c.AppliesToNonPlanCharges.Is(true) {
+		return errors.New("recurly: coupon must apply to all plans, specific plan codes, or non-plan charges")
+	}
+
+	return nil
+}
+
+// Applies returns true if the coupon can be applied to the plan. Coupons
+// apply to all plans unless AppliesToAllPlans is explicitly false, in which
+// case the plan must be listed in PlanCodes.
+func (c Coupon) Applies(planCode string) bool {
+	if !c.AppliesToAllPlans.Is(false) {
+		return true
+	} else if c.PlanCodes == nil {
+		return false
+	}
+
+	for _, p := range *c.PlanCodes {
+		if p.Code == planCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DiscountFor returns the discount of the coupon in currency. Percent
+// coupons return their percent and zero cents, and fixed amount coupons return
+// zero percent and their amount in currency. Free trial coupons return zero
+// for both.
+func (c Coupon) DiscountFor(currency string) (percent int, cents int) {
+	switch c.DiscountType {
+	case CouponDiscountTypePercent:
+		return c.DiscountPercent, 0
+	case CouponDiscountTypeDollars:
+		return 0, c.DiscountInCents.Amount(currency)
+	}
+	return 0, 0
+}
+
+// Discount returns the discount in cents the coupon gives on amountInCents
+// in currency. Percent discounts are rounded to the nearest cent, and the
+// discount is never more than the amount. Free trial coupons give no
+// discount.
+func (c Coupon) Discount(currency string, amountInCents int) int {
+	percent, discount := c.DiscountFor(currency)
+	if percent > 0 {
+		discount = (amountInCents*percent + 50) / 100
+	}
+
+	if discount > amountInCents {
+		return amountInCents
+	} else if discount < 0 {
+		return 0
+	}
+	return discount
 }
 
 // CouponPlanCode holds an xml array of plan_code items that this coupon
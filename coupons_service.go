@@ -1,7 +1,6 @@
 package recurly
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 )
@@ -22,13 +21,7 @@ func (s *couponsImpl) List(params Params) (*Response, []Coupon, error) {
 		return nil, nil, err
 	}
 
-	var c struct {
-		XMLName xml.Name `xml:"coupons"`
-		Coupons []Coupon `xml:"coupon"`
-	}
-	resp, err := s.client.do(req, &c)
-
-	return resp, c.Coupons, err
+	return doList[Coupon](s.client, req, "coupons", "coupon")
 }
 
 // Get returns information about an active coupon.
@@ -50,8 +43,13 @@ func (s *couponsImpl) Get(code string) (*Response, *Coupon, error) {
 }
 
 // Create a new coupon. Coupons cannot be updated after being created.
+// The coupon is checked with Coupon.Validate before it is sent.
 // https://dev.recurly.com/docs/create-coupon
 func (s *couponsImpl) Create(c Coupon) (*Response, *Coupon, error) {
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.client.newRequest("POST", "coupons", nil, c)
 	if err != nil {
 		return nil, nil, err
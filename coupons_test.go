@@ -269,6 +269,56 @@ func TestCoupons_Get(t *testing.T) {
 	}
 }
 
+func TestCoupons_Get_FixedAmount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/coupons/fiveoff", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+        <coupon href="https://your-subdomain.recurly.com/v2/coupons/fiveoff">
+        	<coupon_code>fiveoff</coupon_code>
+        	<name>$5 off</name>
+        	<discount_type>dollars</discount_type>
+        	<discount_in_cents>
+        		<USD type="integer">500</USD>
+        		<EUR type="integer">450</EUR>
+        	</discount_in_cents>
+        </coupon>`)
+	})
+
+	_, coupon, err := client.Coupons.Get("fiveoff")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if coupon.DiscountInCents != (recurly.UnitAmount{USD: 500, EUR: 450}) {
+		t.Fatalf("unexpected discount: %#v", coupon.DiscountInCents)
+	}
+
+	if percent, cents := coupon.DiscountFor("EUR"); percent != 0 || cents != 450 {
+		t.Fatalf("unexpected EUR discount: %d%%, %d", percent, cents)
+	} else if percent, cents := coupon.DiscountFor("GBP"); percent != 0 || cents != 0 {
+		t.Fatalf("unexpected GBP discount: %d%%, %d", percent, cents)
+	}
+}
+
+func TestCoupons_DiscountFor(t *testing.T) {
+	tests := []struct {
+		v       recurly.Coupon
+		percent int
+		cents   int
+	}{
+		{v: recurly.Coupon{DiscountType: recurly.CouponDiscountTypePercent, DiscountPercent: 10}, percent: 10},
+		{v: recurly.Coupon{DiscountType: recurly.CouponDiscountTypeDollars, DiscountInCents: recurly.UnitAmount{USD: 500}}, cents: 500},
+		{v: recurly.Coupon{DiscountType: recurly.CouponDiscountTypeFreeTrial}},
+	}
+
+	for i, tt := range tests {
+		if percent, cents := tt.v.DiscountFor("USD"); percent != tt.percent || cents != tt.cents {
+			t.Fatalf("(%d): unexpected discount: %d%%, %d", i, percent, cents)
+		}
+	}
+}
+
 func TestCoupons_Get_ErrNotFound(t *testing.T) {
 	setup()
 	defer teardown()
@@ -309,6 +359,70 @@ func TestCoupons_Create(t *testing.T) {
 	}
 }
 
+func TestCoupons_Create_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/coupons", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected API call")
+	})
+
+	resp, coupon, err := client.Coupons.Create(recurly.Coupon{
+		AppliesToAllPlans: recurly.NewBool(true),
+		PlanCodes:         &[]recurly.CouponPlanCode{{Code: "gold"}},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	} else if resp != nil || coupon != nil {
+		t.Fatalf("unexpected result: %v %v", resp, coupon)
+	}
+}
+
+func TestCoupons_Validate(t *testing.T) {
+	tests := []struct {
+		v     recurly.Coupon
+		valid bool
+	}{
+		{v: recurly.Coupon{}, valid: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(true)}, valid: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(true), PlanCodes: &[]recurly.CouponPlanCode{}}, valid: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false), PlanCodes: &[]recurly.CouponPlanCode{{Code: "gold"}}}, valid: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false), AppliesToNonPlanCharges: recurly.NewBool(true)}, valid: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(true), PlanCodes: &[]recurly.CouponPlanCode{{Code: "gold"}}}, valid: false},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false)}, valid: false},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false), PlanCodes: &[]recurly.CouponPlanCode{}}, valid: false},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false), AppliesToNonPlanCharges: recurly.NewBool(false)}, valid: false},
+	}
+
+	for i, tt := range tests {
+		if err := tt.v.Validate(); tt.valid && err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if !tt.valid && err == nil {
+			t.Fatalf("(%d): expected error", i)
+		}
+	}
+}
+
+func TestCoupons_Applies(t *testing.T) {
+	tests := []struct {
+		v        recurly.Coupon
+		plan     string
+		expected bool
+	}{
+		{v: recurly.Coupon{}, plan: "gold", expected: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(true)}, plan: "gold", expected: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false), PlanCodes: &[]recurly.CouponPlanCode{{Code: "silver"}, {Code: "gold"}}}, plan: "gold", expected: true},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false), PlanCodes: &[]recurly.CouponPlanCode{{Code: "silver"}}}, plan: "gold", expected: false},
+		{v: recurly.Coupon{AppliesToAllPlans: recurly.NewBool(false)}, plan: "gold", expected: false},
+	}
+
+	for i, tt := range tests {
+		if given := tt.v.Applies(tt.plan); given != tt.expected {
+			t.Fatalf("(%d): unexpected value: %v", i, given)
+		}
+	}
+}
+
 func TestCoupons_Delete(t *testing.T) {
 	setup()
 	defer teardown()
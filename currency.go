@@ -0,0 +1,54 @@
+package recurly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownCurrencies are the ISO 4217 codes of the currencies Recurly supports.
+var knownCurrencies = map[string]struct{}{
+	"AED": {}, "ARS": {}, "AUD": {}, "BRL": {}, "CAD": {}, "CHF": {},
+	"CLP": {}, "CNY": {}, "COP": {}, "CZK": {}, "DKK": {}, "EUR": {},
+	"GBP": {}, "HKD": {}, "HUF": {}, "IDR": {}, "ILS": {}, "INR": {},
+	"ISK": {}, "JPY": {}, "KRW": {}, "MXN": {}, "MYR": {}, "NOK": {},
+	"NZD": {}, "PHP": {}, "PLN": {}, "RUB": {}, "SAR": {}, "SEK": {},
+	"SGD": {}, "THB": {}, "TRY": {}, "TWD": {}, "UAH": {}, "USD": {},
+	"ZAR": {},
+}
+
+// IsKnownCurrency reports whether code is the ISO 4217 code of a currency
+// Recurly is known to support. Recurly may support currencies added after
+// this list was last updated.
+func IsKnownCurrency(code string) bool {
+	_, ok := knownCurrencies[strings.ToUpper(code)]
+	return ok
+}
+
+// ValidateCurrency returns an error if code is not shaped like an ISO 4217
+// currency code, which is three letters such as "USD". It does not check
+// that Recurly supports the currency; see IsKnownCurrency.
+func ValidateCurrency(code string) error {
+	if len(code) != 3 {
+		return fmt.Errorf("recurly: invalid currency %q, must be a three letter ISO 4217 code", code)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return fmt.Errorf("recurly: invalid currency %q, must be a three letter ISO 4217 code", code)
+		}
+	}
+	return nil
+}
+
+// validateCurrency validates a currency sent in a request. An empty currency
+// is left for Recurly to reject or default. Unknown currencies are only
+// rejected when c.StrictCurrency is set.
+func (c *Client) validateCurrency(code string) error {
+	if code == "" {
+		return nil
+	} else if err := ValidateCurrency(code); err != nil {
+		return err
+	} else if c.StrictCurrency && !IsKnownCurrency(code) {
+		return fmt.Errorf("recurly: unsupported currency %q", code)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package recurly_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/portofinolabs/recurly"
+)
+
+func TestValidateCurrency(t *testing.T) {
+	tests := []struct {
+		code  string
+		valid bool
+		known bool
+	}{
+		{code: "USD", valid: true, known: true},
+		{code: "EUR", valid: true, known: true},
+		{code: "jpy", valid: true, known: true},
+		{code: "XYZ", valid: true, known: false},
+		{code: "US", valid: false},
+		{code: "USDD", valid: false},
+		{code: "U$D", valid: false},
+		{code: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		if err := recurly.ValidateCurrency(tt.code); tt.valid && err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.code, err)
+		} else if !tt.valid && err == nil {
+			t.Fatalf("%q: expected error", tt.code)
+		} else if known := recurly.IsKnownCurrency(tt.code); known != tt.known {
+			t.Fatalf("%q: unexpected known: %v", tt.code, known)
+		}
+	}
+}
+
+func TestSubscriptions_Create_InvalidCurrency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected API call")
+	})
+
+	sub := recurly.NewSubscription{
+		PlanCode: "gold",
+		Currency: "US",
+		Account:  recurly.Account{Code: "1"},
+	}
+	if _, _, err := client.Subscriptions.Create(sub); err == nil {
+		t.Fatal("expected error for malformed currency")
+	}
+
+	// Unknown currencies are only rejected when StrictCurrency is set.
+	sub.Currency = "XYZ"
+	client.StrictCurrency = true
+	if _, _, err := client.Subscriptions.Preview(sub); err == nil {
+		t.Fatal("expected error for unknown currency")
+	}
+}
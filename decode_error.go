@@ -0,0 +1,148 @@
+package recurly
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+// decodeSnippetLen is the maximum number of bytes of the response body
+// included before and after the point where decoding failed.
+const decodeSnippetLen = 80
+
+// DecodeError is returned when a response body cannot be decoded into the
+// requested type.
+type DecodeError struct {
+	// Type is the name of the type being decoded, e.g. recurly.Subscription.
+	Type string
+
+	// Element is the name of the element being decoded when the error
+	// occurred. It is empty if it could not be determined.
+	Element string
+
+	// Snippet is the part of the response body around where decoding failed.
+	Snippet string
+
+	// Err is the underlying error returned by the XML decoder.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Element == "" {
+		return fmt.Sprintf("recurly: unable to decode %s: %v (near %q)", e.Type, e.Err, e.Snippet)
+	}
+	return fmt.Sprintf("recurly: unable to decode %s element %q: %v (near %q)", e.Type, e.Element, e.Err, e.Snippet)
+}
+
+// Unwrap returns the underlying decoder error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError wraps err, which occurred decoding v after offset bytes of
+// body had been read.
+func newDecodeError(err error, v interface{}, body []byte, offset int64) *DecodeError {
+	if offset < 0 || offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+
+	start, end := int(offset)-decodeSnippetLen, int(offset)+decodeSnippetLen
+	if start < 0 {
+		start = 0
+	}
+	if end > len(body) {
+		end = len(body)
+	}
+
+	return &DecodeError{
+		Type:    decodeTypeName(v),
+		Element: lastElement(body[:offset]),
+		Snippet: string(bytes.TrimSpace(body[start:end])),
+		Err:     err,
+	}
+}
+
+// decodeTypeName returns the name of the type v points to. Lists decoded by
+// doList are named by their items type, e.g. []recurly.Subscription, and
+// other anonymous types are described by their kind.
+func decodeTypeName(v interface{}) string {
+	if l, ok := v.(interface{ typeName() string }); ok {
+		return l.typeName()
+	}
+
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "<nil>"
+	} else if t.Name() == "" {
+		return t.Kind().String()
+	}
+	return t.String()
+}
+
+// lastElement returns the name of the last element opened or closed in b.
+// The decoder reports a value error once it reaches the element's end tag,
+// so this is the element whose value could not be decoded.
+func lastElement(b []byte) string {
+	i := bytes.LastIndexByte(b, '<')
+	if i < 0 {
+		return ""
+	}
+
+	tag := bytes.TrimLeft(b[i+1:], "/")
+	if j := bytes.IndexAny(tag, " \t\r\n/>"); j >= 0 {
+		tag = tag[:j]
+	}
+	if len(tag) == 0 || tag[0] == '?' || tag[0] == '!' {
+		return ""
+	}
+	return string(tag)
+}
+
+// ErrServiceUnavailable is wrapped by an HTMLResponseError when Recurly
+// responds with 503 Service Unavailable, such as during maintenance.
+var ErrServiceUnavailable = errors.New("recurly: service unavailable")
+
+// HTMLResponseError is returned when Recurly responds with an error status
+// and an HTML page instead of XML, such as a maintenance page. The response
+// is also returned, so its status and headers can be checked.
+type HTMLResponseError struct {
+	StatusCode int
+
+	// Snippet is the start of the response body.
+	Snippet string
+}
+
+func (e *HTMLResponseError) Error() string {
+	return fmt.Sprintf("recurly: unexpected HTML response with status %d (near %q)", e.StatusCode, e.Snippet)
+}
+
+// Unwrap returns ErrServiceUnavailable for 503 responses, and nil otherwise.
+func (e *HTMLResponseError) Unwrap() error {
+	if e.StatusCode == http.StatusServiceUnavailable {
+		return ErrServiceUnavailable
+	}
+	return nil
+}
+
+// newHTMLResponseError returns an HTMLResponseError if resp is an HTML page,
+// reading the start of its body, or nil otherwise.
+func newHTMLResponseError(resp *http.Response) *HTMLResponseError {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" {
+		return nil
+	}
+
+	b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 2*decodeSnippetLen))
+	return &HTMLResponseError{
+		StatusCode: resp.StatusCode,
+		Snippet:    string(bytes.TrimSpace(b)),
+	}
+}
@@ -0,0 +1,14 @@
+package recurly
+
+import "encoding/xml"
+
+// ExportFile is a file generated by Recurly's automated exports.
+// DownloadURL and ExpiresAt are only returned when looking up an
+// individual file.
+type ExportFile struct {
+	XMLName     xml.Name `xml:"export_file"`
+	Name        string   `xml:"name,omitempty"`
+	MD5Sum      string   `xml:"md5sum,omitempty"`
+	ExpiresAt   NullTime `xml:"expires_at,omitempty"`
+	DownloadURL string   `xml:"download_url,omitempty"`
+}
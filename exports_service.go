@@ -0,0 +1,90 @@
+package recurly
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var _ ExportsService = &exportsImpl{}
+
+// exportsImpl handles communication with the automated exports related
+// methods of the recurly API.
+type exportsImpl struct {
+	client *Client
+}
+
+// ListDates returns the dates that have export files available, formatted
+// as YYYY-MM-DD.
+// https://dev.recurly.com/docs/list-export-dates
+func (s *exportsImpl) ListDates() (*Response, []string, error) {
+	req, err := s.client.newRequest("GET", "export_dates", nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doList[string](s.client, req, "export_dates", "export_date>date")
+}
+
+// ListFiles returns the export files available for a date.
+// https://dev.recurly.com/docs/list-date-export-files
+func (s *exportsImpl) ListFiles(date string) (*Response, []ExportFile, error) {
+	action := fmt.Sprintf("export_dates/%s/export_files", date)
+	req, err := s.client.newRequest("GET", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doList[ExportFile](s.client, req, "export_files", "export_file")
+}
+
+// GetFile returns an export file, including a temporary signed URL to
+// download it.
+// https://dev.recurly.com/docs/lookup-export-file
+func (s *exportsImpl) GetFile(date string, fileName string) (*Response, *ExportFile, error) {
+	action := fmt.Sprintf("export_dates/%s/export_files/%s", date, fileName)
+	req, err := s.client.newRequest("GET", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst ExportFile
+	resp, err := s.client.do(req, &dst)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, err
+	}
+
+	return resp, &dst, err
+}
+
+// DownloadFile looks up an export file and then downloads it from the
+// signed URL returned by Recurly. The file is gzipped CSV and is streamed
+// as-is; the caller is responsible for closing the returned reader.
+// If the lookup fails, the lookup response is returned. Otherwise the
+// response is for the download request.
+func (s *exportsImpl) DownloadFile(date string, fileName string) (*Response, io.ReadCloser, error) {
+	resp, file, err := s.GetFile(date, fileName)
+	if err != nil || file == nil {
+		return resp, nil, err
+	}
+
+	// The download URL is pre-signed, so the request is sent without the
+	// API credentials.
+	req, err := http.NewRequest("GET", file.DownloadURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := s.client.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp = &Response{Response: r, Attempts: 1}
+	if resp.IsError() {
+		r.Body.Close()
+		return resp, nil, nil
+	}
+
+	return resp, r.Body, nil
+}
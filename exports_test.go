@@ -0,0 +1,162 @@
+package recurly_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/portofinolabs/recurly"
+)
+
+func TestExports_ListDates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/export_dates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<export_dates type="array">
+			<export_date>
+				<date>2016-08-01</date>
+				<export_files href="https://your-subdomain.recurly.com/v2/export_dates/2016-08-01/export_files"/>
+			</export_date>
+			<export_date>
+				<date>2016-08-02</date>
+				<export_files href="https://your-subdomain.recurly.com/v2/export_dates/2016-08-02/export_files"/>
+			</export_date>
+		</export_dates>`)
+	})
+
+	resp, dates, err := client.Exports.ListDates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected list export dates to return OK")
+	} else if !reflect.DeepEqual(dates, []string{"2016-08-01", "2016-08-02"}) {
+		t.Fatalf("unexpected dates: %v", dates)
+	}
+}
+
+func TestExports_ListFiles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/export_dates/2016-08-01/export_files", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<export_files type="array">
+			<export_file href="https://your-subdomain.recurly.com/v2/export_dates/2016-08-01/export_files/churned_subscriptions_v2_expires.csv.gz">
+				<name>churned_subscriptions_v2_expires.csv.gz</name>
+				<md5sum>1f3bdf1e1ad8ae2e6c3a2b5fc0a4b5e1</md5sum>
+			</export_file>
+			<export_file href="https://your-subdomain.recurly.com/v2/export_dates/2016-08-01/export_files/invoices_created.csv.gz">
+				<name>invoices_created.csv.gz</name>
+				<md5sum>9a5d2e8c4f7b1a3e6d0c2b4a8f6e1d3c</md5sum>
+			</export_file>
+		</export_files>`)
+	})
+
+	resp, files, err := client.Exports.ListFiles("2016-08-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected list export files to return OK")
+	} else if !reflect.DeepEqual(files, []recurly.ExportFile{
+		{
+			XMLName: xml.Name{Local: "export_file"},
+			Name:    "churned_subscriptions_v2_expires.csv.gz",
+			MD5Sum:  "1f3bdf1e1ad8ae2e6c3a2b5fc0a4b5e1",
+		},
+		{
+			XMLName: xml.Name{Local: "export_file"},
+			Name:    "invoices_created.csv.gz",
+			MD5Sum:  "9a5d2e8c4f7b1a3e6d0c2b4a8f6e1d3c",
+		},
+	}) {
+		t.Fatalf("unexpected files: %v", files)
+	}
+}
+
+func TestExports_DownloadFile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/export_dates/2016-08-01/export_files/invoices_created.csv.gz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<export_file href="https://your-subdomain.recurly.com/v2/export_dates/2016-08-01/export_files/invoices_created.csv.gz">
+			<expires_at type="datetime">2016-08-01T21:14:33Z</expires_at>
+			<download_url>%s/downloads/invoices_created.csv.gz?signature=abc</download_url>
+		</export_file>`, server.URL)
+	})
+
+	mux.HandleFunc("/downloads/invoices_created.csv.gz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("signature") != "abc" {
+			t.Fatalf("unexpected signature: %s", r.URL.Query().Get("signature"))
+		} else if r.Header.Get("Authorization") != "" {
+			t.Fatal("expected credentials to not be sent with download")
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, "gzipped-csv")
+	})
+
+	resp, file, err := client.Exports.GetFile("2016-08-01", "invoices_created.csv.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected get export file to return OK")
+	} else if !reflect.DeepEqual(file, &recurly.ExportFile{
+		XMLName:     xml.Name{Local: "export_file"},
+		ExpiresAt:   recurly.NewTime(time.Date(2016, time.August, 1, 21, 14, 33, 0, time.UTC)),
+		DownloadURL: server.URL + "/downloads/invoices_created.csv.gz?signature=abc",
+	}) {
+		t.Fatalf("unexpected file: %v", file)
+	}
+
+	resp, body, err := client.Exports.DownloadFile("2016-08-01", "invoices_created.csv.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected download export file to return OK")
+	}
+	defer body.Close()
+
+	if b, err := ioutil.ReadAll(body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if string(b) != "gzipped-csv" {
+		t.Fatalf("unexpected file contents: %s", b)
+	}
+}
+
+func TestExports_DownloadFile_ErrNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/export_dates/2016-08-01/export_files/invoices_created.csv.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+
+	resp, body, err := client.Exports.DownloadFile("2016-08-01", "invoices_created.csv.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 404 {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if body != nil {
+		t.Fatal("expected body to be nil")
+	}
+}
@@ -0,0 +1,46 @@
+package recurly
+
+import "encoding/xml"
+
+// extraKeeper is implemented by decode destinations that can keep unknown
+// elements. When the client's KeepUnknownXML is set, do calls keepExtra on
+// the destination before decoding into it.
+type extraKeeper interface {
+	keepExtra()
+}
+
+// keepExtra makes s keep unknown elements when it is decoded.
+func (s *Subscription) keepExtra() {
+	s.Extra = map[string]string{}
+}
+
+// keepExtra makes t keep unknown elements when it is decoded.
+func (t *Transaction) keepExtra() {
+	t.Extra = map[string]string{}
+}
+
+// keepExtra makes the previewed subscription keep unknown elements when p
+// is decoded.
+func (p *SubscriptionPreview) keepExtra() {
+	p.Subscription.keepExtra()
+}
+
+// extraElement holds an element that is not mapped to a known field.
+type extraElement struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// extraMap converts unknown elements into a map of element name to text
+// content. It returns nil if keep is false or there are none.
+func extraMap(keep bool, elems []extraElement) map[string]string {
+	if !keep || len(elems) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(elems))
+	for _, e := range elems {
+		m[e.XMLName.Local] = e.Value
+	}
+	return m
+}
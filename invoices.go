@@ -2,6 +2,8 @@ package recurly
 
 import (
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -21,6 +23,34 @@ const (
 	// failed, but Recurly is still attempting collection.
 	InvoiceStatePastDue = "past_due"
 
+	// InvoiceStatePending is an invoice state for charge invoices that have
+	// not been collected yet.
+	InvoiceStatePending = "pending"
+
+	// InvoiceStateProcessing is an invoice state for invoices paid with an
+	// asynchronous payment method, such as ACH, that is still processing.
+	InvoiceStateProcessing = "processing"
+
+	// InvoiceStatePaid is an invoice state for invoices that have been paid.
+	InvoiceStatePaid = "paid"
+
+	// InvoiceStateClosed is an invoice state for credit invoices that have
+	// been fully applied.
+	InvoiceStateClosed = "closed"
+
+	// InvoiceStateVoided is an invoice state for invoices that have been voided.
+	InvoiceStateVoided = "voided"
+
+	// InvoiceTypeCharge is the type for invoices that charge the customer.
+	InvoiceTypeCharge = "charge"
+
+	// InvoiceTypeCredit is the type for invoices that credit the customer.
+	InvoiceTypeCredit = "credit"
+
+	// InvoiceTypeLegacy is the type for invoices created before credit
+	// invoices were enabled on the site.
+	InvoiceTypeLegacy = "legacy"
+
 	// CollectionMethodAutomatic is a collection method where the customer's
 	// credit card is charged.
 	CollectionMethodAutomatic = "automatic"
@@ -30,6 +60,17 @@ const (
 	CollectionMethodManual = "manual"
 )
 
+// ValidateCollectionMethod returns an error if method is not one of the
+// collection method constants. An empty method is valid, leaving Recurly's
+// default in place.
+func ValidateCollectionMethod(method string) error {
+	switch method {
+	case "", CollectionMethodAutomatic, CollectionMethodManual:
+		return nil
+	}
+	return fmt.Errorf("recurly: invalid collection method %q, must be %q or %q", method, CollectionMethodAutomatic, CollectionMethodManual)
+}
+
 // Payment method constants.
 const (
 	PaymentMethodCreditCard   = "credit_card"
@@ -52,6 +93,7 @@ type Invoice struct {
 	OriginalInvoiceNumber int           `xml:"-"`
 	UUID                  string        `xml:"-"`
 	State                 string        `xml:"-"`
+	Type                  string        `xml:"-"`
 	InvoiceNumberPrefix   string        `xml:"-"`
 	InvoiceNumber         int           `xml:"-"`
 	PONumber              string        `xml:"po_number,omitempty"` // PostInvoice param
@@ -62,6 +104,7 @@ type Invoice struct {
 	Currency              string        `xml:"-"`
 	CreatedAt             NullTime      `xml:"-"`
 	ClosedAt              NullTime      `xml:"-"`
+	DueOn                 NullTime      `xml:"-"`
 	TaxType               string        `xml:"-"`
 	TaxRegion             string        `xml:"-"`
 	TaxRate               float64       `xml:"-"`
@@ -85,6 +128,7 @@ func (i *Invoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		OriginalInvoiceNumber hrefInt       `xml:"original_invoice,omitempty"` // Read only
 		UUID                  string        `xml:"uuid,omitempty"`
 		State                 string        `xml:"state,omitempty"`
+		Type                  string        `xml:"type,omitempty"`
 		InvoiceNumberPrefix   string        `xml:"invoice_number_prefix,omitempty"`
 		InvoiceNumber         int           `xml:"invoice_number,omitempty"`
 		PONumber              string        `xml:"po_number,omitempty"`
@@ -95,11 +139,13 @@ func (i *Invoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		Currency              string        `xml:"currency,omitempty"`
 		CreatedAt             NullTime      `xml:"created_at,omitempty"`
 		ClosedAt              NullTime      `xml:"closed_at,omitempty"`
+		DueOn                 NullTime      `xml:"due_on,omitempty"`
 		TaxType               string        `xml:"tax_type,omitempty"`
 		TaxRegion             string        `xml:"tax_region,omitempty"`
 		TaxRate               float64       `xml:"tax_rate,omitempty"`
 		NetTerms              NullInt       `xml:"net_terms,omitempty"`
 		CollectionMethod      string        `xml:"collection_method,omitempty"`
+		TermsAndConditions    string        `xml:"terms_and_conditions,omitempty"`
 		LineItems             []Adjustment  `xml:"line_items>adjustment,omitempty"`
 		Transactions          []Transaction `xml:"transactions>transaction,omitempty"`
 	}
@@ -114,6 +160,7 @@ func (i *Invoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		OriginalInvoiceNumber: int(v.OriginalInvoiceNumber),
 		UUID:                v.UUID,
 		State:               v.State,
+		Type:                v.Type,
 		InvoiceNumberPrefix: v.InvoiceNumberPrefix,
 		InvoiceNumber:       v.InvoiceNumber,
 		PONumber:            v.PONumber,
@@ -124,11 +171,13 @@ func (i *Invoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		Currency:            v.Currency,
 		CreatedAt:           v.CreatedAt,
 		ClosedAt:            v.ClosedAt,
+		DueOn:               v.DueOn,
 		TaxType:             v.TaxType,
 		TaxRegion:           v.TaxRegion,
 		TaxRate:             v.TaxRate,
 		NetTerms:            v.NetTerms,
 		CollectionMethod:    v.CollectionMethod,
+		TermsAndConditions:  v.TermsAndConditions,
 		LineItems:           v.LineItems,
 		Transactions:        v.Transactions,
 	}
@@ -136,6 +185,117 @@ func (i *Invoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// InvoiceListOptions are typed filters for listing invoices. Zero values
+// are not sent.
+type InvoiceListOptions struct {
+	Type      string // InvoiceTypeCharge, InvoiceTypeCredit, or InvoiceTypeLegacy
+	State     string // One of the InvoiceState constants
+	BeginTime time.Time
+	EndTime   time.Time
+	PerPage   int
+	Cursor    string
+}
+
+// invoiceStates are the states that invoices can be filtered by.
+var invoiceStates = map[string]bool{
+	InvoiceStateOpen:       true,
+	InvoiceStateCollected:  true,
+	InvoiceStateFailed:     true,
+	InvoiceStatePastDue:    true,
+	InvoiceStatePending:    true,
+	InvoiceStateProcessing: true,
+	InvoiceStatePaid:       true,
+	InvoiceStateClosed:     true,
+	InvoiceStateVoided:     true,
+}
+
+// Params validates the options and converts them to query parameters.
+func (o InvoiceListOptions) Params() (Params, error) {
+	params := Params{}
+	if o.Type != "" {
+		if o.Type != InvoiceTypeCharge && o.Type != InvoiceTypeCredit && o.Type != InvoiceTypeLegacy {
+			return nil, fmt.Errorf("recurly: invalid invoice type %q", o.Type)
+		}
+		params["type"] = o.Type
+	}
+	if o.State != "" {
+		if !invoiceStates[o.State] {
+			return nil, fmt.Errorf("recurly: invalid invoice state %q", o.State)
+		}
+		params["state"] = o.State
+	}
+	if !o.BeginTime.IsZero() {
+		params["begin_time"] = o.BeginTime.UTC().Format(DateTimeFormat)
+	}
+	if !o.EndTime.IsZero() {
+		params["end_time"] = o.EndTime.UTC().Format(DateTimeFormat)
+	}
+	if o.PerPage > 0 {
+		params["per_page"] = o.PerPage
+	}
+	if o.Cursor != "" {
+		params["cursor"] = o.Cursor
+	}
+
+	return params, nil
+}
+
+// InvoiceCollection is returned by endpoints that can generate a charge
+// invoice along with any credit invoices, such as purchases.
+type InvoiceCollection struct {
+	ChargeInvoice  *Invoice
+	CreditInvoices []Invoice
+}
+
+// UnmarshalXML unmarshals an invoice collection. The charge_invoice and
+// credit_invoice elements are decoded as invoices.
+func (c *InvoiceCollection) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*c = InvoiceCollection{}
+	for depth := 0; ; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "charge_invoice" && t.Name.Local != "credit_invoice" {
+				depth++
+				continue
+			}
+
+			name := t.Name.Local
+			t.Name.Local = "invoice"
+			var invoice Invoice
+			if err := d.DecodeElement(&invoice, &t); err != nil {
+				return err
+			}
+
+			if name == "charge_invoice" {
+				c.ChargeInvoice = &invoice
+			} else {
+				c.CreditInvoices = append(c.CreditInvoices, invoice)
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// Total returns the total of the charge invoice, including tax, and its
+// currency. Line items on the charge invoice identify the subscription they
+// originate from with SubscriptionUUID; adjustments have it unset. It returns
+// 0 and an empty currency if there is no charge invoice.
+func (c InvoiceCollection) Total() (cents int, currency string) {
+	if c.ChargeInvoice == nil {
+		return 0, ""
+	}
+	return c.ChargeInvoice.TotalInCents, c.ChargeInvoice.Currency
+}
+
 // OfflinePayment is a payment received outside the system to be recorded in Recurly.
 type OfflinePayment struct {
 	XMLName       xml.Name   `xml:"transaction"`
@@ -145,3 +305,17 @@ type OfflinePayment struct {
 	Amount        int        `xml:"amount_in_cents,omitempty"`
 	Description   string     `xml:"description,omitempty"`
 }
+
+// Validate ensures the required fields for recording an offline payment
+// are set.
+func (p OfflinePayment) Validate() error {
+	if p.InvoiceNumber <= 0 {
+		return errors.New("recurly: offline payment requires an invoice number")
+	} else if p.PaymentMethod == "" {
+		return errors.New("recurly: offline payment requires a payment method")
+	} else if p.Amount <= 0 {
+		return errors.New("recurly: offline payment requires a positive amount")
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 )
 
 var _ InvoicesService = &invoicesImpl{}
@@ -24,13 +25,18 @@ func (s *invoicesImpl) List(params Params) (*Response, []Invoice, error) {
 		return nil, nil, err
 	}
 
-	var p struct {
-		XMLName  xml.Name  `xml:"invoices"`
-		Invoices []Invoice `xml:"invoice"`
+	return doList[Invoice](s.client, req, "invoices", "invoice")
+}
+
+// ListWithOptions returns a list of all invoices filtered by opts.
+// https://dev.recurly.com/docs/list-invoices
+func (s *invoicesImpl) ListWithOptions(opts InvoiceListOptions) (*Response, []Invoice, error) {
+	params, err := opts.Params()
+	if err != nil {
+		return nil, nil, err
 	}
-	resp, err := s.client.do(req, &p)
 
-	return resp, p.Invoices, err
+	return s.List(params)
 }
 
 // ListAccount returns a list of all invoices for an account.
@@ -42,13 +48,7 @@ func (s *invoicesImpl) ListAccount(accountCode string, params Params) (*Response
 		return nil, nil, err
 	}
 
-	var p struct {
-		XMLName  xml.Name  `xml:"invoices"`
-		Invoices []Invoice `xml:"invoice"`
-	}
-	resp, err := s.client.do(req, &p)
-
-	return resp, p.Invoices, err
+	return doList[Invoice](s.client, req, "invoices", "invoice")
 }
 
 // Get returns detailed information about an invoice including line items and
@@ -56,14 +56,28 @@ func (s *invoicesImpl) ListAccount(accountCode string, params Params) (*Response
 // newest.
 // https://dev.recurly.com/docs/lookup-invoice-details
 func (s *invoicesImpl) Get(invoiceNumber int) (*Response, *Invoice, error) {
-	action := fmt.Sprintf("invoices/%d", invoiceNumber)
+	return s.get(strconv.Itoa(invoiceNumber))
+}
+
+// GetByNumber returns an invoice whose number has a prefix, as used by sites
+// with several invoice numbering sequences. For example, invoice FR1009 has
+// the prefix "FR" and the number 1009. An empty prefix behaves like Get.
+// https://dev.recurly.com/docs/lookup-invoice-details
+func (s *invoicesImpl) GetByNumber(prefix string, invoiceNumber int) (*Response, *Invoice, error) {
+	return s.get(prefix + strconv.Itoa(invoiceNumber))
+}
+
+// get looks up an invoice by its full, possibly prefixed, number, applying
+// opts to the request.
+func (s *invoicesImpl) get(invoiceNumber string, opts ...RequestOption) (*Response, *Invoice, error) {
+	action := fmt.Sprintf("invoices/%s", invoiceNumber)
 	req, err := s.client.newRequest("GET", action, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var dst Invoice
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 	if err != nil || resp.StatusCode >= http.StatusBadRequest {
 		return resp, nil, err
 	}
@@ -123,6 +137,10 @@ func (s *invoicesImpl) Preview(accountCode string) (*Response, *Invoice, error)
 // subscription, you might want to collect the one-time charges well before the renewal.
 // https://dev.recurly.com/docs/post-an-invoice-invoice-pending-charges-on-an-acco
 func (s *invoicesImpl) Create(accountCode string, invoice Invoice) (*Response, *Invoice, error) {
+	if err := ValidateCollectionMethod(invoice.CollectionMethod); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("accounts/%s/invoices", accountCode)
 	req, err := s.client.newRequest("POST", action, nil, invoice)
 	if err != nil {
@@ -187,6 +205,24 @@ func (s *invoicesImpl) MarkFailed(invoiceNumber int) (*Response, *Invoice, error
 	return resp, &dst, err
 }
 
+// Void voids a credit invoice, such as one issued in error. Recurly rejects
+// the request with a validation error in resp.Errors if the invoice is not a
+// credit invoice or cannot be voided, for example because its credit has
+// already been applied.
+// https://dev.recurly.com/docs/void-credit-invoice
+func (s *invoicesImpl) Void(invoiceNumber int) (*Response, *Invoice, error) {
+	action := fmt.Sprintf("invoices/%d/void", invoiceNumber)
+	req, err := s.client.newRequest("PUT", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst Invoice
+	resp, err := s.client.do(req, &dst)
+
+	return resp, &dst, err
+}
+
 // RefundVoidOpenAmount allows custom invoice amounts to be refunded and generates a refund invoice.
 // Full open amount refunds of invoices with an unsettled transaction will void
 // the transaction and generate a void invoice.
@@ -212,9 +248,15 @@ func (s *invoicesImpl) RefundVoidOpenAmount(invoiceNumber int, amountInCents int
 	return resp, &dst, err
 }
 
-// RecordPayment records an offline payment for a manual invoice.
+// RecordPayment records an offline payment, such as a check or wire transfer,
+// for a manual invoice. The invoice number, payment method, and amount are
+// required and are validated before the request is sent.
 // https://dev.recurly.com/v2.5/docs/enter-an-offline-payment-for-a-manual-invoice-beta
 func (s *invoicesImpl) RecordPayment(offlinePayment OfflinePayment) (*Response, *Transaction, error) {
+	if err := offlinePayment.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("invoices/%d/transactions", offlinePayment.InvoiceNumber)
 	req, err := s.client.newRequest("POST", action, nil, offlinePayment)
 	if err != nil {
@@ -226,3 +268,14 @@ func (s *invoicesImpl) RecordPayment(offlinePayment OfflinePayment) (*Response,
 
 	return resp, &dst, err
 }
+
+// ListRedemptions returns all coupon redemptions applied to an invoice.
+func (s *invoicesImpl) ListRedemptions(invoiceNumber int, params Params) (*Response, []CouponRedemption, error) {
+	action := fmt.Sprintf("invoices/%d/redemptions", invoiceNumber)
+	req, err := s.client.newRequest("GET", action, params, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doList[CouponRedemption](s.client, req, "redemptions", "redemption")
+}
@@ -124,6 +124,7 @@ func TestInvoices_List(t *testing.T) {
 			{
 				AccountCode:            "100",
 				InvoiceNumber:          1108,
+				SubscriptionUUID:       "17caaca1716f33572edc8146e0aaefde",
 				UUID:                   "626db120a84102b1809909071c701c60",
 				State:                  "invoiced",
 				Description:            "One-time Charged Fee",
@@ -146,6 +147,90 @@ func TestInvoices_List(t *testing.T) {
 	}
 }
 
+func TestInvoices_ListWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("type") != "charge" {
+			t.Fatalf("unexpected type: %s", r.URL.Query().Get("type"))
+		} else if r.URL.Query().Get("state") != "paid" {
+			t.Fatalf("unexpected state: %s", r.URL.Query().Get("state"))
+		} else if r.URL.Query().Get("begin_time") != "2017-01-01T00:00:00Z" {
+			t.Fatalf("unexpected begin_time: %s", r.URL.Query().Get("begin_time"))
+		} else if r.URL.Query().Get("end_time") != "2017-02-01T00:00:00Z" {
+			t.Fatalf("unexpected end_time: %s", r.URL.Query().Get("end_time"))
+		} else if r.URL.Query().Get("per_page") != "50" {
+			t.Fatalf("unexpected per_page: %s", r.URL.Query().Get("per_page"))
+		} else if _, ok := r.URL.Query()["cursor"]; ok {
+			t.Fatal("unexpected cursor")
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoices type="array">
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1005">
+					<uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>
+					<state>paid</state>
+					<type>charge</type>
+					<invoice_number type="integer">1005</invoice_number>
+					<total_in_cents type="integer">1200</total_in_cents>
+					<currency>USD</currency>
+				</invoice>
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1006">
+					<uuid>5f3e2c0a6a1b4ae9a7d8b3f9f9a0c1d2</uuid>
+					<state>closed</state>
+					<type>credit</type>
+					<invoice_number type="integer">1006</invoice_number>
+					<total_in_cents type="integer">-500</total_in_cents>
+					<currency>USD</currency>
+				</invoice>
+			</invoices>`)
+	})
+
+	resp, invoices, err := client.Invoices.ListWithOptions(recurly.InvoiceListOptions{
+		Type:      recurly.InvoiceTypeCharge,
+		State:     recurly.InvoiceStatePaid,
+		BeginTime: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2017, time.February, 1, 0, 0, 0, 0, time.UTC),
+		PerPage:   50,
+	})
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected list invoices to return OK")
+	} else if len(invoices) != 2 {
+		t.Fatalf("unexpected number of invoices: %d", len(invoices))
+	} else if invoices[0].Type != recurly.InvoiceTypeCharge || invoices[0].InvoiceNumber != 1005 {
+		t.Fatalf("unexpected charge invoice: %#v", invoices[0])
+	} else if invoices[1].Type != recurly.InvoiceTypeCredit || invoices[1].TotalInCents != -500 {
+		t.Fatalf("unexpected credit invoice: %#v", invoices[1])
+	}
+}
+
+func TestInvoices_ListWithOptions_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []recurly.InvoiceListOptions{
+		{Type: "debit"},
+		{State: "closed_won"},
+	}
+
+	for i, opts := range tests {
+		if _, invoices, err := client.Invoices.ListWithOptions(opts); err == nil {
+			t.Fatalf("(%d) expected error", i)
+		} else if invoices != nil {
+			t.Fatalf("(%d) unexpected invoices: %#v", i, invoices)
+		}
+	}
+}
+
 func TestInvoices_ListAccount(t *testing.T) {
 	setup()
 	defer teardown()
@@ -255,6 +340,7 @@ func TestInvoices_ListAccount(t *testing.T) {
 				{
 					AccountCode:            "100",
 					InvoiceNumber:          1108,
+					SubscriptionUUID:       "17caaca1716f33572edc8146e0aaefde",
 					UUID:                   "626db120a84102b1809909071c701c60",
 					State:                  "invoiced",
 					Description:            "One-time Charged Fee",
@@ -443,6 +529,7 @@ func TestInvoices_Get(t *testing.T) {
 			{
 				AccountCode:            "100",
 				InvoiceNumber:          1108,
+				SubscriptionUUID:       "17caaca1716f33572edc8146e0aaefde",
 				UUID:                   "626db120a84102b1809909071c701c60",
 				State:                  "invoiced",
 				Description:            "One-time Charged Fee",
@@ -528,6 +615,113 @@ func TestInvoices_Get(t *testing.T) {
 	}
 }
 
+func TestInvoices_Decode_ManualCollection(t *testing.T) {
+	var invoice recurly.Invoice
+	if err := xml.Unmarshal([]byte(`<invoice href="https://your-subdomain.recurly.com/v2/invoices/1005">
+		<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+		<uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>
+		<state>pending</state>
+		<type>charge</type>
+		<invoice_number type="integer">1005</invoice_number>
+		<po_number>PO-1234</po_number>
+		<total_in_cents type="integer">1200</total_in_cents>
+		<currency>USD</currency>
+		<created_at type="datetime">2011-08-25T12:00:00Z</created_at>
+		<due_on type="datetime">2011-09-24T12:00:00Z</due_on>
+		<net_terms type="integer">30</net_terms>
+		<collection_method>manual</collection_method>
+		<terms_and_conditions>Payment is due within 30 days.</terms_and_conditions>
+	</invoice>`), &invoice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(invoice, recurly.Invoice{
+		XMLName:            xml.Name{Local: "invoice"},
+		AccountCode:        "1",
+		UUID:               "421f7b7d414e4c6792938e7c49d552e9",
+		State:              recurly.InvoiceStatePending,
+		Type:               recurly.InvoiceTypeCharge,
+		InvoiceNumber:      1005,
+		PONumber:           "PO-1234",
+		TotalInCents:       1200,
+		Currency:           "USD",
+		CreatedAt:          recurly.NewTime(time.Date(2011, time.August, 25, 12, 0, 0, 0, time.UTC)),
+		DueOn:              recurly.NewTime(time.Date(2011, time.September, 24, 12, 0, 0, 0, time.UTC)),
+		NetTerms:           recurly.NewInt(30),
+		CollectionMethod:   recurly.CollectionMethodManual,
+		TermsAndConditions: "Payment is due within 30 days.",
+	}) {
+		t.Fatalf("unexpected invoice: %#v", invoice)
+	}
+}
+
+func TestInvoices_Decode_InvoiceCollection(t *testing.T) {
+	var collection recurly.InvoiceCollection
+	if err := xml.Unmarshal([]byte(`<invoice_collection>
+		<charge_invoice href="">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<state>pending</state>
+			<type>charge</type>
+			<subtotal_in_cents type="integer">5500</subtotal_in_cents>
+			<tax_in_cents type="integer">440</tax_in_cents>
+			<total_in_cents type="integer">5940</total_in_cents>
+			<currency>USD</currency>
+			<line_items type="array">
+				<adjustment href="">
+					<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/4110792b3b01967d854f674b7282f542"/>
+					<description>Gold plan</description>
+					<origin>plan</origin>
+					<unit_amount_in_cents type="integer">3000</unit_amount_in_cents>
+					<quantity type="integer">1</quantity>
+					<total_in_cents type="integer">3240</total_in_cents>
+					<currency>USD</currency>
+				</adjustment>
+				<adjustment href="">
+					<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/4110792b3b01967d854f674b7282f543"/>
+					<description>Silver plan</description>
+					<origin>plan</origin>
+					<unit_amount_in_cents type="integer">2000</unit_amount_in_cents>
+					<quantity type="integer">1</quantity>
+					<total_in_cents type="integer">2160</total_in_cents>
+					<currency>USD</currency>
+				</adjustment>
+				<adjustment href="">
+					<description>Setup</description>
+					<origin>debit</origin>
+					<unit_amount_in_cents type="integer">500</unit_amount_in_cents>
+					<quantity type="integer">1</quantity>
+					<total_in_cents type="integer">540</total_in_cents>
+					<currency>USD</currency>
+				</adjustment>
+			</line_items>
+		</charge_invoice>
+		<credit_invoices type="array">
+		</credit_invoices>
+	</invoice_collection>`), &collection); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if collection.ChargeInvoice == nil {
+		t.Fatal("expected charge invoice")
+	} else if cents, currency := collection.Total(); cents != 5940 || currency != "USD" {
+		t.Fatalf("unexpected total: %d %s", cents, currency)
+	} else if collection.ChargeInvoice.TaxInCents != 440 {
+		t.Fatalf("unexpected tax: %d", collection.ChargeInvoice.TaxInCents)
+	}
+
+	var origins []string
+	for _, a := range collection.ChargeInvoice.LineItems {
+		origins = append(origins, a.SubscriptionUUID)
+	}
+	if !reflect.DeepEqual(origins, []string{
+		"4110792b3b01967d854f674b7282f542",
+		"4110792b3b01967d854f674b7282f543",
+		"",
+	}) {
+		t.Fatalf("unexpected line item subscriptions: %v", origins)
+	}
+
+	if cents, currency := (recurly.InvoiceCollection{}).Total(); cents != 0 || currency != "" {
+		t.Fatalf("unexpected total: %d %s", cents, currency)
+	}
+}
+
 func TestInvoices_Get_ErrNotFound(t *testing.T) {
 	setup()
 	defer teardown()
@@ -600,6 +794,36 @@ func TestInvoices_Get_TransactionsOrder(t *testing.T) {
 	}
 }
 
+func TestInvoices_GetByNumber(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/FR1009", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<invoice href="https://your-subdomain.recurly.com/v2/invoices/FR1009">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>
+			<state>paid</state>
+			<invoice_number_prefix>FR</invoice_number_prefix>
+			<invoice_number type="integer">1009</invoice_number>
+			<currency>EUR</currency>
+		</invoice>`)
+	})
+
+	resp, invoice, err := client.Invoices.GetByNumber("FR", 1009)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected get invoice to return OK")
+	} else if invoice.InvoiceNumberPrefix != "FR" || invoice.InvoiceNumber != 1009 {
+		t.Fatalf("unexpected invoice: %v", invoice)
+	}
+}
+
 func TestInvoices_GetPDF(t *testing.T) {
 	setup()
 	defer teardown()
@@ -710,7 +934,7 @@ func TestInvoices_Create_Params(t *testing.T) {
 			t.Fatal(err)
 		}
 		defer r.Body.Close()
-		if !bytes.Equal(b, []byte("<invoice><po_number>ABC</po_number><net_terms>30</net_terms><collection_method>COLLECTION_METHOD</collection_method><terms_and_conditions>TERMS</terms_and_conditions><customer_notes>CUSTOMER_NOTES</customer_notes><vat_reverse_charge_notes>VAT_REVERSE_CHARGE_NOTES</vat_reverse_charge_notes></invoice>")) {
+		if !bytes.Equal(b, []byte("<invoice><po_number>ABC</po_number><net_terms>30</net_terms><collection_method>manual</collection_method><terms_and_conditions>TERMS</terms_and_conditions><customer_notes>CUSTOMER_NOTES</customer_notes><vat_reverse_charge_notes>VAT_REVERSE_CHARGE_NOTES</vat_reverse_charge_notes></invoice>")) {
 			t.Fatalf("unexpected input: %s", string(b))
 		}
 		w.WriteHeader(201)
@@ -722,7 +946,7 @@ func TestInvoices_Create_Params(t *testing.T) {
 	resp, _, err := client.Invoices.Create("10", recurly.Invoice{
 		PONumber:              "ABC",
 		NetTerms:              recurly.NewInt(30),
-		CollectionMethod:      "COLLECTION_METHOD",
+		CollectionMethod:      recurly.CollectionMethodManual,
 		TermsAndConditions:    "TERMS",
 		CustomerNotes:         "CUSTOMER_NOTES",
 		VatReverseChargeNotes: "VAT_REVERSE_CHARGE_NOTES",
@@ -815,6 +1039,47 @@ func TestInvoices_MarkFailed(t *testing.T) {
 	}
 }
 
+func TestInvoices_Void(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1403/void", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><invoice><invoice_number type="integer">1403</invoice_number><state>voided</state><type>credit</type></invoice>`)
+	})
+
+	resp, invoice, err := client.Invoices.Void(1403)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected void invoice to return OK")
+	} else if invoice.State != recurly.InvoiceStateVoided || invoice.Type != recurly.InvoiceTypeCredit {
+		t.Fatalf("unexpected invoice: %#v", invoice)
+	}
+}
+
+func TestInvoices_Void_NotVoidable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1402/void", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="invoice.type" symbol="invalid">can only void credit invoices</error></errors>`)
+	})
+
+	resp, _, err := client.Invoices.Void(1402)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !resp.IsError() {
+		t.Fatal("expected void of charge invoice to return an error")
+	} else if len(resp.Errors) != 1 || resp.Errors[0].Field != "invoice.type" {
+		t.Fatalf("unexpected errors: %#v", resp.Errors)
+	}
+}
+
 func TestInvoices_RefundVoidOpenAmount(t *testing.T) {
 	setup()
 	defer teardown()
@@ -900,3 +1165,72 @@ func TestInvoices_RecordPayment(t *testing.T) {
 		t.Fatal("handler not invoked")
 	}
 }
+
+func TestInvoices_RecordPayment_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1402/transactions", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected API call")
+	})
+
+	tests := []recurly.OfflinePayment{
+		{PaymentMethod: recurly.PaymentMethodCheck, Amount: 1000},
+		{InvoiceNumber: 1402, Amount: 1000},
+		{InvoiceNumber: 1402, PaymentMethod: recurly.PaymentMethodWireTransfer},
+		{InvoiceNumber: 1402, PaymentMethod: recurly.PaymentMethodEFT, Amount: -100},
+	}
+
+	for i, tt := range tests {
+		if resp, transaction, err := client.Invoices.RecordPayment(tt); err == nil {
+			t.Fatalf("(%d): expected error", i)
+		} else if resp != nil || transaction != nil {
+			t.Fatalf("(%d): unexpected result: %v %v", i, resp, transaction)
+		}
+	}
+}
+
+func TestInvoices_ListRedemptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices/1108/redemptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+        <redemptions type="array">
+            <redemption href="https://your-subdomain.recurly.com/v2/accounts/1/redemptions/34ab5a0b4f4aaa0e7e7e7e4c0aa46f82">
+                <coupon href="https://your-subdomain.recurly.com/v2/coupons/special"/>
+                <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+                <single_use type="boolean">true</single_use>
+                <total_discounted_in_cents type="integer">500</total_discounted_in_cents>
+                <currency>USD</currency>
+                <state>inactive</state>
+                <created_at type="datetime">2011-06-27T12:34:56Z</created_at>
+            </redemption>
+        </redemptions>`)
+	})
+
+	r, redemptions, err := client.Invoices.ListRedemptions(1108, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected list redemptions to return OK")
+	}
+
+	if !reflect.DeepEqual(redemptions, []recurly.CouponRedemption{
+		{
+			CouponCode:             "special",
+			AccountCode:            "1",
+			SingleUse:              recurly.NewBool(true),
+			TotalDiscountedInCents: 500,
+			Currency:               "USD",
+			State:                  "inactive",
+			CreatedAt:              recurly.NewTime(time.Date(2011, time.June, 27, 12, 34, 56, 0, time.UTC)),
+		},
+	}) {
+		t.Fatalf("unexpected redemptions: %v", redemptions)
+	}
+}
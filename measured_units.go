@@ -0,0 +1,94 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// MeasuredUnit is the unit in which usage of a usage based add on is
+// recorded, such as API calls or gigabytes.
+// https://dev.recurly.com/docs/measured-units
+type MeasuredUnit struct {
+	XMLName     xml.Name `xml:"measured_unit"`
+	ID          int      `xml:"id,omitempty"` // Read only
+	Name        string   `xml:"name,omitempty"`
+	DisplayName string   `xml:"display_name,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	CreatedAt   NullTime `xml:"created_at,omitempty"` // Read only
+	UpdatedAt   NullTime `xml:"updated_at,omitempty"` // Read only
+}
+
+// Usage is a usage record of a usage based subscription add on.
+// https://dev.recurly.com/docs/usage-record-object
+type Usage struct {
+	XMLName            xml.Name `xml:"usage"`
+	ID                 int      `xml:"id,omitempty"` // Read only
+	Amount             int      `xml:"amount"`
+	MerchantTag        string   `xml:"merchant_tag,omitempty"`
+	MeasuredUnitID     int      `xml:"measured_unit_id,omitempty"` // Read only
+	RecordingTimestamp NullTime `xml:"recording_timestamp,omitempty"`
+	UsageTimestamp     NullTime `xml:"usage_timestamp,omitempty"`
+
+	// measuredUnit caches the unit found by ResolveMeasuredUnit.
+	measuredUnit *MeasuredUnit
+}
+
+// ResolveMeasuredUnit returns the measured unit the usage is recorded in,
+// looking it up with client.MeasuredUnits the first time it is called.
+// Later calls return the cached unit. Use ResolveMeasuredUnits to resolve
+// many records without looking up the same unit more than once.
+func (u *Usage) ResolveMeasuredUnit(client *Client) (*MeasuredUnit, error) {
+	if u.measuredUnit != nil {
+		return u.measuredUnit, nil
+	}
+
+	unit, err := getMeasuredUnit(client, u.MeasuredUnitID)
+	if err != nil {
+		return nil, err
+	}
+	u.measuredUnit = unit
+	return unit, nil
+}
+
+// ResolveMeasuredUnits resolves the measured unit of each usage record,
+// looking up each distinct unit once. The units are cached on the records as
+// with ResolveMeasuredUnit and returned by id.
+func ResolveMeasuredUnits(client *Client, usages []Usage) (map[int]*MeasuredUnit, error) {
+	units := make(map[int]*MeasuredUnit)
+	for i := range usages {
+		if unit := usages[i].measuredUnit; unit != nil {
+			units[usages[i].MeasuredUnitID] = unit
+		}
+	}
+
+	for i := range usages {
+		id := usages[i].MeasuredUnitID
+		unit, ok := units[id]
+		if !ok {
+			var err error
+			if unit, err = getMeasuredUnit(client, id); err != nil {
+				return nil, err
+			}
+			units[id] = unit
+		}
+		usages[i].measuredUnit = unit
+	}
+	return units, nil
+}
+
+// getMeasuredUnit looks up the measured unit with id, returning an error if
+// it could not be found.
+func getMeasuredUnit(client *Client, id int) (*MeasuredUnit, error) {
+	if id == 0 {
+		return nil, errors.New("recurly: usage has no measured unit")
+	}
+
+	resp, unit, err := client.MeasuredUnits.Get(id)
+	if err != nil {
+		return nil, err
+	} else if unit == nil {
+		return nil, fmt.Errorf("recurly: unable to look up measured unit %d: %s", id, resp.Status)
+	}
+	return unit, nil
+}
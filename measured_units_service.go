@@ -0,0 +1,32 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+)
+
+var _ MeasuredUnitsService = &measuredUnitsImpl{}
+
+// measuredUnitsImpl handles communication with the measured unit related
+// methods of the recurly API.
+type measuredUnitsImpl struct {
+	client *Client
+}
+
+// Get returns a measured unit by id.
+// https://dev.recurly.com/docs/lookup-a-measured-unit
+func (s *measuredUnitsImpl) Get(id int) (*Response, *MeasuredUnit, error) {
+	action := fmt.Sprintf("measured_units/%d", id)
+	req, err := s.client.newRequest("GET", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst MeasuredUnit
+	resp, err := s.client.do(req, &dst)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, err
+	}
+
+	return resp, &dst, err
+}
@@ -0,0 +1,94 @@
+package recurly_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/portofinolabs/recurly"
+)
+
+func TestMeasuredUnits_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/measured_units/394681687402874853", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<measured_unit href="https://your-subdomain.recurly.com/v2/measured_units/394681687402874853">
+			<id type="integer">394681687402874853</id>
+			<name>api_calls</name>
+			<display_name>API Calls</display_name>
+			<description>Calls to the public API</description>
+			<created_at type="datetime">2018-01-26T20:16:45Z</created_at>
+			<updated_at type="datetime">2018-01-26T20:16:45Z</updated_at>
+		</measured_unit>`)
+	})
+
+	resp, unit, err := client.MeasuredUnits.Get(394681687402874853)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected get measured unit to return OK")
+	} else if !reflect.DeepEqual(unit, &recurly.MeasuredUnit{
+		XMLName:     xml.Name{Local: "measured_unit"},
+		ID:          394681687402874853,
+		Name:        "api_calls",
+		DisplayName: "API Calls",
+		Description: "Calls to the public API",
+		CreatedAt:   recurly.NewTimeFromString("2018-01-26T20:16:45Z"),
+		UpdatedAt:   recurly.NewTimeFromString("2018-01-26T20:16:45Z"),
+	}) {
+		t.Fatalf("unexpected measured unit: %#v", unit)
+	}
+}
+
+func TestMeasuredUnits_ResolveMeasuredUnits(t *testing.T) {
+	setup()
+	defer teardown()
+
+	lookups := map[string]int{}
+	mux.HandleFunc("/v2/measured_units/", func(w http.ResponseWriter, r *http.Request) {
+		lookups[r.URL.Path]++
+		if r.URL.Path == "/v2/measured_units/3" {
+			w.WriteHeader(404)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description>Couldn't find MeasuredUnit with id = 3</description></error>`)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><measured_unit><id type="integer">%s</id><name>unit_%s</name></measured_unit>`, r.URL.Path[len("/v2/measured_units/"):], r.URL.Path[len("/v2/measured_units/"):])
+	})
+
+	usages := []recurly.Usage{
+		{Amount: 10, MeasuredUnitID: 1},
+		{Amount: 20, MeasuredUnitID: 2},
+		{Amount: 30, MeasuredUnitID: 1},
+	}
+	units, err := recurly.ResolveMeasuredUnits(client, usages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(units) != 2 || units[1].Name != "unit_1" || units[2].Name != "unit_2" {
+		t.Fatalf("unexpected units: %v", units)
+	} else if !reflect.DeepEqual(lookups, map[string]int{"/v2/measured_units/1": 1, "/v2/measured_units/2": 1}) {
+		t.Fatalf("unexpected lookups: %v", lookups)
+	}
+
+	// Units are cached on the records.
+	if unit, err := usages[2].ResolveMeasuredUnit(client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if unit != units[1] {
+		t.Fatalf("unexpected unit: %v", unit)
+	} else if lookups["/v2/measured_units/1"] != 1 {
+		t.Fatalf("unexpected lookups: %v", lookups)
+	}
+
+	missing := recurly.Usage{MeasuredUnitID: 3}
+	if unit, err := missing.ResolveMeasuredUnit(client); err == nil {
+		t.Fatalf("expected error, got unit: %v", unit)
+	}
+}
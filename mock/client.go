@@ -21,8 +21,10 @@ func NewClient(httpClient *http.Client) *recurly.Client {
 	client.Adjustments = &AdjustmentsService{}
 	client.Billing = &BillingService{}
 	client.Coupons = &CouponsService{}
+	client.Exports = &ExportsService{}
 	client.Redemptions = &RedemptionsService{}
 	client.Invoices = &InvoicesService{}
+	client.MeasuredUnits = &MeasuredUnitsService{}
 	client.Plans = &PlansService{}
 	client.AddOns = &AddOnsService{}
 	client.Subscriptions = &SubscriptionsService{}
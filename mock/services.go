@@ -2,6 +2,8 @@ package mock
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"time"
 
 	"github.com/portofinolabs/recurly"
@@ -14,6 +16,9 @@ type AccountsService struct {
 	OnList      func(params recurly.Params) (*recurly.Response, []recurly.Account, error)
 	ListInvoked bool
 
+	OnSearch      func(params recurly.Params) (*recurly.Response, []recurly.Account, error)
+	SearchInvoked bool
+
 	OnGet      func(code string) (*recurly.Response, *recurly.Account, error)
 	GetInvoked bool
 
@@ -34,6 +39,30 @@ type AccountsService struct {
 
 	OnListNotes      func(code string) (*recurly.Response, []recurly.Note, error)
 	ListNotesInvoked bool
+
+	OnListChildAccounts      func(parentCode string, params recurly.Params) (*recurly.Response, []recurly.Account, error)
+	ListChildAccountsInvoked bool
+
+	OnListTransactions      func(code string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error)
+	ListTransactionsInvoked bool
+
+	OnListInvoices      func(code string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error)
+	ListInvoicesInvoked bool
+
+	OnListRedemptions      func(code string, params recurly.Params) (*recurly.Response, []recurly.CouponRedemption, error)
+	ListRedemptionsInvoked bool
+
+	OnGetAcquisition      func(code string) (*recurly.Response, *recurly.AccountAcquisition, error)
+	GetAcquisitionInvoked bool
+
+	OnCreateAcquisition      func(code string, a recurly.AccountAcquisition) (*recurly.Response, *recurly.AccountAcquisition, error)
+	CreateAcquisitionInvoked bool
+
+	OnUpdateAcquisition      func(code string, a recurly.AccountAcquisition) (*recurly.Response, *recurly.AccountAcquisition, error)
+	UpdateAcquisitionInvoked bool
+
+	OnDeleteAcquisition      func(code string) (*recurly.Response, error)
+	DeleteAcquisitionInvoked bool
 }
 
 func (m *AccountsService) List(params recurly.Params) (*recurly.Response, []recurly.Account, error) {
@@ -41,6 +70,11 @@ func (m *AccountsService) List(params recurly.Params) (*recurly.Response, []recu
 	return m.OnList(params)
 }
 
+func (m *AccountsService) Search(params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+	m.SearchInvoked = true
+	return m.OnSearch(params)
+}
+
 func (m *AccountsService) Get(code string) (*recurly.Response, *recurly.Account, error) {
 	m.GetInvoked = true
 	return m.OnGet(code)
@@ -76,6 +110,46 @@ func (m *AccountsService) ListNotes(code string) (*recurly.Response, []recurly.N
 	return m.OnListNotes(code)
 }
 
+func (m *AccountsService) ListChildAccounts(parentCode string, params recurly.Params) (*recurly.Response, []recurly.Account, error) {
+	m.ListChildAccountsInvoked = true
+	return m.OnListChildAccounts(parentCode, params)
+}
+
+func (m *AccountsService) ListTransactions(code string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+	m.ListTransactionsInvoked = true
+	return m.OnListTransactions(code, params)
+}
+
+func (m *AccountsService) ListInvoices(code string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
+	m.ListInvoicesInvoked = true
+	return m.OnListInvoices(code, params)
+}
+
+func (m *AccountsService) ListRedemptions(code string, params recurly.Params) (*recurly.Response, []recurly.CouponRedemption, error) {
+	m.ListRedemptionsInvoked = true
+	return m.OnListRedemptions(code, params)
+}
+
+func (m *AccountsService) GetAcquisition(code string) (*recurly.Response, *recurly.AccountAcquisition, error) {
+	m.GetAcquisitionInvoked = true
+	return m.OnGetAcquisition(code)
+}
+
+func (m *AccountsService) CreateAcquisition(code string, a recurly.AccountAcquisition) (*recurly.Response, *recurly.AccountAcquisition, error) {
+	m.CreateAcquisitionInvoked = true
+	return m.OnCreateAcquisition(code, a)
+}
+
+func (m *AccountsService) UpdateAcquisition(code string, a recurly.AccountAcquisition) (*recurly.Response, *recurly.AccountAcquisition, error) {
+	m.UpdateAcquisitionInvoked = true
+	return m.OnUpdateAcquisition(code, a)
+}
+
+func (m *AccountsService) DeleteAcquisition(code string) (*recurly.Response, error) {
+	m.DeleteAcquisitionInvoked = true
+	return m.OnDeleteAcquisition(code)
+}
+
 var _ recurly.AdjustmentsService = &AdjustmentsService{}
 
 // AdjustmentsService represents the interactions available for adjustments.
@@ -89,6 +163,9 @@ type AdjustmentsService struct {
 	OnCreate      func(accountCode string, a recurly.Adjustment) (*recurly.Response, *recurly.Adjustment, error)
 	CreateInvoked bool
 
+	OnPreview      func(accountCode string, a recurly.Adjustment) (*recurly.Response, *recurly.Adjustment, error)
+	PreviewInvoked bool
+
 	OnDelete      func(uuid string) (*recurly.Response, error)
 	DeleteInvoked bool
 }
@@ -108,6 +185,11 @@ func (m *AdjustmentsService) Create(accountCode string, a recurly.Adjustment) (*
 	return m.OnCreate(accountCode, a)
 }
 
+func (m *AdjustmentsService) Preview(accountCode string, a recurly.Adjustment) (*recurly.Response, *recurly.Adjustment, error) {
+	m.PreviewInvoked = true
+	return m.OnPreview(accountCode, a)
+}
+
 func (m *AdjustmentsService) Delete(uuid string) (*recurly.Response, error) {
 	m.DeleteInvoked = true
 	return m.OnDelete(uuid)
@@ -179,6 +261,9 @@ type BillingService struct {
 
 	OnClear      func(accountCode string) (*recurly.Response, error)
 	ClearInvoked bool
+
+	OnVerify      func(accountCode string) (*recurly.Response, *recurly.Transaction, error)
+	VerifyInvoked bool
 }
 
 func (m *BillingService) Get(accountCode string) (*recurly.Response, *recurly.Billing, error) {
@@ -211,6 +296,11 @@ func (m *BillingService) Clear(accountCode string) (*recurly.Response, error) {
 	return m.OnClear(accountCode)
 }
 
+func (m *BillingService) Verify(accountCode string) (*recurly.Response, *recurly.Transaction, error) {
+	m.VerifyInvoked = true
+	return m.OnVerify(accountCode)
+}
+
 var _ recurly.CouponsService = &CouponsService{}
 
 // CouponsService represents the interactions available for coupons.
@@ -248,6 +338,43 @@ func (m *CouponsService) Delete(code string) (*recurly.Response, error) {
 	return m.OnDelete(code)
 }
 
+var _ recurly.ExportsService = &ExportsService{}
+
+// ExportsService mocks the automated exports service.
+type ExportsService struct {
+	OnListDates      func() (*recurly.Response, []string, error)
+	ListDatesInvoked bool
+
+	OnListFiles      func(date string) (*recurly.Response, []recurly.ExportFile, error)
+	ListFilesInvoked bool
+
+	OnGetFile      func(date string, fileName string) (*recurly.Response, *recurly.ExportFile, error)
+	GetFileInvoked bool
+
+	OnDownloadFile      func(date string, fileName string) (*recurly.Response, io.ReadCloser, error)
+	DownloadFileInvoked bool
+}
+
+func (m *ExportsService) ListDates() (*recurly.Response, []string, error) {
+	m.ListDatesInvoked = true
+	return m.OnListDates()
+}
+
+func (m *ExportsService) ListFiles(date string) (*recurly.Response, []recurly.ExportFile, error) {
+	m.ListFilesInvoked = true
+	return m.OnListFiles(date)
+}
+
+func (m *ExportsService) GetFile(date string, fileName string) (*recurly.Response, *recurly.ExportFile, error) {
+	m.GetFileInvoked = true
+	return m.OnGetFile(date, fileName)
+}
+
+func (m *ExportsService) DownloadFile(date string, fileName string) (*recurly.Response, io.ReadCloser, error) {
+	m.DownloadFileInvoked = true
+	return m.OnDownloadFile(date, fileName)
+}
+
 var _ recurly.InvoicesService = &InvoicesService{}
 
 // InvoicesService represents the interactions available for invoices.
@@ -255,12 +382,18 @@ type InvoicesService struct {
 	OnList      func(params recurly.Params) (*recurly.Response, []recurly.Invoice, error)
 	ListInvoked bool
 
+	OnListWithOptions      func(opts recurly.InvoiceListOptions) (*recurly.Response, []recurly.Invoice, error)
+	ListWithOptionsInvoked bool
+
 	OnListAccount      func(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error)
 	ListAccountInvoked bool
 
 	OnGet      func(invoiceNumber int) (*recurly.Response, *recurly.Invoice, error)
 	GetInvoked bool
 
+	OnGetByNumber      func(prefix string, invoiceNumber int) (*recurly.Response, *recurly.Invoice, error)
+	GetByNumberInvoked bool
+
 	OnGetPDF      func(invoiceNumber int, language string) (*recurly.Response, *bytes.Buffer, error)
 	GetPDFInvoked bool
 
@@ -279,11 +412,17 @@ type InvoicesService struct {
 	OnMarkFailed      func(invoiceNumber int) (*recurly.Response, *recurly.Invoice, error)
 	MarkFailedInvoked bool
 
+	OnVoid      func(invoiceNumber int) (*recurly.Response, *recurly.Invoice, error)
+	VoidInvoked bool
+
 	OnRefundVoidOpenAmount      func(invoiceNumber int, amountInCents int, refundApplyOrder string) (*recurly.Response, *recurly.Invoice, error)
 	RefundVoidOpenAmountInvoked bool
 
 	OnRecordPayment      func(pmt recurly.OfflinePayment) (*recurly.Response, *recurly.Transaction, error)
 	RecordPaymentInvoked bool
+
+	OnListRedemptions      func(invoiceNumber int, params recurly.Params) (*recurly.Response, []recurly.CouponRedemption, error)
+	ListRedemptionsInvoked bool
 }
 
 func (m *InvoicesService) List(params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
@@ -291,6 +430,11 @@ func (m *InvoicesService) List(params recurly.Params) (*recurly.Response, []recu
 	return m.OnList(params)
 }
 
+func (m *InvoicesService) ListWithOptions(opts recurly.InvoiceListOptions) (*recurly.Response, []recurly.Invoice, error) {
+	m.ListWithOptionsInvoked = true
+	return m.OnListWithOptions(opts)
+}
+
 func (m *InvoicesService) ListAccount(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Invoice, error) {
 	m.ListAccountInvoked = true
 	return m.OnListAccount(accountCode, params)
@@ -301,6 +445,11 @@ func (m *InvoicesService) Get(invoiceNumber int) (*recurly.Response, *recurly.In
 	return m.OnGet(invoiceNumber)
 }
 
+func (m *InvoicesService) GetByNumber(prefix string, invoiceNumber int) (*recurly.Response, *recurly.Invoice, error) {
+	m.GetByNumberInvoked = true
+	return m.OnGetByNumber(prefix, invoiceNumber)
+}
+
 func (m *InvoicesService) GetPDF(invoiceNumber int, language string) (*recurly.Response, *bytes.Buffer, error) {
 	m.GetPDFInvoked = true
 	return m.OnGetPDF(invoiceNumber, language)
@@ -331,6 +480,11 @@ func (m *InvoicesService) MarkFailed(invoiceNumber int) (*recurly.Response, *rec
 	return m.OnMarkFailed(invoiceNumber)
 }
 
+func (m *InvoicesService) Void(invoiceNumber int) (*recurly.Response, *recurly.Invoice, error) {
+	m.VoidInvoked = true
+	return m.OnVoid(invoiceNumber)
+}
+
 func (m *InvoicesService) RefundVoidOpenAmount(invoiceNumber int, amountInCents int, refundApplyOrder string) (*recurly.Response, *recurly.Invoice, error) {
 	m.RefundVoidOpenAmountInvoked = true
 	return m.OnRefundVoidOpenAmount(invoiceNumber, amountInCents, refundApplyOrder)
@@ -341,6 +495,25 @@ func (m *InvoicesService) RecordPayment(pmt recurly.OfflinePayment) (*recurly.Re
 	return m.OnRecordPayment(pmt)
 }
 
+func (m *InvoicesService) ListRedemptions(invoiceNumber int, params recurly.Params) (*recurly.Response, []recurly.CouponRedemption, error) {
+	m.ListRedemptionsInvoked = true
+	return m.OnListRedemptions(invoiceNumber, params)
+}
+
+var _ recurly.MeasuredUnitsService = &MeasuredUnitsService{}
+
+// MeasuredUnitsService represents the interactions available for measured
+// units.
+type MeasuredUnitsService struct {
+	OnGet      func(id int) (*recurly.Response, *recurly.MeasuredUnit, error)
+	GetInvoked bool
+}
+
+func (m *MeasuredUnitsService) Get(id int) (*recurly.Response, *recurly.MeasuredUnit, error) {
+	m.GetInvoked = true
+	return m.OnGet(id)
+}
+
 // PlansService represents the interactions available for plans.
 type PlansService struct {
 	OnList      func(params recurly.Params) (*recurly.Response, []recurly.Plan, error)
@@ -349,6 +522,9 @@ type PlansService struct {
 	OnGet      func(code string) (*recurly.Response, *recurly.Plan, error)
 	GetInvoked bool
 
+	OnGetWithAddOns      func(code string) (*recurly.Response, *recurly.Plan, error)
+	GetWithAddOnsInvoked bool
+
 	OnCreate      func(p recurly.Plan) (*recurly.Response, *recurly.Plan, error)
 	CreateInvoked bool
 
@@ -369,6 +545,11 @@ func (m *PlansService) Get(code string) (*recurly.Response, *recurly.Plan, error
 	return m.OnGet(code)
 }
 
+func (m *PlansService) GetWithAddOns(code string) (*recurly.Response, *recurly.Plan, error) {
+	m.GetWithAddOnsInvoked = true
+	return m.OnGetWithAddOns(code)
+}
+
 func (m *PlansService) Create(p recurly.Plan) (*recurly.Response, *recurly.Plan, error) {
 	m.CreateInvoked = true
 	return m.OnCreate(p)
@@ -431,11 +612,23 @@ type TransactionsService struct {
 	OnListAccount      func(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error)
 	ListAccountInvoked bool
 
+	OnListForSubscription      func(subUUID string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error)
+	ListForSubscriptionInvoked bool
+
 	OnGet      func(uuid string) (*recurly.Response, *recurly.Transaction, error)
 	GetInvoked bool
 
 	OnCreate      func(trans recurly.Transaction) (*recurly.Response, *recurly.Transaction, error)
 	CreateInvoked bool
+
+	OnCreateForAccount      func(accountCode string, t recurly.Transaction) (*recurly.Response, *recurly.Transaction, error)
+	CreateForAccountInvoked bool
+
+	OnCapture      func(uuid string, amountInCents int) (*recurly.Response, *recurly.Transaction, error)
+	CaptureInvoked bool
+
+	OnCancel      func(uuid string) (*recurly.Response, *recurly.Transaction, error)
+	CancelInvoked bool
 }
 
 func (m *TransactionsService) List(params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
@@ -448,6 +641,11 @@ func (m *TransactionsService) ListAccount(accountCode string, params recurly.Par
 	return m.OnListAccount(accountCode, params)
 }
 
+func (m *TransactionsService) ListForSubscription(subUUID string, params recurly.Params) (*recurly.Response, []recurly.Transaction, error) {
+	m.ListForSubscriptionInvoked = true
+	return m.OnListForSubscription(subUUID, params)
+}
+
 func (m *TransactionsService) Get(uuid string) (*recurly.Response, *recurly.Transaction, error) {
 	m.GetInvoked = true
 	return m.OnGet(uuid)
@@ -458,6 +656,21 @@ func (m *TransactionsService) Create(t recurly.Transaction) (*recurly.Response,
 	return m.OnCreate(t)
 }
 
+func (m *TransactionsService) CreateForAccount(accountCode string, t recurly.Transaction) (*recurly.Response, *recurly.Transaction, error) {
+	m.CreateForAccountInvoked = true
+	return m.OnCreateForAccount(accountCode, t)
+}
+
+func (m *TransactionsService) Capture(uuid string, amountInCents int) (*recurly.Response, *recurly.Transaction, error) {
+	m.CaptureInvoked = true
+	return m.OnCapture(uuid, amountInCents)
+}
+
+func (m *TransactionsService) Cancel(uuid string) (*recurly.Response, *recurly.Transaction, error) {
+	m.CancelInvoked = true
+	return m.OnCancel(uuid)
+}
+
 var _ recurly.SubscriptionsService = &SubscriptionsService{}
 
 // SubscriptionService mocks the subscription service.
@@ -465,33 +678,69 @@ type SubscriptionsService struct {
 	OnList      func(params recurly.Params) (*recurly.Response, []recurly.Subscription, error)
 	ListInvoked bool
 
+	OnStream      func(ctx context.Context, params recurly.Params) (<-chan recurly.Subscription, <-chan error)
+	StreamInvoked bool
+
+	OnUpcomingRenewals      func(ctx context.Context, within time.Duration) ([]recurly.Subscription, error)
+	UpcomingRenewalsInvoked bool
+
+	OnListWithInvoices      func(ctx context.Context, o recurly.ListWithInvoicesOptions) (*recurly.Response, []recurly.SubscriptionWithInvoice, error)
+	ListWithInvoicesInvoked bool
+
 	OnListAccount      func(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Subscription, error)
 	ListAccountInvoked bool
 
 	OnGet      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
 	GetInvoked bool
 
+	OnGetWithAddOnNames      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
+	GetWithAddOnNamesInvoked bool
+
 	OnCreate      func(sub recurly.NewSubscription) (*recurly.Response, *recurly.NewSubscriptionResponse, error)
 	CreateInvoked bool
 
-	OnPreview      func(sub recurly.NewSubscription) (*recurly.Response, *recurly.Subscription, error)
+	OnCreateForAccount      func(accountCode string, sub recurly.NewSubscription) (*recurly.Response, *recurly.NewSubscriptionResponse, error)
+	CreateForAccountInvoked bool
+
+	OnCreateWithTransaction      func(sub recurly.NewSubscription) (*recurly.Response, *recurly.NewSubscriptionResponse, error)
+	CreateWithTransactionInvoked bool
+
+	OnPreview      func(sub recurly.NewSubscription) (*recurly.Response, *recurly.SubscriptionPreview, error)
 	PreviewInvoked bool
 
 	OnUpdate      func(uuid string, sub recurly.UpdateSubscription) (*recurly.Response, *recurly.Subscription, error)
 	UpdateInvoked bool
 
+	OnIncrementAddOn      func(uuid string, addOnCode string, delta int) (*recurly.Response, *recurly.Subscription, error)
+	IncrementAddOnInvoked bool
+
 	OnUpdateNotes      func(uuid string, n recurly.SubscriptionNotes) (*recurly.Response, *recurly.Subscription, error)
 	UpdateNotesInvoked bool
 
 	OnPreviewChange      func(uuid string, sub recurly.UpdateSubscription) (*recurly.Response, *recurly.Subscription, error)
 	PreviewChangeInvoked bool
 
+	OnPreviewChangeDetails      func(uuid string, sub recurly.UpdateSubscription) (*recurly.Response, *recurly.SubscriptionChangePreview, error)
+	PreviewChangeDetailsInvoked bool
+
+	OnPreviewChangeWithCache      func(uuid string, sub recurly.UpdateSubscription, cache recurly.PreviewCache) (*recurly.Response, *recurly.Subscription, error)
+	PreviewChangeWithCacheInvoked bool
+
+	OnPreviewRenewal      func(uuid string) (*recurly.Response, *recurly.InvoiceCollection, error)
+	PreviewRenewalInvoked bool
+
 	OnCancel      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
 	CancelInvoked bool
 
+	OnCancelIfActive      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
+	CancelIfActiveInvoked bool
+
 	OnReactivate      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
 	ReactivateInvoked bool
 
+	OnReactivateWithOptions      func(uuid string, o recurly.ReactivateOptions) (*recurly.Response, *recurly.Subscription, error)
+	ReactivateWithOptionsInvoked bool
+
 	OnTerminateWithPartialRefund      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
 	TerminateWithPartialRefundInvoked bool
 
@@ -501,76 +750,144 @@ type SubscriptionsService struct {
 	OnTerminateWithoutRefund      func(uuid string) (*recurly.Response, *recurly.Subscription, error)
 	TerminateWithoutRefundInvoked bool
 
+	OnTerminateWithResult      func(uuid string, refundType string) (*recurly.Response, *recurly.TerminateResult, error)
+	TerminateWithResultInvoked bool
+
 	OnPostpone      func(uuid string, dt time.Time, bulk bool) (*recurly.Response, *recurly.Subscription, error)
 	PostponeInvoked bool
 }
 
-func (m *SubscriptionsService) List(params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+func (m *SubscriptionsService) List(params recurly.Params, opts ...recurly.RequestOption) (*recurly.Response, []recurly.Subscription, error) {
 	m.ListInvoked = true
 	return m.OnList(params)
 }
 
-func (m *SubscriptionsService) ListAccount(accountCode string, params recurly.Params) (*recurly.Response, []recurly.Subscription, error) {
+func (m *SubscriptionsService) Stream(ctx context.Context, params recurly.Params, opts ...recurly.RequestOption) (<-chan recurly.Subscription, <-chan error) {
+	m.StreamInvoked = true
+	return m.OnStream(ctx, params)
+}
+
+func (m *SubscriptionsService) UpcomingRenewals(ctx context.Context, within time.Duration, opts ...recurly.RequestOption) ([]recurly.Subscription, error) {
+	m.UpcomingRenewalsInvoked = true
+	return m.OnUpcomingRenewals(ctx, within)
+}
+
+func (m *SubscriptionsService) ListWithInvoices(ctx context.Context, o recurly.ListWithInvoicesOptions, opts ...recurly.RequestOption) (*recurly.Response, []recurly.SubscriptionWithInvoice, error) {
+	m.ListWithInvoicesInvoked = true
+	return m.OnListWithInvoices(ctx, o)
+}
+
+func (m *SubscriptionsService) ListAccount(accountCode string, params recurly.Params, opts ...recurly.RequestOption) (*recurly.Response, []recurly.Subscription, error) {
 	m.ListAccountInvoked = true
 	return m.OnListAccount(accountCode, params)
 }
 
-func (m *SubscriptionsService) Get(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) Get(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.GetInvoked = true
 	return m.OnGet(uuid)
 }
 
-func (m *SubscriptionsService) Create(sub recurly.NewSubscription) (*recurly.Response, *recurly.NewSubscriptionResponse, error) {
+func (m *SubscriptionsService) GetWithAddOnNames(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
+	m.GetWithAddOnNamesInvoked = true
+	return m.OnGetWithAddOnNames(uuid)
+}
+
+func (m *SubscriptionsService) Create(sub recurly.NewSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.NewSubscriptionResponse, error) {
 	m.CreateInvoked = true
 	return m.OnCreate(sub)
 }
 
-func (m *SubscriptionsService) Preview(sub recurly.NewSubscription) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) CreateForAccount(accountCode string, sub recurly.NewSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.NewSubscriptionResponse, error) {
+	m.CreateForAccountInvoked = true
+	return m.OnCreateForAccount(accountCode, sub)
+}
+
+func (m *SubscriptionsService) CreateWithTransaction(sub recurly.NewSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.NewSubscriptionResponse, error) {
+	m.CreateWithTransactionInvoked = true
+	return m.OnCreateWithTransaction(sub)
+}
+
+func (m *SubscriptionsService) Preview(sub recurly.NewSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.SubscriptionPreview, error) {
 	m.PreviewInvoked = true
 	return m.OnPreview(sub)
 }
 
-func (m *SubscriptionsService) Update(uuid string, sub recurly.UpdateSubscription) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) Update(uuid string, sub recurly.UpdateSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.UpdateInvoked = true
 	return m.OnUpdate(uuid, sub)
 }
 
-func (m *SubscriptionsService) UpdateNotes(uuid string, n recurly.SubscriptionNotes) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) IncrementAddOn(uuid string, addOnCode string, delta int, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
+	m.IncrementAddOnInvoked = true
+	return m.OnIncrementAddOn(uuid, addOnCode, delta)
+}
+
+func (m *SubscriptionsService) UpdateNotes(uuid string, n recurly.SubscriptionNotes, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.UpdateNotesInvoked = true
 	return m.OnUpdateNotes(uuid, n)
 }
 
-func (m *SubscriptionsService) PreviewChange(uuid string, sub recurly.UpdateSubscription) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) PreviewChange(uuid string, sub recurly.UpdateSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.PreviewChangeInvoked = true
 	return m.OnPreviewChange(uuid, sub)
 }
 
-func (m *SubscriptionsService) Cancel(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) PreviewChangeDetails(uuid string, sub recurly.UpdateSubscription, opts ...recurly.RequestOption) (*recurly.Response, *recurly.SubscriptionChangePreview, error) {
+	m.PreviewChangeDetailsInvoked = true
+	return m.OnPreviewChangeDetails(uuid, sub)
+}
+
+func (m *SubscriptionsService) PreviewChangeWithCache(uuid string, sub recurly.UpdateSubscription, cache recurly.PreviewCache, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
+	m.PreviewChangeWithCacheInvoked = true
+	return m.OnPreviewChangeWithCache(uuid, sub, cache)
+}
+
+func (m *SubscriptionsService) PreviewRenewal(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.InvoiceCollection, error) {
+	m.PreviewRenewalInvoked = true
+	return m.OnPreviewRenewal(uuid)
+}
+
+func (m *SubscriptionsService) Cancel(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.CancelInvoked = true
 	return m.OnCancel(uuid)
 }
 
-func (m *SubscriptionsService) Reactivate(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) CancelIfActive(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
+	m.CancelIfActiveInvoked = true
+	return m.OnCancelIfActive(uuid)
+}
+
+func (m *SubscriptionsService) Reactivate(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.ReactivateInvoked = true
 	return m.OnReactivate(uuid)
 }
 
-func (m *SubscriptionsService) TerminateWithPartialRefund(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) ReactivateWithOptions(uuid string, o recurly.ReactivateOptions, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
+	m.ReactivateWithOptionsInvoked = true
+	return m.OnReactivateWithOptions(uuid, o)
+}
+
+func (m *SubscriptionsService) TerminateWithPartialRefund(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.TerminateWithPartialRefundInvoked = true
 	return m.OnTerminateWithPartialRefund(uuid)
 }
 
-func (m *SubscriptionsService) TerminateWithFullRefund(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) TerminateWithFullRefund(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.TerminateWithFullRefundInvoked = true
 	return m.OnTerminateWithFullRefund(uuid)
 }
 
-func (m *SubscriptionsService) TerminateWithoutRefund(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) TerminateWithoutRefund(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.TerminateWithoutRefundInvoked = true
 	return m.OnTerminateWithoutRefund(uuid)
 }
 
-func (m *SubscriptionsService) Postpone(uuid string, dt time.Time, bulk bool) (*recurly.Response, *recurly.Subscription, error) {
+func (m *SubscriptionsService) TerminateWithResult(uuid string, refundType string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.TerminateResult, error) {
+	m.TerminateWithResultInvoked = true
+	return m.OnTerminateWithResult(uuid, refundType)
+}
+
+func (m *SubscriptionsService) Postpone(uuid string, dt time.Time, bulk bool, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error) {
 	m.PostponeInvoked = true
 	return m.OnPostpone(uuid, dt, bulk)
 }
@@ -0,0 +1,54 @@
+package recurly
+
+import "time"
+
+// NewParams returns empty Params for building list parameters with the
+// chainable methods below, such as:
+//
+//	recurly.NewParams().PerPage(100).State("active").Sort("created_at", "desc")
+func NewParams() Params {
+	return Params{}
+}
+
+// Set sets the parameter key to value and returns p.
+func (p Params) Set(key string, value interface{}) Params {
+	p[key] = value
+	return p
+}
+
+// PerPage sets the number of records returned per page and returns p.
+func (p Params) PerPage(n int) Params {
+	return p.Set("per_page", n)
+}
+
+// Cursor sets the cursor of the page to return, as returned by
+// Response.Next or Response.Prev, and returns p.
+func (p Params) Cursor(cursor string) Params {
+	return p.Set("cursor", cursor)
+}
+
+// State filters records by state and returns p.
+func (p Params) State(state string) Params {
+	return p.Set("state", state)
+}
+
+// Sort sorts records by field, such as "created_at" or "updated_at", in the
+// given order, "asc" or "desc", and returns p. The order is not sent if it is
+// empty, in which case Recurly's default applies.
+func (p Params) Sort(field string, order string) Params {
+	p.Set("sort", field)
+	if order != "" {
+		p.Set("order", order)
+	}
+	return p
+}
+
+// BeginTime filters records to those on or after t and returns p.
+func (p Params) BeginTime(t time.Time) Params {
+	return p.Set("begin_time", t.UTC().Format(DateTimeFormat))
+}
+
+// EndTime filters records to those before t and returns p.
+func (p Params) EndTime(t time.Time) Params {
+	return p.Set("end_time", t.UTC().Format(DateTimeFormat))
+}
@@ -0,0 +1,45 @@
+package recurly_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/portofinolabs/recurly"
+)
+
+func TestParams_Builder(t *testing.T) {
+	begin := time.Date(2017, time.September, 1, 9, 30, 0, 0, time.FixedZone("EDT", -4*60*60))
+	end := time.Date(2017, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		given    recurly.Params
+		expected recurly.Params
+	}{
+		{given: recurly.NewParams(), expected: recurly.Params{}},
+		{given: recurly.NewParams().PerPage(100), expected: recurly.Params{"per_page": 100}},
+		{given: recurly.NewParams().Cursor("1972702718353176814:A1465932489"), expected: recurly.Params{"cursor": "1972702718353176814:A1465932489"}},
+		{given: recurly.NewParams().State("active"), expected: recurly.Params{"state": "active"}},
+		{given: recurly.NewParams().Sort("created_at", "desc"), expected: recurly.Params{"sort": "created_at", "order": "desc"}},
+		{given: recurly.NewParams().Sort("updated_at", ""), expected: recurly.Params{"sort": "updated_at"}},
+		{given: recurly.NewParams().BeginTime(begin), expected: recurly.Params{"begin_time": "2017-09-01T13:30:00Z"}},
+		{given: recurly.NewParams().EndTime(end), expected: recurly.Params{"end_time": "2017-10-01T00:00:00Z"}},
+		{given: recurly.NewParams().Set("type", "charge"), expected: recurly.Params{"type": "charge"}},
+		{
+			given: recurly.NewParams().PerPage(50).State("past_due").Sort("created_at", "asc").BeginTime(begin),
+			expected: recurly.Params{
+				"per_page":   50,
+				"state":      "past_due",
+				"sort":       "created_at",
+				"order":      "asc",
+				"begin_time": "2017-09-01T13:30:00Z",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		if !reflect.DeepEqual(tt.given, tt.expected) {
+			t.Fatalf("(%d) unexpected params: %#v", i, tt.given)
+		}
+	}
+}
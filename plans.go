@@ -25,6 +25,30 @@ type Plan struct {
 	CreatedAt                NullTime   `xml:"created_at,omitempty"`
 	TaxExempt                NullBool   `xml:"tax_exempt,omitempty"`
 	TaxCode                  string     `xml:"tax_code,omitempty"`
+	DunningCampaignID        string     `xml:"dunning_campaign_id,omitempty"`
 	UnitAmountInCents        UnitAmount `xml:"unit_amount_in_cents"`
 	SetupFeeInCents          UnitAmount `xml:"setup_fee_in_cents,omitempty"`
+
+	// AddOns is populated when the response includes the plan's add ons
+	// inline, or by PlansService.GetWithAddOns. It is never sent on create
+	// or update.
+	AddOns []AddOn `xml:"-"` // Read only
+}
+
+// UnmarshalXML unmarshals plans, including any add ons returned inline.
+func (p *Plan) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type planAlias Plan
+	var v struct {
+		XMLName xml.Name `xml:"plan"`
+		planAlias
+		AddOns []AddOn `xml:"add_ons>add_on"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*p = Plan(v.planAlias)
+	p.XMLName = v.XMLName
+	p.AddOns = v.AddOns
+	return nil
 }
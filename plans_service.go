@@ -1,7 +1,6 @@
 package recurly
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 )
@@ -22,18 +21,17 @@ func (s *plansImpl) List(params Params) (*Response, []Plan, error) {
 		return nil, nil, err
 	}
 
-	var p struct {
-		XMLName xml.Name `xml:"plans"`
-		Plans   []Plan   `xml:"plan"`
-	}
-	resp, err := s.client.do(req, &p)
-
-	return resp, p.Plans, err
+	return doList[Plan](s.client, req, "plans", "plan")
 }
 
 // Get will lookup a specific plan by code.
 // https://docs.recurly.com/api/plans#lookup-plan
 func (s *plansImpl) Get(code string) (*Response, *Plan, error) {
+	return s.get(code)
+}
+
+// get looks up a plan by code, applying opts to the request.
+func (s *plansImpl) get(code string, opts ...RequestOption) (*Response, *Plan, error) {
 	action := fmt.Sprintf("plans/%s", code)
 	req, err := s.client.newRequest("GET", action, nil, nil)
 	if err != nil {
@@ -41,7 +39,7 @@ func (s *plansImpl) Get(code string) (*Response, *Plan, error) {
 	}
 
 	var dst Plan
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 	if err != nil || resp.StatusCode >= http.StatusBadRequest {
 		return resp, nil, err
 	}
@@ -49,6 +47,40 @@ func (s *plansImpl) Get(code string) (*Response, *Plan, error) {
 	return resp, &dst, err
 }
 
+// GetWithAddOns will lookup a specific plan by code along with all of its
+// add ons. If the plan response doesn't include the add ons inline, they are
+// listed page by page and set on the returned plan's AddOns.
+func (s *plansImpl) GetWithAddOns(code string) (*Response, *Plan, error) {
+	return s.getWithAddOns(code)
+}
+
+// getWithAddOns is GetWithAddOns, applying opts to each request.
+func (s *plansImpl) getWithAddOns(code string, opts ...RequestOption) (*Response, *Plan, error) {
+	resp, plan, err := s.get(code, opts...)
+	if err != nil || plan == nil || plan.AddOns != nil {
+		return resp, plan, err
+	}
+
+	addOnsService := &addOnsImpl{client: s.client}
+	params := Params{"per_page": 200}
+	for {
+		var addOns []AddOn
+		resp, addOns, err = addOnsService.list(code, params, opts...)
+		if err != nil || resp.IsError() {
+			return resp, nil, err
+		}
+		plan.AddOns = append(plan.AddOns, addOns...)
+
+		cursor := resp.Next()
+		if cursor == "" {
+			break
+		}
+		params = Params{"per_page": 200, "cursor": cursor}
+	}
+
+	return resp, plan, nil
+}
+
 // Create will create a new subscription plan.
 // https://docs.recurly.com/api/plans#create-plan
 func (s *plansImpl) Create(p Plan) (*Response, *Plan, error) {
@@ -40,6 +40,7 @@ func TestPlans_Encoding(t *testing.T) {
 		{v: recurly.Plan{Name: "Gold plan", UnitAmountInCents: recurly.UnitAmount{USD: 1500}, TaxExempt: recurly.NewBool(true)}, expected: "<plan><name>Gold plan</name><tax_exempt>true</tax_exempt><unit_amount_in_cents><USD>1500</USD></unit_amount_in_cents></plan>"},
 		{v: recurly.Plan{Name: "Gold plan", UnitAmountInCents: recurly.UnitAmount{USD: 1500}, TaxExempt: recurly.NewBool(false)}, expected: "<plan><name>Gold plan</name><tax_exempt>false</tax_exempt><unit_amount_in_cents><USD>1500</USD></unit_amount_in_cents></plan>"},
 		{v: recurly.Plan{Name: "Gold plan", UnitAmountInCents: recurly.UnitAmount{USD: 1500}, TaxCode: "physical"}, expected: "<plan><name>Gold plan</name><tax_code>physical</tax_code><unit_amount_in_cents><USD>1500</USD></unit_amount_in_cents></plan>"},
+		{v: recurly.Plan{Name: "Gold plan", UnitAmountInCents: recurly.UnitAmount{USD: 1500}, DunningCampaignID: "ocd3oqf4nexm"}, expected: "<plan><name>Gold plan</name><dunning_campaign_id>ocd3oqf4nexm</dunning_campaign_id><unit_amount_in_cents><USD>1500</USD></unit_amount_in_cents></plan>"},
 	}
 
 	for _, tt := range tests {
@@ -234,6 +235,98 @@ func TestPlans_Get_ErrNotFound(t *testing.T) {
 	}
 }
 
+func TestPlans_Get_AddOns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/plans/gold", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+			<add_ons type="array">
+				<add_on>
+					<add_on_code>extra_users</add_on_code>
+					<name>Extra Users</name>
+					<unit_amount_in_cents>
+						<USD type="integer">1000</USD>
+					</unit_amount_in_cents>
+				</add_on>
+			</add_ons>
+			<plan_code>gold</plan_code>
+			<name>Gold plan</name>
+		</plan>`)
+	})
+
+	_, plan, err := client.Plans.Get("gold")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(plan.AddOns, []recurly.AddOn{{
+		XMLName:           xml.Name{Local: "add_on"},
+		Code:              "extra_users",
+		Name:              "Extra Users",
+		UnitAmountInCents: recurly.UnitAmount{USD: 1000},
+	}}) {
+		t.Fatalf("unexpected add ons: %#v", plan.AddOns)
+	}
+}
+
+func TestPlans_GetWithAddOns(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/plans/gold", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+			<add_ons href="https://your-subdomain.recurly.com/v2/plans/gold/add_ons"/>
+			<plan_code>gold</plan_code>
+			<name>Gold plan</name>
+		</plan>`)
+	})
+
+	mux.HandleFunc("/v2/plans/gold/add_ons", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/plans/gold/add_ons?cursor=1304958672>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<add_ons type="array">
+				<add_on>
+					<add_on_code>extra_users</add_on_code>
+				</add_on>
+			</add_ons>`)
+		case "1304958672":
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<add_ons type="array">
+				<add_on>
+					<add_on_code>support</add_on_code>
+				</add_on>
+			</add_ons>`)
+		default:
+			t.Fatalf("unexpected cursor: %s", r.URL.Query().Get("cursor"))
+		}
+	})
+
+	r, plan, err := client.Plans.GetWithAddOns("gold")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected get plan with add ons to return OK")
+	} else if plan.Code != "gold" || plan.Name != "Gold plan" {
+		t.Fatalf("unexpected plan: %#v", plan)
+	} else if !reflect.DeepEqual(plan.AddOns, []recurly.AddOn{
+		{XMLName: xml.Name{Local: "add_on"}, Code: "extra_users"},
+		{XMLName: xml.Name{Local: "add_on"}, Code: "support"},
+	}) {
+		t.Fatalf("unexpected add ons: %#v", plan.AddOns)
+	}
+}
+
 func TestPlans_Create(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,56 @@
+package recurly
+
+// RoundingMode determines how fractional cents are rounded by Prorate.
+type RoundingMode int
+
+// Rounding modes.
+const (
+	// RoundHalfEven rounds half cents to the nearest even cent. This is the
+	// rounding Recurly uses when prorating charges and credits.
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds half cents away from zero.
+	RoundHalfUp
+
+	// RoundDown truncates fractional cents toward zero.
+	RoundDown
+
+	// RoundUp rounds any fractional cent away from zero.
+	RoundUp
+)
+
+// Prorate returns the portion of amountInCents for daysRemaining out of
+// daysInPeriod, rounded using mode. It can be used to estimate prorated
+// charges before requesting a preview from Recurly. Negative amounts, such
+// as credits, are rounded symmetrically. Prorate returns 0 if daysInPeriod
+// is not positive.
+func Prorate(amountInCents, daysRemaining, daysInPeriod int, mode RoundingMode) int {
+	if daysInPeriod <= 0 {
+		return 0
+	}
+
+	n := int64(amountInCents) * int64(daysRemaining)
+	sign := int64(1)
+	if n < 0 {
+		sign, n = -1, -n
+	}
+
+	d := int64(daysInPeriod)
+	q, r := n/d, n%d
+	switch mode {
+	case RoundHalfEven:
+		if 2*r > d || (2*r == d && q%2 == 1) {
+			q++
+		}
+	case RoundHalfUp:
+		if 2*r >= d {
+			q++
+		}
+	case RoundUp:
+		if r > 0 {
+			q++
+		}
+	}
+
+	return int(sign * q)
+}
@@ -0,0 +1,54 @@
+package recurly_test
+
+import (
+	"testing"
+
+	"github.com/portofinolabs/recurly"
+)
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		amount    int
+		remaining int
+		days      int
+		mode      recurly.RoundingMode
+		expected  int
+	}{
+		// Whole cents are unaffected by the rounding mode.
+		{amount: 1000, remaining: 15, days: 30, mode: recurly.RoundHalfEven, expected: 500},
+		{amount: 1000, remaining: 30, days: 30, mode: recurly.RoundHalfEven, expected: 1000},
+		{amount: 1000, remaining: 0, days: 30, mode: recurly.RoundHalfEven, expected: 0},
+
+		// $10.00 plan with 10 of 30 days remaining is 333.33 cents.
+		{amount: 1000, remaining: 10, days: 30, mode: recurly.RoundHalfEven, expected: 333},
+		{amount: 1000, remaining: 10, days: 30, mode: recurly.RoundUp, expected: 334},
+
+		// $20.00 plan with 20 of 30 days remaining is 1333.33 cents.
+		{amount: 2000, remaining: 20, days: 30, mode: recurly.RoundHalfEven, expected: 1333},
+
+		// $29.99 plan with 17 of 31 days remaining is 1644.61 cents.
+		{amount: 2999, remaining: 17, days: 31, mode: recurly.RoundHalfEven, expected: 1645},
+		{amount: 2999, remaining: 17, days: 31, mode: recurly.RoundDown, expected: 1644},
+
+		// Half cents round to the nearest even cent.
+		{amount: 25, remaining: 1, days: 2, mode: recurly.RoundHalfEven, expected: 12},
+		{amount: 35, remaining: 1, days: 2, mode: recurly.RoundHalfEven, expected: 18},
+		{amount: 25, remaining: 1, days: 2, mode: recurly.RoundHalfUp, expected: 13},
+		{amount: 25, remaining: 1, days: 2, mode: recurly.RoundDown, expected: 12},
+		{amount: 25, remaining: 1, days: 2, mode: recurly.RoundUp, expected: 13},
+
+		// Credits round symmetrically.
+		{amount: -25, remaining: 1, days: 2, mode: recurly.RoundHalfEven, expected: -12},
+		{amount: -25, remaining: 1, days: 2, mode: recurly.RoundHalfUp, expected: -13},
+		{amount: -1000, remaining: 10, days: 30, mode: recurly.RoundHalfEven, expected: -333},
+
+		// Invalid periods.
+		{amount: 1000, remaining: 10, days: 0, mode: recurly.RoundHalfEven, expected: 0},
+	}
+
+	for i, tt := range tests {
+		if given := recurly.Prorate(tt.amount, tt.remaining, tt.days, tt.mode); given != tt.expected {
+			t.Fatalf("(%d) unexpected value: %d", i, given)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package recurlytest
+
+// Fixtures mirror responses returned by the Recurly API and can be passed
+// to Server.HandleXML.
+const (
+	// SubscriptionXML is an active subscription with the UUID
+	// 44f83d7cba354d5b84812419f923ea96 on account 1.
+	SubscriptionXML = `<?xml version="1.0" encoding="UTF-8"?>
+<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+	<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+	<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+	<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+		<plan_code>gold</plan_code>
+		<name>Gold plan</name>
+	</plan>
+	<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+	<state>active</state>
+	<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
+	<currency>EUR</currency>
+	<quantity type="integer">1</quantity>
+	<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
+	<canceled_at nil="nil"></canceled_at>
+	<expires_at nil="nil"></expires_at>
+	<current_period_started_at type="datetime">2011-06-27T07:00:00Z</current_period_started_at>
+	<current_period_ends_at type="datetime">2011-07-27T07:00:00Z</current_period_ends_at>
+	<trial_started_at nil="nil"></trial_started_at>
+	<trial_ends_at nil="nil"></trial_ends_at>
+	<tax_in_cents type="integer">72</tax_in_cents>
+	<tax_type>usst</tax_type>
+	<tax_region>CA</tax_region>
+	<tax_rate type="float">0.0875</tax_rate>
+	<po_number nil="nil"></po_number>
+	<net_terms type="integer">0</net_terms>
+	<subscription_add_ons type="array">
+	</subscription_add_ons>
+</subscription>`
+
+	// InvoiceXML is an open invoice with the invoice number 1005 on
+	// account 1.
+	InvoiceXML = `<?xml version="1.0" encoding="UTF-8"?>
+<invoice href="https://your-subdomain.recurly.com/v2/invoices/1005">
+	<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+	<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96"/>
+	<uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>
+	<state>open</state>
+	<invoice_number_prefix></invoice_number_prefix>
+	<invoice_number type="integer">1005</invoice_number>
+	<po_number nil="nil"></po_number>
+	<vat_number nil="nil"></vat_number>
+	<subtotal_in_cents type="integer">2000</subtotal_in_cents>
+	<tax_in_cents type="integer">180</tax_in_cents>
+	<total_in_cents type="integer">2180</total_in_cents>
+	<currency>USD</currency>
+	<created_at type="datetime">2011-08-25T12:00:00Z</created_at>
+	<closed_at nil="nil"></closed_at>
+	<tax_type>usst</tax_type>
+	<tax_region>CA</tax_region>
+	<tax_rate type="float">0.0875</tax_rate>
+	<net_terms type="integer">0</net_terms>
+	<collection_method>automatic</collection_method>
+	<line_items type="array">
+		<adjustment href="https://your-subdomain.recurly.com/v2/adjustments/626db120a84102b1809909071c701c60" type="charge">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<invoice href="https://your-subdomain.recurly.com/v2/invoices/1005"/>
+			<uuid>626db120a84102b1809909071c701c60</uuid>
+			<state>invoiced</state>
+			<description>One-time Charged Fee</description>
+			<product_code>basic</product_code>
+			<origin>debit</origin>
+			<unit_amount_in_cents type="integer">2000</unit_amount_in_cents>
+			<quantity type="integer">1</quantity>
+			<discount_in_cents type="integer">0</discount_in_cents>
+			<tax_in_cents type="integer">180</tax_in_cents>
+			<total_in_cents type="integer">2180</total_in_cents>
+			<currency>USD</currency>
+			<taxable type="boolean">false</taxable>
+			<created_at type="datetime">2011-08-31T03:30:00Z</created_at>
+		</adjustment>
+	</line_items>
+	<transactions type="array">
+	</transactions>
+</invoice>`
+
+	// TransactionErrorXML is a 422 Unprocessable Entity response for a
+	// transaction declined by the gateway's fraud filters. Serve it with
+	// http.StatusUnprocessableEntity.
+	TransactionErrorXML = `<?xml version="1.0" encoding="UTF-8"?>
+<errors>
+	<transaction_error>
+		<error_code>fraud_gateway</error_code>
+		<error_category>fraud</error_category>
+		<merchant_message>The payment gateway declined the transaction due to fraud filters enabled in your gateway.</merchant_message>
+		<customer_message>The transaction was declined. Please use a different card, contact your bank, or contact support.</customer_message>
+		<gateway_error_code nil="nil"></gateway_error_code>
+	</transaction_error>
+	<error field="transaction.account.base" symbol="fraud_gateway">The transaction was declined. Please use a different card, contact your bank, or contact support.</error>
+	<transaction href="https://your-subdomain.recurly.com/v2/transactions/3054a79e4c3ab4699f95be455f8653bb" type="credit_card">
+		<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+		<uuid>3054a79e4c3ab4699f95be455f8653bb</uuid>
+		<action>purchase</action>
+		<amount_in_cents type="integer">100</amount_in_cents>
+		<tax_in_cents type="integer">0</tax_in_cents>
+		<currency>USD</currency>
+		<status>declined</status>
+		<payment_method>credit_card</payment_method>
+		<transaction_error>
+			<error_code>fraud_gateway</error_code>
+			<error_category>fraud</error_category>
+			<merchant_message>The payment gateway declined the transaction due to fraud filters enabled in your gateway.</merchant_message>
+			<customer_message>The transaction was declined. Please use a different card, contact your bank, or contact support.</customer_message>
+			<gateway_error_code nil="nil"></gateway_error_code>
+		</transaction_error>
+		<details>
+		</details>
+	</transaction>
+</errors>`
+)
@@ -0,0 +1,50 @@
+// Package recurlytest provides utilities for testing code that uses the
+// recurly package against a local HTTP server with realistic responses.
+package recurlytest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/portofinolabs/recurly"
+)
+
+// Server is a test HTTP server that mimics the Recurly API. Register
+// handlers on Mux, or use HandleXML, to provide responses for the
+// endpoints under test. Paths include the API version, e.g.
+// "/v2/subscriptions/44f83d7cba354d5b84812419f923ea96".
+type Server struct {
+	*httptest.Server
+	Mux *http.ServeMux
+}
+
+// NewServer starts a Server and returns it along with a *recurly.Client
+// configured to talk to it. Callers should call Close when finished.
+func NewServer() (*Server, *recurly.Client) {
+	mux := http.NewServeMux()
+	s := &Server{
+		Server: httptest.NewServer(mux),
+		Mux:    mux,
+	}
+
+	client := recurly.NewClient("test", "abc", nil)
+	client.BaseURL = s.URL + "/"
+
+	return s, client
+}
+
+// HandleXML registers a handler for pattern that responds to requests
+// using method with statusCode and body. Requests using any other method
+// receive a 405 Method Not Allowed.
+func (s *Server) HandleXML(method, pattern string, statusCode int, body string) {
+	s.Mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(statusCode)
+		fmt.Fprint(w, body)
+	})
+}
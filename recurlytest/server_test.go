@@ -0,0 +1,55 @@
+package recurlytest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/portofinolabs/recurly"
+	"github.com/portofinolabs/recurly/recurlytest"
+)
+
+func TestServer_Fixtures(t *testing.T) {
+	s, client := recurlytest.NewServer()
+	defer s.Close()
+
+	s.HandleXML("GET", "/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", http.StatusOK, recurlytest.SubscriptionXML)
+	s.HandleXML("GET", "/v2/invoices/1005", http.StatusOK, recurlytest.InvoiceXML)
+	s.HandleXML("POST", "/v2/transactions", http.StatusUnprocessableEntity, recurlytest.TransactionErrorXML)
+
+	if resp, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if sub.UUID != "44f83d7cba354d5b84812419f923ea96" || sub.AccountCode != "1" || sub.State != "active" {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+
+	if resp, invoice, err := client.Invoices.Get(1005); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if invoice.InvoiceNumber != 1005 || invoice.TotalInCents != 2180 || len(invoice.LineItems) != 1 {
+		t.Fatalf("unexpected invoice: %#v", invoice)
+	}
+
+	resp, tx, err := client.Transactions.Create(recurly.Transaction{AmountInCents: 100, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	} else if tx == nil || tx.TransactionError == nil || tx.TransactionError.ErrorCode != "fraud_gateway" {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	}
+}
+
+func TestServer_HandleXML_MethodNotAllowed(t *testing.T) {
+	s, client := recurlytest.NewServer()
+	defer s.Close()
+
+	s.HandleXML("POST", "/v2/invoices/1005", http.StatusOK, recurlytest.InvoiceXML)
+	if resp, _, err := client.Invoices.Get(1005); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+}
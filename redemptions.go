@@ -2,6 +2,14 @@ package recurly
 
 import "encoding/xml"
 
+const (
+	// RedemptionStateActive is the state of redemptions that still apply.
+	RedemptionStateActive = "active"
+
+	// RedemptionStateInactive is the state of expired or removed redemptions.
+	RedemptionStateInactive = "inactive"
+)
+
 // Redemption holds redeemed coupons for an account or invoice.
 type Redemption struct {
 	CouponCode             string
@@ -13,11 +21,17 @@ type Redemption struct {
 	CreatedAt              NullTime
 }
 
+// CouponRedemption is a coupon redeemed on an account or invoice, as listed
+// by Accounts.ListRedemptions and Invoices.ListRedemptions.
+type CouponRedemption = Redemption
+
 // UnmarshalXML unmarshal a coupon redemption object. Minaly converts href links
-// for coupons and accounts to CouponCode and AccountCodes.
+// for coupons and accounts to CouponCode and AccountCodes. Redemptions are
+// read from either <redemption> or, inline in a subscription,
+// <coupon_redemption> elements.
 func (r *Redemption) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var v struct {
-		XMLName                xml.Name   `xml:"redemption"`
+		XMLName                xml.Name
 		CouponCode             hrefString `xml:"coupon,omitempty"`
 		AccountCode            hrefString `xml:"account,omitempty"`
 		SingleUse              NullBool   `xml:"single_use,omitempty"`
@@ -0,0 +1,94 @@
+package recurly
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestInfo describes a completed API request. It is passed to hooks
+// registered with Client.OnRequestComplete.
+type RequestInfo struct {
+	Method string
+
+	// Route is the request path with identifiers replaced by placeholders,
+	// e.g. /v2/subscriptions/{uuid}. It is suitable for use as a metric
+	// label without creating a label per account or subscription.
+	Route string
+
+	// StatusCode is 0 if the request failed before a response was received.
+	StatusCode int
+
+	// Duration includes sending the request and reading the response body.
+	Duration time.Duration
+
+	// Retries is the number of times the request was retried according to
+	// Client.RetryPolicy.
+	Retries int
+
+	// Body is the request body, which is identical on each attempt. It is
+	// nil for requests without a body and must not be modified.
+	Body []byte
+}
+
+// routeIdentifiers maps collections to the placeholder used for the path
+// segment that follows them.
+var routeIdentifiers = map[string]string{
+	"accounts":       "{account_code}",
+	"add_ons":        "{add_on_code}",
+	"adjustments":    "{uuid}",
+	"coupons":        "{coupon_code}",
+	"export_dates":   "{date}",
+	"export_files":   "{file_name}",
+	"invoices":       "{invoice_number}",
+	"measured_units": "{id}",
+	"plans":          "{plan_code}",
+	"subscriptions":  "{uuid}",
+	"transactions":   "{uuid}",
+}
+
+// OnRequestComplete registers fn to be called after each API request
+// completes, whether or not it succeeded. Hooks should be registered before
+// the client is used and must be safe for concurrent use.
+func (c *Client) OnRequestComplete(fn func(info RequestInfo)) {
+	c.requestHooks = append(c.requestHooks, fn)
+}
+
+// requestComplete calls the registered hooks for req.
+func (c *Client) requestComplete(req *http.Request, body []byte, resp *http.Response, start time.Time, retries int) {
+	info := RequestInfo{
+		Method:   req.Method,
+		Route:    normalizeRoute(req.URL.Path),
+		Duration: time.Since(start),
+		Retries:  retries,
+		Body:     body,
+	}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+
+	for _, fn := range c.requestHooks {
+		fn(info)
+	}
+}
+
+// normalizeRoute replaces identifiers in an API path with placeholders.
+// Any prefix before the API version, such as a proxy path in BaseURL, is
+// removed.
+func normalizeRoute(path string) string {
+	if i := strings.Index(path, "/v2/"); i >= 0 {
+		path = path[i:]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		placeholder, ok := routeIdentifiers[segments[i-1]]
+		if !ok || segments[i] == "preview" {
+			continue
+		}
+		segments[i] = placeholder
+		i++ // The segment after an identifier is never an identifier.
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,123 @@
+package recurly
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// RequestOption modifies an API request before it is sent. Options are
+// applied after the client sets its default headers, so they may override
+// them.
+type RequestOption func(req *http.Request)
+
+// WithHeader sets the header key to value on the request.
+func WithHeader(key, value string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// WithUserAgent sets the User-Agent header on the request, overriding
+// Client.UserAgent.
+func WithUserAgent(userAgent string) RequestOption {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithContext sets the context of the request. Canceling ctx aborts the
+// request, including any retries.
+func WithContext(ctx context.Context) RequestOption {
+	return func(req *http.Request) {
+		*req = *req.WithContext(ctx)
+	}
+}
+
+// WithQuery sets the query string parameter key to value on the request,
+// replacing any value set by the method's params.
+func WithQuery(key, value string) RequestOption {
+	return func(req *http.Request) {
+		qs := req.URL.Query()
+		qs.Set(key, value)
+		req.URL.RawQuery = qs.Encode()
+	}
+}
+
+// WithCompression asks Recurly to gzip the response body when enabled is
+// true, or stops asking when false, overriding Client.Compression.
+// Compressed responses are decompressed before they are decoded, which
+// reduces bandwidth for large list and invoice responses. It is useful with
+// HTTP clients whose transport does not request compression itself.
+func WithCompression(enabled bool) RequestOption {
+	return func(req *http.Request) {
+		if enabled {
+			req.Header.Set("Accept-Encoding", "gzip")
+		} else {
+			req.Header.Del("Accept-Encoding")
+		}
+	}
+}
+
+// WithBulk sets the bulk query string parameter on the request. In the v2
+// API, bulk=true bypasses the check that rejects a duplicate subscription
+// created for the same account within 60 seconds, which is meant for
+// creating or modifying many subscriptions from a batch job. See
+// SubscriptionsService.Postpone, which takes bulk as an argument.
+// https://dev.recurly.com/docs/create-subscription
+func WithBulk(bulk bool) RequestOption {
+	return WithQuery("bulk", strconv.FormatBool(bulk))
+}
+
+// WithXMLDeclaration adds the standard XML declaration to the request body
+// when enabled is true, or removes it when false, overriding
+// Client.XMLDeclaration. Requests without a body are unchanged. If the body
+// can't be read, the request fails with the error instead of being sent.
+func WithXMLDeclaration(enabled bool) RequestOption {
+	return func(req *http.Request) {
+		if req.GetBody == nil || req.ContentLength == 0 {
+			return
+		}
+		rc, err := req.GetBody()
+		if err != nil {
+			failBody(req, err)
+			return
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			failBody(req, err)
+			return
+		}
+
+		b = bytes.TrimPrefix(b, []byte(xml.Header))
+		if enabled {
+			b = append([]byte(xml.Header), b...)
+		}
+		req.ContentLength = int64(len(b))
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(b)), nil
+		}
+	}
+}
+
+// failBody replaces the body of req with one that can't be read, so that do
+// returns err when it reads the body instead of sending the request.
+func failBody(req *http.Request, err error) {
+	req.Body = ioutil.NopCloser(failReader{err})
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, err
+	}
+}
+
+// failReader is an io.Reader that always returns err.
+type failReader struct {
+	err error
+}
+
+func (r failReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
@@ -15,6 +15,10 @@ type Response struct {
 	// Errors holds an array of validation errors if any occurred.
 	Errors []Error
 
+	// Attempts is the number of times the request was sent, including
+	// retries made according to Client.RetryPolicy.
+	Attempts int
+
 	// transaction holds the transaction returned with a transaction error.
 	transaction *Transaction
 }
@@ -0,0 +1,171 @@
+package recurly
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how the client retries requests that Recurly
+// rejected without processing. Only 429 Too Many Requests and
+// 503 Service Unavailable responses are retried, so requests that create
+// or modify resources are never sent twice after Recurly accepted them.
+// GET requests, which don't modify anything, are also retried when they fail
+// before a response is received, such as on a network error.
+//
+// If a request fails on every attempt, the returned error holds the error of
+// each attempt, so errors.Is and errors.As find the underlying network error.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+
+	// Backoff returns how long to wait before the given retry attempt,
+	// starting at 1. resp is the response that caused the retry, or nil if
+	// the attempt failed without a response. If nil,
+	// FullJitterBackoff(DefaultRetryBaseDelay, DefaultRetryMaxDelay) is used.
+	//
+	// If the response has a Retry-After header, the client waits for the
+	// greater of the header, capped at MaxRetryAfter, and the value
+	// returned by Backoff.
+	Backoff func(attempt int, resp *http.Response) time.Duration
+
+	// MaxRetryAfter caps the wait taken from a Retry-After header, so a
+	// large value can't stall a request indefinitely. If zero,
+	// DefaultRetryMaxDelay is used.
+	MaxRetryAfter time.Duration
+}
+
+const (
+	// DefaultRetryBaseDelay is the base delay used when RetryPolicy.Backoff is nil.
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+
+	// DefaultRetryMaxDelay is the maximum delay used when RetryPolicy.Backoff is nil.
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// FullJitterBackoff returns a backoff function that waits a random duration
+// between zero and base * 2^(attempt-1), capped at max. Spreading retries
+// over the whole interval keeps many clients that were rate limited at the
+// same time from retrying in lockstep.
+func FullJitterBackoff(base, max time.Duration) func(attempt int, resp *http.Response) time.Duration {
+	return func(attempt int, resp *http.Response) time.Duration {
+		ceiling := max
+		if attempt < 1 {
+			attempt = 1
+		}
+		// Stop doubling once the ceiling is reached to avoid overflow.
+		if shift := uint(attempt - 1); shift < 63 && base <= max>>shift {
+			ceiling = base << shift
+		}
+		if ceiling <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(ceiling) + 1))
+	}
+}
+
+// shouldRetry reports whether the response to the given attempt should be
+// retried.
+func (p *RetryPolicy) shouldRetry(attempt int, resp *http.Response) bool {
+	if p == nil || attempt > p.MaxRetries {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// shouldRetryError reports whether req should be retried after the given
+// attempt failed without a response.
+func (p *RetryPolicy) shouldRetryError(attempt int, req *http.Request) bool {
+	if p == nil || attempt > p.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+	return req.Method == "GET" || req.Method == "HEAD"
+}
+
+// delay returns how long to wait before the given retry attempt.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = FullJitterBackoff(DefaultRetryBaseDelay, DefaultRetryMaxDelay)
+	}
+
+	max := p.MaxRetryAfter
+	if max <= 0 {
+		max = DefaultRetryMaxDelay
+	}
+
+	d := backoff(attempt, resp)
+	if after := retryAfter(resp, time.Now(), max); after > d {
+		d = after
+	}
+	return d
+}
+
+// retryAfter parses the Retry-After header of resp, which may be a number
+// of seconds or an HTTP date, capped at max. It returns 0 if the header is
+// absent or invalid.
+func retryAfter(resp *http.Response, now time.Time, max time.Duration) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0
+		} else if seconds > int64(max/time.Second) {
+			return max
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		if d := t.Sub(now); d < max {
+			return d
+		}
+		return max
+	}
+	return 0
+}
+
+// attemptErrors holds the error of each attempt of a request that failed
+// without a response on every attempt.
+type attemptErrors []error
+
+func (e attemptErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the error of each attempt.
+func (e attemptErrors) Unwrap() []error {
+	return e
+}
+
+// Is reports whether the error of any attempt matches target. It lets
+// errors.Is look through the attempts on Go versions that don't use Unwrap.
+func (e attemptErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first attempt error that matches target, like errors.As.
+func (e attemptErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
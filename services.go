@@ -2,6 +2,8 @@ package recurly
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"time"
 )
 
@@ -11,6 +13,7 @@ type Params map[string]interface{}
 // AccountsService represents the interactions available for accounts.
 type AccountsService interface {
 	List(params Params) (*Response, []Account, error)
+	Search(params Params) (*Response, []Account, error)
 	Get(code string) (*Response, *Account, error)
 	LookupAccountBalance(code string) (*Response, *AccountBalance, error)
 	Create(a Account) (*Response, *Account, error)
@@ -18,6 +21,14 @@ type AccountsService interface {
 	Close(code string) (*Response, error)
 	Reopen(code string) (*Response, error)
 	ListNotes(code string) (*Response, []Note, error)
+	ListChildAccounts(parentCode string, params Params) (*Response, []Account, error)
+	ListTransactions(code string, params Params) (*Response, []Transaction, error)
+	ListInvoices(code string, params Params) (*Response, []Invoice, error)
+	ListRedemptions(code string, params Params) (*Response, []CouponRedemption, error)
+	GetAcquisition(code string) (*Response, *AccountAcquisition, error)
+	CreateAcquisition(code string, a AccountAcquisition) (*Response, *AccountAcquisition, error)
+	UpdateAcquisition(code string, a AccountAcquisition) (*Response, *AccountAcquisition, error)
+	DeleteAcquisition(code string) (*Response, error)
 }
 
 // AdjustmentsService represents the interactions available for adjustments.
@@ -25,6 +36,7 @@ type AdjustmentsService interface {
 	List(accountCode string, params Params) (*Response, []Adjustment, error)
 	Get(uuid string) (*Response, *Adjustment, error)
 	Create(accountCode string, a Adjustment) (*Response, *Adjustment, error)
+	Preview(accountCode string, a Adjustment) (*Response, *Adjustment, error)
 	Delete(uuid string) (*Response, error)
 }
 
@@ -45,6 +57,7 @@ type BillingService interface {
 	Update(accountCode string, b Billing) (*Response, *Billing, error)
 	UpdateWithToken(accountCode string, token string) (*Response, *Billing, error)
 	Clear(accountCode string) (*Response, error)
+	Verify(accountCode string) (*Response, *Transaction, error)
 }
 
 // CouponsService represents the interactions available for coupons.
@@ -55,25 +68,44 @@ type CouponsService interface {
 	Delete(code string) (*Response, error)
 }
 
+// ExportsService represents the interactions available for automated exports.
+type ExportsService interface {
+	ListDates() (*Response, []string, error)
+	ListFiles(date string) (*Response, []ExportFile, error)
+	GetFile(date string, fileName string) (*Response, *ExportFile, error)
+	DownloadFile(date string, fileName string) (*Response, io.ReadCloser, error)
+}
+
 // InvoicesService represents the interactions available for invoices.
 type InvoicesService interface {
 	List(params Params) (*Response, []Invoice, error)
+	ListWithOptions(opts InvoiceListOptions) (*Response, []Invoice, error)
 	ListAccount(accountCode string, params Params) (*Response, []Invoice, error)
 	Get(invoiceNumber int) (*Response, *Invoice, error)
+	GetByNumber(prefix string, invoiceNumber int) (*Response, *Invoice, error)
 	GetPDF(invoiceNumber int, language string) (*Response, *bytes.Buffer, error)
 	Preview(accountCode string) (*Response, *Invoice, error)
 	Create(accountCode string, invoice Invoice) (*Response, *Invoice, error)
 	Collect(invoiceNumber int) (*Response, *Invoice, error)
 	MarkPaid(invoiceNumber int) (*Response, *Invoice, error)
 	MarkFailed(invoiceNumber int) (*Response, *Invoice, error)
+	Void(invoiceNumber int) (*Response, *Invoice, error)
 	RefundVoidOpenAmount(invoiceNumber int, amountInCents int, refundApplyOrder string) (*Response, *Invoice, error)
 	RecordPayment(offlinePayment OfflinePayment) (*Response, *Transaction, error)
+	ListRedemptions(invoiceNumber int, params Params) (*Response, []CouponRedemption, error)
+}
+
+// MeasuredUnitsService represents the interactions available for measured
+// units.
+type MeasuredUnitsService interface {
+	Get(id int) (*Response, *MeasuredUnit, error)
 }
 
 // PlansService represents the interactions available for plans.
 type PlansService interface {
 	List(params Params) (*Response, []Plan, error)
 	Get(code string) (*Response, *Plan, error)
+	GetWithAddOns(code string) (*Response, *Plan, error)
 	Create(p Plan) (*Response, *Plan, error)
 	Update(code string, p Plan) (*Response, *Plan, error)
 	Delete(code string) (*Response, error)
@@ -89,26 +121,43 @@ type RedemptionsService interface {
 
 // SubscriptionsService represents the interactinos available for subscriptions.
 type SubscriptionsService interface {
-	List(params Params) (*Response, []Subscription, error)
-	ListAccount(accountCode string, params Params) (*Response, []Subscription, error)
-	Get(uuid string) (*Response, *Subscription, error)
-	Create(sub NewSubscription) (*Response, *NewSubscriptionResponse, error)
-	Preview(sub NewSubscription) (*Response, *Subscription, error)
-	Update(uuid string, sub UpdateSubscription) (*Response, *Subscription, error)
-	UpdateNotes(uuid string, n SubscriptionNotes) (*Response, *Subscription, error)
-	PreviewChange(uuid string, sub UpdateSubscription) (*Response, *Subscription, error)
-	Cancel(uuid string) (*Response, *Subscription, error)
-	Reactivate(uuid string) (*Response, *Subscription, error)
-	TerminateWithPartialRefund(uuid string) (*Response, *Subscription, error)
-	TerminateWithFullRefund(uuid string) (*Response, *Subscription, error)
-	TerminateWithoutRefund(uuid string) (*Response, *Subscription, error)
-	Postpone(uuid string, dt time.Time, bulk bool) (*Response, *Subscription, error)
+	List(params Params, opts ...RequestOption) (*Response, []Subscription, error)
+	Stream(ctx context.Context, params Params, opts ...RequestOption) (<-chan Subscription, <-chan error)
+	UpcomingRenewals(ctx context.Context, within time.Duration, opts ...RequestOption) ([]Subscription, error)
+	ListWithInvoices(ctx context.Context, o ListWithInvoicesOptions, opts ...RequestOption) (*Response, []SubscriptionWithInvoice, error)
+	ListAccount(accountCode string, params Params, opts ...RequestOption) (*Response, []Subscription, error)
+	Get(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	GetWithAddOnNames(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	Create(sub NewSubscription, opts ...RequestOption) (*Response, *NewSubscriptionResponse, error)
+	CreateForAccount(accountCode string, sub NewSubscription, opts ...RequestOption) (*Response, *NewSubscriptionResponse, error)
+	CreateWithTransaction(sub NewSubscription, opts ...RequestOption) (*Response, *NewSubscriptionResponse, error)
+	Preview(sub NewSubscription, opts ...RequestOption) (*Response, *SubscriptionPreview, error)
+	Update(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *Subscription, error)
+	IncrementAddOn(uuid string, addOnCode string, delta int, opts ...RequestOption) (*Response, *Subscription, error)
+	UpdateNotes(uuid string, n SubscriptionNotes, opts ...RequestOption) (*Response, *Subscription, error)
+	PreviewChange(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *Subscription, error)
+	PreviewChangeDetails(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *SubscriptionChangePreview, error)
+	PreviewChangeWithCache(uuid string, sub UpdateSubscription, cache PreviewCache, opts ...RequestOption) (*Response, *Subscription, error)
+	PreviewRenewal(uuid string, opts ...RequestOption) (*Response, *InvoiceCollection, error)
+	Cancel(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	CancelIfActive(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	Reactivate(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	ReactivateWithOptions(uuid string, o ReactivateOptions, opts ...RequestOption) (*Response, *Subscription, error)
+	TerminateWithPartialRefund(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	TerminateWithFullRefund(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	TerminateWithoutRefund(uuid string, opts ...RequestOption) (*Response, *Subscription, error)
+	TerminateWithResult(uuid string, refundType string, opts ...RequestOption) (*Response, *TerminateResult, error)
+	Postpone(uuid string, dt time.Time, bulk bool, opts ...RequestOption) (*Response, *Subscription, error)
 }
 
 // TransactionsService represents the interactions available for transactions.
 type TransactionsService interface {
 	List(params Params) (*Response, []Transaction, error)
 	ListAccount(accountCode string, params Params) (*Response, []Transaction, error)
+	ListForSubscription(subUUID string, params Params) (*Response, []Transaction, error)
 	Get(uuid string) (*Response, *Transaction, error)
 	Create(t Transaction) (*Response, *Transaction, error)
+	CreateForAccount(accountCode string, t Transaction) (*Response, *Transaction, error)
+	Capture(uuid string, amountInCents int) (*Response, *Transaction, error)
+	Cancel(uuid string) (*Response, *Transaction, error)
 }
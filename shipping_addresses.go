@@ -0,0 +1,24 @@
+package recurly
+
+import "encoding/xml"
+
+// ShippingAddress represents a shipping address on an account. Subscriptions
+// can reference a shipping address by ID or create one inline.
+// https://dev.recurly.com/docs/shipping-addresses
+type ShippingAddress struct {
+	XMLName     xml.Name `xml:"shipping_address"`
+	ID          int      `xml:"id,omitempty"` // Read only
+	Nickname    string   `xml:"nickname,omitempty"`
+	FirstName   string   `xml:"first_name,omitempty"`
+	LastName    string   `xml:"last_name,omitempty"`
+	CompanyName string   `xml:"company,omitempty"`
+	Email       string   `xml:"email,omitempty"`
+	VATNumber   string   `xml:"vat_number,omitempty"`
+	Phone       string   `xml:"phone,omitempty"`
+	Address     string   `xml:"address1,omitempty"`
+	Address2    string   `xml:"address2,omitempty"`
+	City        string   `xml:"city,omitempty"`
+	State       string   `xml:"state,omitempty"`
+	Zip         string   `xml:"zip,omitempty"`
+	Country     string   `xml:"country,omitempty"`
+}
@@ -0,0 +1,17 @@
+package recurly
+
+import "encoding/xml"
+
+// ShippingMethod is a way of shipping physical goods, such as ground or
+// express delivery, configured on the site. Subscriptions reference a
+// shipping method by its code.
+// https://dev.recurly.com/docs/shipping-methods
+type ShippingMethod struct {
+	XMLName        xml.Name `xml:"shipping_method"`
+	Code           string   `xml:"code,omitempty"`
+	Name           string   `xml:"name,omitempty"`
+	AccountingCode string   `xml:"accounting_code,omitempty"`
+	TaxCode        string   `xml:"tax_code,omitempty"`
+	CreatedAt      NullTime `xml:"created_at,omitempty"` // Read only
+	UpdatedAt      NullTime `xml:"updated_at,omitempty"` // Read only
+}
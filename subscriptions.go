@@ -1,15 +1,40 @@
 package recurly
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
 	"strings"
+	"time"
 )
 
-// SanitizeUUID returns the uuid without dashes.
+// SanitizeUUID returns the uuid without dashes. Every method that takes a
+// uuid sanitizes it, so dashed and dash-free uuids may be used
+// interchangeably.
 func SanitizeUUID(id string) string {
 	return strings.TrimSpace(strings.Replace(id, "-", "", -1))
 }
 
+// ValidateUUID returns an error if id, once sanitized, is not a Recurly uuid
+// of 32 hexadecimal characters. Methods taking a uuid don't validate it, so
+// use ValidateUUID to reject ids from untrusted input, such as a uuid with a
+// slash that would otherwise change the request path.
+func ValidateUUID(id string) error {
+	uuid := SanitizeUUID(id)
+	if len(uuid) != 32 {
+		return fmt.Errorf("recurly: invalid uuid %q, must be 32 hexadecimal characters", id)
+	}
+	for _, r := range uuid {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') && (r < 'A' || r > 'F') {
+			return fmt.Errorf("recurly: invalid uuid %q, must be 32 hexadecimal characters", id)
+		}
+	}
+	return nil
+}
+
 const (
 	// SubscriptionStateActive represents subscriptions that are valid for the
 	// current time. This includes subscriptions in a trial period
@@ -36,8 +61,92 @@ const (
 	// SubscriptionStatePastDue are subscriptions that are active or canceled
 	// and have a past-due invoice
 	SubscriptionStatePastDue = "past_due"
+
+	// SubscriptionStatePaused are subscriptions that are paused and will
+	// resume at a later date
+	SubscriptionStatePaused = "paused"
 )
 
+// SubscriptionState is the state of a subscription as stored by Recurly:
+// active, canceled, expired, future, or paused. SubscriptionStateLive,
+// SubscriptionStateInTrial, and SubscriptionStatePastDue are filters for
+// listing subscriptions and are never the state of a subscription.
+type SubscriptionState string
+
+// IsActive returns true if the subscription is active and will renew. This
+// includes subscriptions in a trial period.
+func (s SubscriptionState) IsActive() bool {
+	return s == SubscriptionStateActive
+}
+
+// IsCanceled returns true if the subscription was canceled and will expire
+// at the end of its current term.
+func (s SubscriptionState) IsCanceled() bool {
+	return s == SubscriptionStateCanceled
+}
+
+// IsExpired returns true if the subscription has expired.
+func (s SubscriptionState) IsExpired() bool {
+	return s == SubscriptionStateExpired
+}
+
+// IsLive returns true if the subscription is not expired. This matches the
+// SubscriptionStateLive filter: active, canceled, future, and paused
+// subscriptions are live.
+func (s SubscriptionState) IsLive() bool {
+	switch s {
+	case SubscriptionStateActive, SubscriptionStateCanceled, SubscriptionStateFuture, SubscriptionStatePaused:
+		return true
+	}
+	return false
+}
+
+const (
+	// SubscriptionTimeframeNow applies a subscription change immediately.
+	SubscriptionTimeframeNow = "now"
+
+	// SubscriptionTimeframeRenewal applies a subscription change at the
+	// next renewal.
+	SubscriptionTimeframeRenewal = "renewal"
+
+	// SubscriptionTimeframeBillDate applies a subscription change at the
+	// next bill date.
+	SubscriptionTimeframeBillDate = "bill_date"
+
+	// SubscriptionTimeframeTermEnd applies a subscription change at the end
+	// of the current term.
+	SubscriptionTimeframeTermEnd = "term_end"
+)
+
+// Revenue schedule type constants.
+const (
+	RevenueScheduleTypeNever        = "never"
+	RevenueScheduleTypeEvenly       = "evenly"
+	RevenueScheduleTypeAtRangeStart = "at_range_start"
+	RevenueScheduleTypeAtRangeEnd   = "at_range_end"
+	RevenueScheduleTypeAtInvoice    = "at_invoice"
+)
+
+// Refund type constants used when terminating a subscription.
+const (
+	RefundTypeFull    = "full"
+	RefundTypePartial = "partial"
+	RefundTypeNone    = "none"
+)
+
+// ErrRefundNotFound is returned by Subscriptions.TerminateWithResult when a
+// refund was requested but no refund transaction for the subscription was
+// found.
+var ErrRefundNotFound = errors.New("recurly: refund not found")
+
+// TerminateResult is returned by Subscriptions.TerminateWithResult. It holds
+// the terminated subscription along with the refund that was issued, if any.
+type TerminateResult struct {
+	Subscription      *Subscription
+	RefundInCents     int
+	RefundTransaction *Transaction
+}
+
 // Subscription represents an individual subscription.
 type Subscription struct {
 	XMLName                xml.Name             `xml:"subscription" json:"-"`
@@ -57,19 +166,33 @@ type Subscription struct {
 	CurrentPeriodEndsAt    NullTime             `xml:"current_period_ends_at,omitempty" json:"current_period_ends_at"`
 	TrialStartedAt         NullTime             `xml:"trial_started_at,omitempty" json:"trial_started_at"`
 	TrialEndsAt            NullTime             `xml:"trial_ends_at,omitempty" json:"trial_ends_at"`
+	ConvertedAt            NullTime             `xml:"converted_at,omitempty" json:"converted_at"`                                     // Read only
+	StartedWithGift        bool                 `xml:"started_with_gift,omitempty" json:"started_with_gift"`                           // Read only
+	GiftCreditInCents      NullInt              `xml:"remaining_gift_credit_in_cents,omitempty" json:"remaining_gift_credit_in_cents"` // Read only
 	TaxInCents             int                  `xml:"tax_in_cents,omitempty" json:"tax_in_cents"`
 	TaxType                string               `xml:"tax_type,omitempty" json:"tax_type"`
 	TaxRegion              string               `xml:"tax_region,omitempty" json:"tax_region"`
 	TaxRate                float64              `xml:"tax_rate,omitempty" json:"tax_rate"`
 	PONumber               string               `xml:"po_number,omitempty" json:"po_number"`
+	CollectionMethod       string               `xml:"collection_method,omitempty" json:"collection_method"`
 	NetTerms               NullInt              `xml:"net_terms,omitempty" json:"net_terms"`
+	RenewalBillingCycles   NullInt              `xml:"renewal_billing_cycles,omitempty" json:"renewal_billing_cycles"`
 	SubscriptionAddOns     []SubscriptionAddOn  `xml:"subscription_add_ons>subscription_add_on,omitempty" json:"-"`
 	PendingSubscription    *PendingSubscription `xml:"pending_subscription,omitempty" json:"pending_subscription,omitempty"`
+	CouponRedemptions      []Redemption         `xml:"-" json:"-"` // Read only
+
+	// Extra holds the text of response elements not mapped to a field.
+	// It is only populated when the client's KeepUnknownXML is set, or when
+	// decoding into a Subscription whose Extra is already non-nil.
+	Extra map[string]string `xml:"-" json:"-"`
 }
 
 // UnmarshalXML unmarshals transactions and handles intermediary state during unmarshaling
-// for types like href.
+// for types like href. SubscriptionAddOns is set to an empty, non-nil slice
+// when the subscription_add_ons element is present but empty, and left nil
+// when the element is absent.
 func (s *Subscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	keep := s.Extra != nil
 	var v struct {
 		XMLName                xml.Name             `xml:"subscription"`
 		Plan                   NestedPlan           `xml:"plan,omitempty"`
@@ -88,14 +211,21 @@ func (s *Subscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		CurrentPeriodEndsAt    NullTime             `xml:"current_period_ends_at,omitempty"`
 		TrialStartedAt         NullTime             `xml:"trial_started_at,omitempty"`
 		TrialEndsAt            NullTime             `xml:"trial_ends_at,omitempty"`
+		ConvertedAt            NullTime             `xml:"converted_at,omitempty"`
+		StartedWithGift        bool                 `xml:"started_with_gift,omitempty"`
+		GiftCreditInCents      NullInt              `xml:"remaining_gift_credit_in_cents,omitempty"`
 		TaxInCents             int                  `xml:"tax_in_cents,omitempty"`
 		TaxType                string               `xml:"tax_type,omitempty"`
 		TaxRegion              string               `xml:"tax_region,omitempty"`
 		TaxRate                float64              `xml:"tax_rate,omitempty"`
 		PONumber               string               `xml:"po_number,omitempty"`
+		CollectionMethod       string               `xml:"collection_method,omitempty"`
 		NetTerms               NullInt              `xml:"net_terms,omitempty"`
-		SubscriptionAddOns     []SubscriptionAddOn  `xml:"subscription_add_ons>subscription_add_on,omitempty"`
+		RenewalBillingCycles   NullInt              `xml:"renewal_billing_cycles,omitempty"`
+		SubscriptionAddOns     *subscriptionAddOns  `xml:"subscription_add_ons"`
 		PendingSubscription    *PendingSubscription `xml:"pending_subscription,omitempty"`
+		CouponRedemptions      []Redemption         `xml:"coupon_redemptions>coupon_redemption"`
+		Extra                  []extraElement       `xml:",any"`
 	}
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
@@ -118,19 +248,120 @@ func (s *Subscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		CurrentPeriodEndsAt:    v.CurrentPeriodEndsAt,
 		TrialStartedAt:         v.TrialStartedAt,
 		TrialEndsAt:            v.TrialEndsAt,
+		ConvertedAt:            v.ConvertedAt,
+		StartedWithGift:        v.StartedWithGift,
+		GiftCreditInCents:      v.GiftCreditInCents,
 		TaxInCents:             v.TaxInCents,
 		TaxType:                v.TaxType,
 		TaxRegion:              v.TaxRegion,
 		TaxRate:                v.TaxRate,
 		PONumber:               v.PONumber,
+		CollectionMethod:       v.CollectionMethod,
 		NetTerms:               v.NetTerms,
-		SubscriptionAddOns:     v.SubscriptionAddOns,
+		RenewalBillingCycles:   v.RenewalBillingCycles,
 		PendingSubscription:    v.PendingSubscription,
+		CouponRedemptions:      v.CouponRedemptions,
+		Extra:                  extraMap(keep, v.Extra),
+	}
+
+	if v.SubscriptionAddOns != nil {
+		s.SubscriptionAddOns = v.SubscriptionAddOns.AddOns
+		if s.SubscriptionAddOns == nil {
+			s.SubscriptionAddOns = []SubscriptionAddOn{}
+		}
 	}
 
 	return nil
 }
 
+// StateEnum returns the subscription's state as a SubscriptionState.
+func (s Subscription) StateEnum() SubscriptionState {
+	return SubscriptionState(s.State)
+}
+
+// InTrial returns true if the subscription is active or canceled and its
+// trial has not ended. This matches the SubscriptionStateInTrial filter,
+// which depends on the trial dates rather than the state alone.
+func (s Subscription) InTrial() bool {
+	state := s.StateEnum()
+	if !state.IsActive() && !state.IsCanceled() {
+		return false
+	}
+	return s.TrialEndsAt.Time != nil && s.TrialEndsAt.Time.After(time.Now())
+}
+
+// HasPendingChange returns true if the subscription has changes that take
+// effect at the end of the current period.
+func (s Subscription) HasPendingChange() bool {
+	return s.PendingSubscription != nil
+}
+
+// NextPeriodAmount returns the amount in cents, before discounts and taxes,
+// that the subscription will renew at: the base price times quantity plus
+// each add on's price times its quantity. Pending changes are used where
+// present, including a change to a free plan or a quantity of zero, falling
+// back to the current subscription for anything the pending subscription
+// leaves unchanged.
+func (s Subscription) NextPeriodAmount() int {
+	price, quantity, addOns := s.UnitAmountInCents, s.Quantity, s.SubscriptionAddOns
+	if p := s.PendingSubscription; p != nil {
+		if p.Price.Valid {
+			price = p.Price.Int
+		}
+		if p.Quantity.Valid {
+			quantity = p.Quantity.Int
+		}
+		if p.SubscriptionAddOns != nil {
+			addOns = p.SubscriptionAddOns
+		}
+	}
+
+	return periodAmount(price, quantity, addOns)
+}
+
+// DiscountedAmountInCents returns the subscription's current price times
+// quantity plus its add ons, less the discount of each coupon that is
+// actively redeemed on the subscription and applies to its plan. Redemptions
+// don't include the coupon's discount, so pass the redeemed coupons, such as
+// from Coupons.Get. Coupons are applied in turn to the remaining amount, and
+// the result is never negative.
+func (s Subscription) DiscountedAmountInCents(coupons ...Coupon) int {
+	amount := periodAmount(s.UnitAmountInCents, s.Quantity, s.SubscriptionAddOns)
+	for _, c := range coupons {
+		if s.hasActiveRedemption(c.Code) && c.Applies(s.Plan.Code) {
+			amount -= c.Discount(s.Currency, amount)
+		}
+	}
+	return amount
+}
+
+// hasActiveRedemption returns true if the coupon is actively redeemed on the
+// subscription.
+func (s Subscription) hasActiveRedemption(couponCode string) bool {
+	for _, r := range s.CouponRedemptions {
+		if r.CouponCode == couponCode && r.State == RedemptionStateActive {
+			return true
+		}
+	}
+	return false
+}
+
+// periodAmount returns price times quantity plus each add on's price times
+// its quantity.
+func periodAmount(price int, quantity int, addOns []SubscriptionAddOn) int {
+	total := price * quantity
+	for _, a := range addOns {
+		total += a.UnitAmountInCents * a.Quantity
+	}
+	return total
+}
+
+// subscriptionAddOns is used to tell an empty subscription_add_ons element
+// apart from a missing one when unmarshaling.
+type subscriptionAddOns struct {
+	AddOns []SubscriptionAddOn `xml:"subscription_add_on"`
+}
+
 // MakeUpdate creates an UpdateSubscription with values that need to be passed
 // on update to be retained (meaning nil/zero values will delete that value).
 // After calling MakeUpdate you should modify the struct with your updates.
@@ -144,6 +375,54 @@ func (s Subscription) MakeUpdate() UpdateSubscription {
 	}
 }
 
+// DiffUpdate returns an UpdateSubscription that changes s into modified,
+// setting only the updatable fields that differ: the plan, quantity, unit
+// amount, add-ons, PO number, net terms, and collection method. If nothing
+// changed the update is empty. Changed add-ons are sent as the full list
+// from modified, since Recurly replaces a subscription's add-ons on update.
+// Because empty values are not encoded, clearing the PO number or setting
+// the quantity or unit amount to zero is not represented.
+func (s Subscription) DiffUpdate(modified Subscription) UpdateSubscription {
+	var u UpdateSubscription
+	if modified.Plan.Code != s.Plan.Code {
+		u.PlanCode = modified.Plan.Code
+	}
+	if modified.Quantity != s.Quantity {
+		u.Quantity = modified.Quantity
+	}
+	if modified.UnitAmountInCents != s.UnitAmountInCents {
+		u.UnitAmountInCents = modified.UnitAmountInCents
+	}
+	if modified.PONumber != s.PONumber {
+		u.PONumber = modified.PONumber
+	}
+	if modified.NetTerms != s.NetTerms {
+		u.NetTerms = modified.NetTerms
+	}
+	if modified.CollectionMethod != s.CollectionMethod {
+		u.CollectionMethod = modified.CollectionMethod
+	}
+	if !equalAddOns(s.SubscriptionAddOns, modified.SubscriptionAddOns) {
+		addOns := append([]SubscriptionAddOn{}, modified.SubscriptionAddOns...)
+		u.SubscriptionAddOns = &addOns
+	}
+	return u
+}
+
+// equalAddOns reports whether a and b hold the same add-ons in the same
+// order. Nil and empty lists are equal.
+func equalAddOns(a, b []SubscriptionAddOn) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 type NestedPlan struct {
 	Code string `xml:"plan_code,omitempty" json:"plan_code"`
 	Name string `xml:"name,omitempty" json:"name"`
@@ -157,19 +436,134 @@ type SubscriptionAddOn struct {
 	Code              string   `xml:"add_on_code"`
 	UnitAmountInCents int      `xml:"unit_amount_in_cents"`
 	Quantity          int      `xml:"quantity,omitempty"`
+
+	// Name is the add on's name from the plan. Recurly doesn't include it
+	// in subscriptions, so it is only set by Subscriptions.GetWithAddOnNames.
+	Name string `xml:"-"`
+
+	// AddOnSource is AddOnSourcePlan for add ons defined on the plan, or
+	// AddOnSourceAccount for account level add ons that aren't tied to a
+	// plan. Recurly defaults to AddOnSourcePlan when it is empty.
+	AddOnSource string `xml:"add_on_source,omitempty"`
+
+	// Usage-based add ons. These are empty for flat add ons, and are
+	// decoded by UnmarshalXML but never sent.
+	UsageType       string                   `xml:"-"` // Read only
+	UsagePercentage float64                  `xml:"-"` // Read only
+	MeasuredUnitID  int                      `xml:"-"` // Read only
+	TierType        string                   `xml:"-"` // Read only
+	Tiers           *[]SubscriptionAddOnTier `xml:"-"` // Read only
+}
+
+// UnmarshalXML unmarshals a subscription add on, including its read only
+// usage fields.
+func (a *SubscriptionAddOn) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		XMLName           xml.Name                 `xml:"subscription_add_on"`
+		Type              string                   `xml:"add_on_type,omitempty"`
+		Code              string                   `xml:"add_on_code"`
+		UnitAmountInCents int                      `xml:"unit_amount_in_cents"`
+		Quantity          int                      `xml:"quantity,omitempty"`
+		AddOnSource       string                   `xml:"add_on_source,omitempty"`
+		UsageType         string                   `xml:"usage_type,omitempty"`
+		UsagePercentage   float64                  `xml:"usage_percentage,omitempty"`
+		MeasuredUnitID    int                      `xml:"measured_unit_id,omitempty"`
+		TierType          string                   `xml:"tier_type,omitempty"`
+		Tiers             *[]SubscriptionAddOnTier `xml:"tiers>tier,omitempty"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*a = SubscriptionAddOn{
+		XMLName:           v.XMLName,
+		Type:              v.Type,
+		Code:              v.Code,
+		UnitAmountInCents: v.UnitAmountInCents,
+		Quantity:          v.Quantity,
+		AddOnSource:       v.AddOnSource,
+		UsageType:         v.UsageType,
+		UsagePercentage:   v.UsagePercentage,
+		MeasuredUnitID:    v.MeasuredUnitID,
+		TierType:          v.TierType,
+		Tiers:             v.Tiers,
+	}
+
+	return nil
+}
+
+// Add on source constants for SubscriptionAddOn.AddOnSource.
+const (
+	AddOnSourcePlan    = "plan_add_on"
+	AddOnSourceAccount = "account_add_on"
+)
+
+// SubscriptionAddOnTier is a pricing tier of a tiered subscription add on.
+// The tier applies to quantities up to and including EndingQuantity; the
+// last tier has no EndingQuantity.
+type SubscriptionAddOnTier struct {
+	XMLName           xml.Name `xml:"tier"`
+	EndingQuantity    int      `xml:"ending_quantity,omitempty"`
+	UnitAmountInCents int      `xml:"unit_amount_in_cents"`
 }
 
 // PendingSubscription are updates to the subscription or subscription add ons that
-// will be made on the next renewal.
+// will be made on the next renewal. The changes take effect at the
+// subscription's CurrentPeriodEndsAt.
 type PendingSubscription struct {
 	XMLName            xml.Name            `xml:"pending_subscription" json:"-"`
 	Plan               NestedPlan          `xml:"plan,omitempty" json:"plan,omitempty"`
-	Quantity           int                 `xml:"quantity,omitempty" json:"quantity,omitempty"` // Quantity of subscriptions
-	Price              int                 `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents,omitempty"`
+	Quantity           NullInt             `xml:"quantity,omitempty" json:"quantity,omitempty"` // Quantity of subscriptions
+	Price              NullInt             `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents,omitempty"`
+	TotalAmountInCents int                 `xml:"total_amount_in_cents,omitempty" json:"total_amount_in_cents,omitempty"`
+	CollectionMethod   string              `xml:"collection_method,omitempty" json:"collection_method,omitempty"`
+	CouponCode         string              `xml:"coupon_code,omitempty" json:"coupon_code,omitempty"`
 	SubscriptionAddOns []SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
 }
 
+// UnmarshalXML unmarshals a pending subscription. As with Subscription,
+// SubscriptionAddOns is set to an empty, non-nil slice when the
+// subscription_add_ons element is present but empty.
+func (p *PendingSubscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		XMLName            xml.Name            `xml:"pending_subscription"`
+		Plan               NestedPlan          `xml:"plan,omitempty"`
+		Quantity           NullInt             `xml:"quantity,omitempty"`
+		Price              NullInt             `xml:"unit_amount_in_cents,omitempty"`
+		TotalAmountInCents int                 `xml:"total_amount_in_cents,omitempty"`
+		CollectionMethod   string              `xml:"collection_method,omitempty"`
+		CouponCode         string              `xml:"coupon_code,omitempty"`
+		SubscriptionAddOns *subscriptionAddOns `xml:"subscription_add_ons"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*p = PendingSubscription{
+		XMLName:            v.XMLName,
+		Plan:               v.Plan,
+		Quantity:           v.Quantity,
+		Price:              v.Price,
+		TotalAmountInCents: v.TotalAmountInCents,
+		CollectionMethod:   v.CollectionMethod,
+		CouponCode:         v.CouponCode,
+	}
+
+	if v.SubscriptionAddOns != nil {
+		p.SubscriptionAddOns = v.SubscriptionAddOns.AddOns
+		if p.SubscriptionAddOns == nil {
+			p.SubscriptionAddOns = []SubscriptionAddOn{}
+		}
+	}
+
+	return nil
+}
+
 // NewSubscription is used to create new subscriptions.
+//
+// When migrating subscriptions from another system, set ImportedTrial so
+// that a trial carried over from the previous system isn't treated as a new
+// trial, and anchor the billing period with StartsAt, TrialEndsAt, and
+// FirstRenewalDate. The API doesn't accept activated_at or
+// current_period_started_at on creation; those are derived from the anchors.
 type NewSubscription struct {
 	XMLName                 xml.Name             `xml:"subscription"`
 	PlanCode                string               `xml:"plan_code"`
@@ -180,6 +574,7 @@ type NewSubscription struct {
 	Currency                string               `xml:"currency"`
 	Quantity                int                  `xml:"quantity,omitempty"`
 	TrialEndsAt             NullTime             `xml:"trial_ends_at,omitempty"`
+	ImportedTrial           bool                 `xml:"imported_trial,omitempty"`
 	StartsAt                NullTime             `xml:"starts_at,omitempty"`
 	TotalBillingCycles      int                  `xml:"total_billing_cycles,omitempty"`
 	FirstRenewalDate        NullTime             `xml:"first_renewal_date,omitempty"`
@@ -191,12 +586,127 @@ type NewSubscription struct {
 	CustomerNotes           string               `xml:"customer_notes,omitempty"`
 	VATReverseChargeNotes   string               `xml:"vat_reverse_charge_notes,omitempty"`
 	BankAccountAuthorizedAt NullTime             `xml:"bank_account_authorized_at,omitempty"`
+
+	// GatewayCode routes the subscription's payments to a specific payment
+	// gateway when the site has more than one.
+	GatewayCode string `xml:"gateway_code,omitempty"`
+
+	// DunningCampaignID assigns a dunning campaign to the subscription,
+	// overriding the one set on the plan.
+	DunningCampaignID string `xml:"dunning_campaign_id,omitempty"`
+
+	// ShippingAddressID ships the subscription to an existing shipping
+	// address on the account. Alternatively, set ShippingAddress to create
+	// a new address.
+	ShippingAddressID NullInt          `xml:"shipping_address_id,omitempty"`
+	ShippingAddress   *ShippingAddress `xml:"shipping_address,omitempty"`
+
+	// ShippingMethodCode is the code of the ShippingMethod used to ship the
+	// subscription. It is required when ShippingAmountInCents is set.
+	ShippingMethodCode string `xml:"shipping_method_code,omitempty"`
+
+	// ShippingAmountInCents is the shipping fee charged with each billing
+	// cycle of the subscription.
+	ShippingAmountInCents int `xml:"shipping_amount_in_cents,omitempty"`
 }
 
 // NewSubscriptionResponse is used to unmarshal either the subscription or the transaction.
 type NewSubscriptionResponse struct {
 	Subscription *Subscription
-	Transaction  *Transaction // UnprocessableEntity errors return only the transaction
+	Transaction  *Transaction // The declined transaction, or the purchase from CreateWithTransaction
+}
+
+// SubscriptionPreview is returned when previewing a new subscription. It holds
+// the previewed subscription along with the totals of the invoice that would
+// be created.
+type SubscriptionPreview struct {
+	Subscription Subscription
+
+	// Invoice is the previewed invoice, or nil if the response did not
+	// include one.
+	Invoice *Invoice
+
+	SubtotalInCents int
+	DiscountInCents int // Sum of discounts applied to the line items
+	TaxInCents      int
+	TotalInCents    int
+}
+
+// UnmarshalXML unmarshals a subscription preview. The invoice is read from
+// either <invoice> or <invoice_collection><charge_invoice>, depending on the
+// API version.
+func (p *SubscriptionPreview) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Inner []byte `xml:",innerxml"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<subscription>")
+	buf.Write(v.Inner)
+	buf.WriteString("</subscription>")
+
+	var sub Subscription
+	if p.Subscription.Extra != nil {
+		sub.keepExtra()
+	}
+	if err := xml.NewDecoder(&buf).Decode(&sub); err != nil {
+		return err
+	}
+
+	// The invoice collection is decoded below, so it isn't unknown.
+	delete(sub.Extra, "invoice_collection")
+	if len(sub.Extra) == 0 {
+		sub.Extra = nil
+	}
+
+	invoice, err := decodePreviewInvoice(v.Inner)
+	if err != nil {
+		return err
+	}
+
+	*p = SubscriptionPreview{Subscription: sub, Invoice: invoice}
+	if invoice != nil {
+		p.SubtotalInCents = invoice.SubtotalInCents
+		p.TaxInCents = invoice.TaxInCents
+		p.TotalInCents = invoice.TotalInCents
+		for _, a := range invoice.LineItems {
+			p.DiscountInCents += a.DiscountInCents
+		}
+	}
+
+	return nil
+}
+
+// decodePreviewInvoice finds and decodes the first <invoice> or
+// <charge_invoice> element in b. It returns nil if neither are present.
+func decodePreviewInvoice(b []byte) (*Invoice, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || (start.Name.Local != "invoice" && start.Name.Local != "charge_invoice") {
+			continue
+		}
+
+		start.Name.Local = "invoice"
+		var invoice Invoice
+		if err := d.DecodeElement(&invoice, &start); err != nil {
+			return nil, err
+		} else if invoice.Currency == "" && len(invoice.LineItems) == 0 {
+			// Skip invoice references such as <invoice href="..."/>.
+			continue
+		}
+		return &invoice, nil
+	}
 }
 
 // UpdateSubscription is used to update subscriptions
@@ -210,6 +720,288 @@ type UpdateSubscription struct {
 	NetTerms           NullInt              `xml:"net_terms,omitempty"`
 	PONumber           string               `xml:"po_number,omitempty"`
 	SubscriptionAddOns *[]SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
+
+	// RevenueScheduleType controls how revenue from the change is recognized.
+	RevenueScheduleType string `xml:"revenue_schedule_type,omitempty"`
+
+	// ChargeNow invoices and collects a prorated upgrade immediately instead
+	// of at renewal. Only valid when the timeframe is SubscriptionTimeframeNow.
+	ChargeNow NullBool `xml:"charge_now,omitempty"`
+
+	// TrialEndsAt keeps a subscription in its trial until the given time
+	// when the update changes the plan. If not set, Recurly applies the new
+	// plan's trial rules, which can end the current trial early. See
+	// SetPreserveTrial.
+	TrialEndsAt NullTime `xml:"trial_ends_at,omitempty"`
+
+	// RenewalBillingCycles sets the number of billing cycles in each term
+	// after the current one for fixed-term subscriptions, extending or
+	// shortening the committed term on renewal.
+	RenewalBillingCycles NullInt `xml:"renewal_billing_cycles,omitempty"`
+
+	// ShippingAddressID moves the subscription to an existing shipping
+	// address on the account. Alternatively, set ShippingAddress to create
+	// a new address.
+	ShippingAddressID NullInt          `xml:"shipping_address_id,omitempty"`
+	ShippingAddress   *ShippingAddress `xml:"shipping_address,omitempty"`
+
+	// GatewayCode moves the subscription's payments to a specific payment
+	// gateway when the site has more than one.
+	GatewayCode string `xml:"gateway_code,omitempty"`
+
+	// DunningCampaignID assigns a different dunning campaign to the
+	// subscription.
+	DunningCampaignID string `xml:"dunning_campaign_id,omitempty"`
+}
+
+// AddAddOn adds an add on to the update, or replaces the quantity and unit
+// amount of the add on if it's already present. Other add ons are retained.
+func (s *UpdateSubscription) AddAddOn(code string, quantity int, unitAmountInCents int) {
+	var addOns []SubscriptionAddOn
+	if s.SubscriptionAddOns != nil {
+		// Copy so add ons shared with a Subscription (see MakeUpdate)
+		// are not modified.
+		addOns = append(addOns, *s.SubscriptionAddOns...)
+	}
+
+	for i := range addOns {
+		if addOns[i].Code == code {
+			addOns[i].Quantity = quantity
+			addOns[i].UnitAmountInCents = unitAmountInCents
+			s.SubscriptionAddOns = &addOns
+			return
+		}
+	}
+
+	addOns = append(addOns, SubscriptionAddOn{
+		Code:              code,
+		Quantity:          quantity,
+		UnitAmountInCents: unitAmountInCents,
+	})
+	s.SubscriptionAddOns = &addOns
+}
+
+// RemoveAddOn removes an add on from the update while retaining the others.
+// Because Recurly replaces the entire set of add ons on update, the add on
+// will be removed from the subscription. If the update has no add ons,
+// RemoveAddOn does nothing, since sending an empty set would remove every
+// add on; start from MakeUpdate or DiffUpdate so the others are retained.
+func (s *UpdateSubscription) RemoveAddOn(code string) {
+	if s.SubscriptionAddOns == nil {
+		return
+	}
+
+	addOns := make([]SubscriptionAddOn, 0, len(*s.SubscriptionAddOns))
+	for _, a := range *s.SubscriptionAddOns {
+		if a.Code != code {
+			addOns = append(addOns, a)
+		}
+	}
+	s.SubscriptionAddOns = &addOns
+}
+
+// SetAddOns replaces the entire set of add ons on the subscription. Passing
+// an empty slice removes all add ons.
+func (s *UpdateSubscription) SetAddOns(addOns []SubscriptionAddOn) {
+	if addOns == nil {
+		addOns = []SubscriptionAddOn{}
+	}
+	s.SubscriptionAddOns = &addOns
+}
+
+// SetShippingAddressID moves the subscription to the account's existing
+// shipping address with the given id.
+func (s *UpdateSubscription) SetShippingAddressID(id int) {
+	s.ShippingAddressID = NewInt(id)
+	s.ShippingAddress = nil
+}
+
+// SetManualCollection switches the subscription to manual collection.
+// Recurly expects net terms and the PO number to be sent along with the
+// collection method, otherwise net terms can be reset to 0.
+func (s *UpdateSubscription) SetManualCollection(netTerms int, poNumber string) {
+	s.CollectionMethod = CollectionMethodManual
+	s.NetTerms = NewInt(netTerms)
+	s.PONumber = poNumber
+}
+
+// SetAutomaticCollection switches the subscription to automatic collection
+// and clears the net terms and PO number, which only apply to manual
+// collection.
+func (s *UpdateSubscription) SetAutomaticCollection() {
+	s.CollectionMethod = CollectionMethodAutomatic
+	s.NetTerms = NullInt{}
+	s.PONumber = ""
+}
+
+// SetPreserveTrial keeps sub's remaining trial across the update when
+// preserve is true and sub is in a trial, by sending its current trial end.
+// Otherwise the trial end is cleared and Recurly's default applies.
+func (s *UpdateSubscription) SetPreserveTrial(sub Subscription, preserve bool) {
+	if preserve && sub.InTrial() {
+		s.TrialEndsAt = sub.TrialEndsAt
+	} else {
+		s.TrialEndsAt = NullTime{}
+	}
+}
+
+// Validate checks the collection method and shipping options of the new
+// subscription before it is sent to Recurly.
+func (s NewSubscription) Validate() error {
+	if s.ShippingAmountInCents < 0 {
+		return fmt.Errorf("recurly: invalid shipping amount %d", s.ShippingAmountInCents)
+	} else if s.ShippingAmountInCents > 0 && s.ShippingMethodCode == "" {
+		return errors.New("recurly: shipping amount requires a shipping method code")
+	} else if s.ShippingAddressID.Valid && s.ShippingAddress != nil {
+		return errors.New("recurly: set either a shipping address id or a shipping address, not both")
+	}
+
+	return ValidateCollectionMethod(s.CollectionMethod)
+}
+
+// Validate checks the timeframe, proration, and collection method options of
+// the update before it is sent to Recurly.
+func (s UpdateSubscription) Validate() error {
+	switch s.Timeframe {
+	case "", SubscriptionTimeframeNow, SubscriptionTimeframeRenewal, SubscriptionTimeframeBillDate, SubscriptionTimeframeTermEnd:
+	default:
+		return fmt.Errorf("recurly: invalid subscription timeframe %q", s.Timeframe)
+	}
+
+	switch s.RevenueScheduleType {
+	case "", RevenueScheduleTypeNever, RevenueScheduleTypeEvenly, RevenueScheduleTypeAtRangeStart, RevenueScheduleTypeAtRangeEnd, RevenueScheduleTypeAtInvoice:
+	default:
+		return fmt.Errorf("recurly: invalid revenue schedule type %q", s.RevenueScheduleType)
+	}
+
+	if s.ChargeNow.Is(true) && s.Timeframe != SubscriptionTimeframeNow {
+		return fmt.Errorf("recurly: charge_now requires the %q timeframe", SubscriptionTimeframeNow)
+	}
+
+	return ValidateCollectionMethod(s.CollectionMethod)
+}
+
+// SubscriptionChangePreview holds the decoded result of a PreviewChange call
+// so that it can be stored in a PreviewCache.
+type SubscriptionChangePreview struct {
+	Subscription *Subscription
+
+	// Invoice is the previewed invoice, or nil if the response did not
+	// include one.
+	Invoice *Invoice
+}
+
+// AddOnCharges returns the previewed charge in cents for each add-on,
+// keyed by add-on code, such as the prorated charge for an add-on added
+// mid-term. Add-on line items are matched by their add_on origin and their
+// product code, which Recurly sets to the add-on code. Amounts are line item
+// totals, so they include discounts and tax. It returns nil if there is no
+// previewed invoice.
+func (p SubscriptionChangePreview) AddOnCharges() map[string]int {
+	if p.Invoice == nil {
+		return nil
+	}
+
+	charges := make(map[string]int)
+	for _, a := range p.Invoice.LineItems {
+		if a.Origin != AdjustmentOriginAddOn || a.ProductCode == "" {
+			continue
+		}
+		charges[a.ProductCode] += a.TotalInCents
+	}
+	return charges
+}
+
+// PreviewCache caches subscription change previews. Keys are computed by the
+// library from the subscription uuid and the encoded UpdateSubscription, so
+// identical previews map to the same key. Subscriptions.PreviewChangeWithCache
+// copies previews on the way in and out, so a cache may hand out the stored
+// value and callers may modify what they are returned.
+type PreviewCache interface {
+	Get(key string) (*SubscriptionChangePreview, bool)
+	Set(key string, p *SubscriptionChangePreview)
+}
+
+// copySubscription returns a copy of s that shares no slices, maps or
+// pointers with it, so either can be modified without affecting the other.
+func copySubscription(s *Subscription) *Subscription {
+	c := *s
+	c.SubscriptionAddOns = copySubscriptionAddOns(s.SubscriptionAddOns)
+	if s.PendingSubscription != nil {
+		pending := *s.PendingSubscription
+		pending.SubscriptionAddOns = copySubscriptionAddOns(pending.SubscriptionAddOns)
+		c.PendingSubscription = &pending
+	}
+	if s.CouponRedemptions != nil {
+		c.CouponRedemptions = append([]Redemption(nil), s.CouponRedemptions...)
+	}
+	if s.Extra != nil {
+		c.Extra = make(map[string]string, len(s.Extra))
+		for k, v := range s.Extra {
+			c.Extra[k] = v
+		}
+	}
+	return &c
+}
+
+// copySubscriptionAddOns returns a copy of addOns, including their tiers.
+func copySubscriptionAddOns(addOns []SubscriptionAddOn) []SubscriptionAddOn {
+	if addOns == nil {
+		return nil
+	}
+
+	c := append([]SubscriptionAddOn(nil), addOns...)
+	for i, a := range c {
+		if a.Tiers != nil {
+			tiers := append([]SubscriptionAddOnTier(nil), *a.Tiers...)
+			c[i].Tiers = &tiers
+		}
+	}
+	return c
+}
+
+// ReactivateOptions are used with ReactivateWithOptions. Recurly reactivates
+// a canceled subscription immediately and does not charge for it; the
+// subscription renews at the end of its current term instead of expiring.
+// Expired subscriptions cannot be reactivated.
+type ReactivateOptions struct {
+	// NextRenewalDate, if set, postpones the next renewal of the
+	// reactivated subscription to this date, delaying its next charge.
+	// It must be in the future.
+	NextRenewalDate time.Time
+
+	// Bulk is sent with the postponement when NextRenewalDate is set.
+	// See SubscriptionsService.Postpone.
+	Bulk bool
+}
+
+// Validate checks the options before any request is sent to Recurly.
+func (o ReactivateOptions) Validate() error {
+	if !o.NextRenewalDate.IsZero() && !o.NextRenewalDate.After(time.Now()) {
+		return fmt.Errorf("recurly: next renewal date %s is not in the future", o.NextRenewalDate.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ListWithInvoicesOptions are used with ListWithInvoices.
+type ListWithInvoicesOptions struct {
+	// Params are passed through when listing subscriptions.
+	Params Params
+
+	// Concurrency is the maximum number of invoices looked up at once.
+	// It defaults to DefaultInvoiceConcurrency.
+	Concurrency int
+}
+
+// DefaultInvoiceConcurrency is the number of invoices ListWithInvoices looks
+// up at once when ListWithInvoicesOptions.Concurrency is not set.
+const DefaultInvoiceConcurrency = 4
+
+// SubscriptionWithInvoice pairs a subscription with the invoice it links to.
+// Invoice is nil if the subscription has no invoice or it wasn't found.
+type SubscriptionWithInvoice struct {
+	Subscription Subscription
+	Invoice      *Invoice
 }
 
 // SubscriptionNotes is used to update a subscription's notes.
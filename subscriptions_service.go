@@ -1,9 +1,15 @@
 package recurly
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -17,42 +23,178 @@ type subscriptionsImpl struct {
 
 // List returns a list of all the subscriptions.
 // https://docs.recurly.com/api/subscriptions#list-subscriptions
-func (s *subscriptionsImpl) List(params Params) (*Response, []Subscription, error) {
+func (s *subscriptionsImpl) List(params Params, opts ...RequestOption) (*Response, []Subscription, error) {
 	req, err := s.client.newRequest("GET", "subscriptions", params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var v struct {
-		XMLName       xml.Name       `xml:"subscriptions"`
-		Subscriptions []Subscription `xml:"subscription"`
+	return doList[Subscription](s.client, req, "subscriptions", "subscription", opts...)
+}
+
+// Stream lists all subscriptions matching params, following the cursor
+// across pages. Subscriptions are sent on the returned channel as they are
+// decoded, so neither a page nor the full list is held in memory. Both
+// channels are closed when the last page is done or on the first error,
+// which is sent on the error channel. Canceling ctx stops the stream.
+func (s *subscriptionsImpl) Stream(ctx context.Context, params Params, opts ...RequestOption) (<-chan Subscription, <-chan error) {
+	subs := make(chan Subscription)
+	errs := make(chan error, 1)
+	opts = append(opts[:len(opts):len(opts)], WithContext(ctx))
+
+	go func() {
+		defer close(errs)
+		defer close(subs)
+
+		p := Params{}
+		for k, v := range params {
+			p[k] = v
+		}
+		for {
+			req, err := s.client.newRequest("GET", "subscriptions", p, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			resp, err := s.client.do(req, streamList(ctx, "subscription", subs), opts...)
+			if err != nil {
+				errs <- err
+				return
+			} else if resp.IsError() {
+				errs <- fmt.Errorf("recurly: streaming subscriptions failed with status %d", resp.StatusCode)
+				return
+			}
+
+			cursor := resp.Next()
+			if cursor == "" {
+				return
+			}
+			p["cursor"] = cursor
+		}
+	}()
+
+	return subs, errs
+}
+
+// UpcomingRenewals returns the live subscriptions whose current period ends
+// between now and within from now, listing every page with Stream.
+// Canceled subscriptions are skipped, as they expire instead of renewing,
+// as are subscriptions without a current period end.
+func (s *subscriptionsImpl) UpcomingRenewals(ctx context.Context, within time.Duration, opts ...RequestOption) ([]Subscription, error) {
+	now := time.Now()
+	end := now.Add(within)
+
+	subs, errs := s.Stream(ctx, Params{"state": SubscriptionStateLive}, opts...)
+	var dst []Subscription
+	for sub := range subs {
+		endsAt := sub.CurrentPeriodEndsAt.Time
+		if sub.State == SubscriptionStateCanceled || endsAt == nil || endsAt.Before(now) || endsAt.After(end) {
+			continue
+		}
+		dst = append(dst, sub)
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// ListWithInvoices returns a list of subscriptions like List, along with the
+// invoice each subscription links to. Invoices are looked up concurrently by
+// at most o.Concurrency workers. An invoice that isn't found is left nil.
+// If ctx is canceled or an invoice lookup fails, including with an error
+// response, the remaining lookups are abandoned and the error is returned.
+// The returned response is from listing the subscriptions.
+func (s *subscriptionsImpl) ListWithInvoices(ctx context.Context, o ListWithInvoicesOptions, opts ...RequestOption) (*Response, []SubscriptionWithInvoice, error) {
+	opts = append(opts[:len(opts):len(opts)], WithContext(ctx))
+	resp, subs, err := s.List(o.Params, opts...)
+	if err != nil || resp.IsError() {
+		return resp, nil, err
+	}
+
+	concurrency := o.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultInvoiceConcurrency
+	}
+
+	dst := make([]SubscriptionWithInvoice, len(subs))
+	for i, sub := range subs {
+		dst[i].Subscription = sub
+	}
+
+	lookupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	invoices := &invoicesImpl{client: s.client}
+	lookupOpts := append(opts[:len(opts):len(opts)], WithContext(lookupCtx))
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	jobs := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				number := dst[i].Subscription.InvoiceNumber
+				resp, invoice, err := invoices.get(strconv.Itoa(number), lookupOpts...)
+				if err == nil && resp.IsError() && resp.StatusCode != http.StatusNotFound {
+					err = fmt.Errorf("recurly: unable to look up invoice %d: %s", number, resp.Status)
+				}
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				dst[i].Invoice = invoice
+			}
+		}()
+	}
+
+send:
+	for i := range dst {
+		if dst[i].Subscription.InvoiceNumber == 0 {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-lookupCtx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return resp, nil, err
+	} else if firstErr != nil {
+		return resp, nil, firstErr
 	}
-	resp, err := s.client.do(req, &v)
 
-	return resp, v.Subscriptions, err
+	return resp, dst, nil
 }
 
 // ListAccount returns a list of subscriptions for an account.
 // https://docs.recurly.com/api/subscriptions#list-account-subscriptions
-func (s *subscriptionsImpl) ListAccount(accountCode string, params Params) (*Response, []Subscription, error) {
+func (s *subscriptionsImpl) ListAccount(accountCode string, params Params, opts ...RequestOption) (*Response, []Subscription, error) {
 	action := fmt.Sprintf("accounts/%s/subscriptions", accountCode)
 	req, err := s.client.newRequest("GET", action, params, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var v struct {
-		XMLName       xml.Name       `xml:"subscriptions"`
-		Subscriptions []Subscription `xml:"subscription"`
-	}
-	resp, err := s.client.do(req, &v)
-
-	return resp, v.Subscriptions, err
+	return doList[Subscription](s.client, req, "subscriptions", "subscription", opts...)
 }
 
-// Get returns a subscription by uuid
+// Get returns a subscription by uuid.
 // https://docs.recurly.com/api/subscriptions#lookup-subscription
-func (s *subscriptionsImpl) Get(uuid string) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) Get(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
 	action := fmt.Sprintf("subscriptions/%s", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("GET", action, nil, nil)
 	if err != nil {
@@ -60,7 +202,7 @@ func (s *subscriptionsImpl) Get(uuid string) (*Response, *Subscription, error) {
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 	if err != nil || resp.StatusCode >= http.StatusBadRequest {
 		return resp, nil, err
 	}
@@ -68,9 +210,84 @@ func (s *subscriptionsImpl) Get(uuid string) (*Response, *Subscription, error) {
 	return resp, &dst, err
 }
 
-// Create creates a new subscription.
+// GetWithAddOnNames returns a subscription by uuid like Get, and fills in
+// the Name of each of its add ons, and those of its pending subscription,
+// from the plan's add ons. Each plan is looked up once, with opts applied to
+// each request. Account level add ons are left without a name. If a plan
+// lookup fails, the subscription response and subscription are returned
+// with the error.
+func (s *subscriptionsImpl) GetWithAddOnNames(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
+	resp, sub, err := s.Get(uuid, opts...)
+	if err != nil || sub == nil {
+		return resp, sub, err
+	}
+
+	return resp, sub, s.fillAddOnNames(sub, opts...)
+}
+
+// fillAddOnNames sets the names of sub's add ons, and those of its pending
+// subscription, from their plans. Each plan is fetched once.
+func (s *subscriptionsImpl) fillAddOnNames(sub *Subscription, opts ...RequestOption) error {
+	plansService := &plansImpl{client: s.client}
+	plans := make(map[string]map[string]string)
+	fill := func(planCode string, addOns []SubscriptionAddOn) error {
+		if len(addOns) == 0 {
+			return nil
+		}
+
+		names, ok := plans[planCode]
+		if !ok {
+			resp, plan, err := plansService.getWithAddOns(planCode, opts...)
+			if err != nil {
+				return err
+			} else if resp.IsError() {
+				return fmt.Errorf("recurly: unable to look up plan %q: %s", planCode, resp.Status)
+			}
+
+			names = make(map[string]string)
+			for _, a := range plan.AddOns {
+				names[a.Code] = a.Name
+			}
+			plans[planCode] = names
+		}
+
+		for i := range addOns {
+			if addOns[i].AddOnSource != AddOnSourceAccount {
+				addOns[i].Name = names[addOns[i].Code]
+			}
+		}
+		return nil
+	}
+
+	if err := fill(sub.Plan.Code, sub.SubscriptionAddOns); err != nil {
+		return err
+	}
+	if p := sub.PendingSubscription; p != nil {
+		planCode := p.Plan.Code
+		if planCode == "" {
+			planCode = sub.Plan.Code
+		}
+		return fill(planCode, p.SubscriptionAddOns)
+	}
+	return nil
+}
+
+// Create creates a new subscription. The subscription is validated with
+// NewSubscription.Validate before it is sent. If sub.Currency is empty, the
+// client's DefaultCurrency is used. When the subscription is rejected, the
+// response's Errors hold each failed field; Transaction is only set if a
+// payment was attempted and declined.
 // https://docs.recurly.com/api/subscriptions#create-subscription
-func (s *subscriptionsImpl) Create(sub NewSubscription) (*Response, *NewSubscriptionResponse, error) {
+func (s *subscriptionsImpl) Create(sub NewSubscription, opts ...RequestOption) (*Response, *NewSubscriptionResponse, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	sub.Currency = s.client.currency(sub.Currency)
+	if err := s.client.validateCurrency(sub.Currency); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.client.newRequest("POST", "subscriptions", nil, sub)
 	if err != nil {
 		return nil, nil, err
@@ -78,27 +295,88 @@ func (s *subscriptionsImpl) Create(sub NewSubscription) (*Response, *NewSubscrip
 
 	var dst NewSubscriptionResponse
 	var subscription Subscription
-	resp, err := s.client.do(req, &subscription)
+	resp, err := s.client.do(req, &subscription, opts...)
 	if subscription.UUID != "" { // If subscription not present, dst.Subscription should be nil
 		dst.Subscription = &subscription
 	}
-	if resp.transaction != nil {
+	if err != nil {
+		return resp, &dst, err
+	} else if resp.transaction != nil {
 		dst.Transaction = resp.transaction
 	}
 
-	return resp, &dst, err
+	return resp, &dst, nil
+}
+
+// CreateWithTransaction creates a new subscription like Create. Recurly
+// only returns the subscription on success, so when the subscription is
+// created the purchase transaction is then looked up from its invoice, with
+// an extra request, so that its CVV and AVS results can be checked on
+// approvals too. Transaction is nil if the invoice has no purchase. If the
+// lookup fails, the create response and subscription are returned with the
+// error.
+func (s *subscriptionsImpl) CreateWithTransaction(sub NewSubscription, opts ...RequestOption) (*Response, *NewSubscriptionResponse, error) {
+	resp, dst, err := s.Create(sub, opts...)
+	if err != nil || resp.IsError() || dst.Subscription == nil {
+		return resp, dst, err
+	}
+
+	dst.Transaction, err = s.purchaseTransaction(dst.Subscription.InvoiceNumber, opts...)
+	return resp, dst, err
+}
+
+// purchaseTransaction returns the purchase transaction of the invoice, such
+// as the one created for a new subscription, or nil if there is none.
+func (s *subscriptionsImpl) purchaseTransaction(invoiceNumber int, opts ...RequestOption) (*Transaction, error) {
+	if invoiceNumber == 0 {
+		return nil, nil
+	}
+
+	invoices := &invoicesImpl{client: s.client}
+	resp, invoice, err := invoices.get(strconv.Itoa(invoiceNumber), opts...)
+	if err != nil {
+		return nil, err
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("recurly: unable to look up invoice %d: %s", invoiceNumber, resp.Status)
+	}
+
+	for i := range invoice.Transactions {
+		if invoice.Transactions[i].Action == "purchase" {
+			return &invoice.Transactions[i], nil
+		}
+	}
+	return nil, nil
 }
 
-// Preview returns a preview for a new subscription applied to an account.
+// CreateForAccount creates a new subscription like Create for an existing
+// account. sub.Account is replaced with only the account code, so billing
+// info or other account fields set on sub are not sent.
+// https://docs.recurly.com/api/subscriptions#create-subscription
+func (s *subscriptionsImpl) CreateForAccount(accountCode string, sub NewSubscription, opts ...RequestOption) (*Response, *NewSubscriptionResponse, error) {
+	sub.Account = Account{Code: accountCode}
+	return s.Create(sub, opts...)
+}
+
+// Preview returns a preview for a new subscription applied to an account,
+// including the totals of the invoice that would be created.
 // https://docs.recurly.com/api/subscriptions#preview-sub
-func (s *subscriptionsImpl) Preview(sub NewSubscription) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) Preview(sub NewSubscription, opts ...RequestOption) (*Response, *SubscriptionPreview, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	sub.Currency = s.client.currency(sub.Currency)
+	if err := s.client.validateCurrency(sub.Currency); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.client.newRequest("POST", "subscriptions/preview", nil, sub)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	var dst SubscriptionPreview
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
@@ -107,9 +385,14 @@ func (s *subscriptionsImpl) Preview(sub NewSubscription) (*Response, *Subscripti
 // Note: SubscriptionAddOns MUST be set to retain previous values. It's recommended you
 // copy these over from a Subscription object, or use the data you have to recreate them
 // identically. If updating SubscriptionAddOns, you should provide the entire replacement
-// value. See recurly documentation for more info.
+// value. The update is validated with UpdateSubscription.Validate before it is
+// sent. See recurly documentation for more info.
 // https://docs.recurly.com/api/subscriptions#update-subscription
-func (s *subscriptionsImpl) Update(uuid string, sub UpdateSubscription) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) Update(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *Subscription, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, sub)
 	if err != nil {
@@ -117,15 +400,81 @@ func (s *subscriptionsImpl) Update(uuid string, sub UpdateSubscription) (*Respon
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
 
+// IncrementAddOn changes the quantity of the add on addOnCode by delta,
+// keeping the subscription's other add ons as they are. If the subscription
+// doesn't have the add on, it is added with a quantity of delta at the plan's
+// price. If the quantity falls to zero the add on is removed.
+//
+// The subscription is fetched and then updated with the full set of add ons,
+// so concurrent changes to the same subscription's add ons may be lost.
+// If either request returns an error status, that response is returned
+// with a nil subscription.
+func (s *subscriptionsImpl) IncrementAddOn(uuid string, addOnCode string, delta int, opts ...RequestOption) (*Response, *Subscription, error) {
+	resp, sub, err := s.Get(uuid, opts...)
+	if err != nil || sub == nil {
+		return resp, nil, err
+	}
+
+	update := sub.MakeUpdate()
+	addOns := make([]SubscriptionAddOn, 0, len(sub.SubscriptionAddOns)+1)
+	found := false
+	for _, a := range sub.SubscriptionAddOns {
+		if a.Code == addOnCode {
+			found = true
+			a.Quantity += delta
+			if a.Quantity < 0 {
+				return nil, nil, fmt.Errorf("recurly: add on %q quantity cannot be negative", addOnCode)
+			} else if a.Quantity == 0 {
+				continue
+			}
+		}
+		addOns = append(addOns, a)
+	}
+
+	if !found {
+		if delta <= 0 {
+			return nil, nil, fmt.Errorf("recurly: subscription has no add on %q to decrement", addOnCode)
+		}
+
+		resp, addOn, err := s.client.AddOns.Get(sub.Plan.Code, addOnCode)
+		if err != nil || addOn == nil {
+			return resp, nil, err
+		}
+
+		var price int
+		switch sub.Currency {
+		case "USD":
+			price = addOn.UnitAmountInCents.USD
+		case "EUR":
+			price = addOn.UnitAmountInCents.EUR
+		default:
+			return nil, nil, fmt.Errorf("recurly: add on prices in %s are not supported", sub.Currency)
+		}
+		addOns = append(addOns, SubscriptionAddOn{
+			Code:              addOnCode,
+			UnitAmountInCents: price,
+			Quantity:          delta,
+		})
+	}
+	update.SubscriptionAddOns = &addOns
+
+	resp, dst, err := s.Update(uuid, update, opts...)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, err
+	}
+
+	return resp, dst, err
+}
+
 // UpdateNotes updates a subscription's invoice notes before the next renewal.
 // Updating notes will not trigger the renewal.
 // https://docs.recurly.com/api/subscriptions#update-subscription-notes
-func (s *subscriptionsImpl) UpdateNotes(uuid string, n SubscriptionNotes) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) UpdateNotes(uuid string, n SubscriptionNotes, opts ...RequestOption) (*Response, *Subscription, error) {
 	action := fmt.Sprintf("subscriptions/%s/notes", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, n)
 	if err != nil {
@@ -133,7 +482,7 @@ func (s *subscriptionsImpl) UpdateNotes(uuid string, n SubscriptionNotes) (*Resp
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
@@ -141,23 +490,121 @@ func (s *subscriptionsImpl) UpdateNotes(uuid string, n SubscriptionNotes) (*Resp
 // PreviewChange returns a preview for a subscription change applied to an
 // account without committing a subscription change or posting an invoice.
 // https://docs.recurly.com/api/subscriptions#sub-change-preview
-func (s *subscriptionsImpl) PreviewChange(uuid string, sub UpdateSubscription) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) PreviewChange(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *Subscription, error) {
+	resp, p, err := s.previewChange(uuid, sub, opts...)
+	if p == nil {
+		return resp, nil, err
+	}
+	return resp, &p.Subscription, err
+}
+
+// PreviewChangeDetails works like PreviewChange but also returns the
+// previewed invoice, so charges can be broken down by line. See
+// SubscriptionChangePreview.AddOnCharges.
+func (s *subscriptionsImpl) PreviewChangeDetails(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *SubscriptionChangePreview, error) {
+	resp, p, err := s.previewChange(uuid, sub, opts...)
+	if p == nil {
+		return resp, nil, err
+	}
+	return resp, &SubscriptionChangePreview{
+		Subscription: &p.Subscription,
+		Invoice:      p.Invoice,
+	}, err
+}
+
+// previewChange posts the change preview and decodes the subscription along
+// with the previewed invoice.
+func (s *subscriptionsImpl) previewChange(uuid string, sub UpdateSubscription, opts ...RequestOption) (*Response, *SubscriptionPreview, error) {
+	if err := sub.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/preview", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("POST", action, nil, sub)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	var dst SubscriptionPreview
+	resp, err := s.client.do(req, &dst, opts...)
+
+	return resp, &dst, err
+}
+
+// PreviewChangeWithCache works like PreviewChange but first checks cache for
+// a preview of an identical change. Successful previews are stored in cache,
+// and a cache hit returns a copy of the stored subscription without making an
+// API call. Because no request is made on a hit, the returned Response is a
+// synthesized 200 OK with no body and an Attempts count of 0. If cache is nil,
+// it behaves exactly like PreviewChange.
+func (s *subscriptionsImpl) PreviewChangeWithCache(uuid string, sub UpdateSubscription, cache PreviewCache, opts ...RequestOption) (*Response, *Subscription, error) {
+	if cache == nil {
+		return s.PreviewChange(uuid, sub, opts...)
+	}
+
+	key, err := previewCacheKey(uuid, sub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p, ok := cache.Get(key); ok && p != nil && p.Subscription != nil {
+		return &Response{Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       http.NoBody,
+		}}, copySubscription(p.Subscription), nil
+	}
+
+	resp, p, err := s.PreviewChangeDetails(uuid, sub, opts...)
+	if p == nil {
+		return resp, nil, err
+	} else if err == nil && resp.IsOK() {
+		cache.Set(key, &SubscriptionChangePreview{
+			Subscription: copySubscription(p.Subscription),
+			Invoice:      p.Invoice,
+		})
+	}
+
+	return resp, p.Subscription, err
+}
+
+// PreviewRenewal returns a preview of the invoice that will be created when
+// the subscription next renews, including any usage recorded so far in the
+// current period. Nothing is invoiced or collected.
+// https://dev.recurly.com/docs/preview-subscription-renewal
+func (s *subscriptionsImpl) PreviewRenewal(uuid string, opts ...RequestOption) (*Response, *InvoiceCollection, error) {
+	action := fmt.Sprintf("subscriptions/%s/preview_renewal", SanitizeUUID(uuid))
+	req, err := s.client.newRequest("POST", action, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst InvoiceCollection
+	resp, err := s.client.do(req, &dst, opts...)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, nil, err
+	}
 
 	return resp, &dst, err
 }
 
+// previewCacheKey returns a hex encoded sha256 hash of the sanitized uuid
+// and the XML encoded subscription change.
+func previewCacheKey(uuid string, sub UpdateSubscription) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, SanitizeUUID(uuid))
+	if err := xml.NewEncoder(h).Encode(sub); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // Cancel cancels a subscription so it remains active and then expires at the
 // end of the current bill cycle.
 // https://docs.recurly.com/api/subscriptions#cancel-subscription
-func (s *subscriptionsImpl) Cancel(uuid string) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) Cancel(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
 	action := fmt.Sprintf("subscriptions/%s/cancel", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, nil)
 	if err != nil {
@@ -165,15 +612,51 @@ func (s *subscriptionsImpl) Cancel(uuid string) (*Response, *Subscription, error
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
 
+// errorSymbolInvalidTransition is returned when a subscription can't move to
+// the requested state, such as canceling a subscription that is already
+// canceled.
+const errorSymbolInvalidTransition = "invalid_transition"
+
+// CancelIfActive cancels a subscription like Cancel, but succeeds without
+// error if the subscription is already canceled or expired. This makes it
+// safe to call more than once. When Recurly rejects the cancelation as an
+// invalid transition, the subscription is looked up to confirm its state and
+// the lookup is returned. Any other failure is returned as from Cancel.
+func (s *subscriptionsImpl) CancelIfActive(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
+	resp, dst, err := s.Cancel(uuid, opts...)
+	if err != nil || !resp.IsClientError() || !hasErrorSymbol(resp, errorSymbolInvalidTransition) {
+		return resp, dst, err
+	}
+
+	getResp, sub, err := s.Get(uuid, opts...)
+	if err != nil {
+		return getResp, nil, err
+	} else if sub != nil && (sub.State == SubscriptionStateCanceled || sub.State == SubscriptionStateExpired) {
+		return getResp, sub, nil
+	}
+
+	return resp, dst, nil
+}
+
+// hasErrorSymbol returns true if any of the response's errors has symbol.
+func hasErrorSymbol(resp *Response, symbol string) bool {
+	for _, e := range resp.Errors {
+		if e.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
 // Reactivate will reactivate a canceled subscription so it renews at the end
 // of the current bill cycle.
 // https://docs.recurly.com/api/subscriptions#reactivate-subscription
-func (s *subscriptionsImpl) Reactivate(uuid string) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) Reactivate(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
 	action := fmt.Sprintf("subscriptions/%s/reactivate", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, nil)
 	if err != nil {
@@ -181,55 +664,136 @@ func (s *subscriptionsImpl) Reactivate(uuid string) (*Response, *Subscription, e
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
 
-// TerminateWithPartialRefund will terminate the active subscription
-// immediately with a full refund.
-// https://docs.recurly.com/api/subscriptions#terminate-subscription
-func (s *subscriptionsImpl) TerminateWithPartialRefund(uuid string) (*Response, *Subscription, error) {
-	action := fmt.Sprintf("subscriptions/%s/terminate", SanitizeUUID(uuid))
-	req, err := s.client.newRequest("PUT", action, Params{"refund_type": "partial"}, nil)
-	if err != nil {
+// ReactivateWithOptions reactivates a canceled subscription like Reactivate,
+// then postpones its next renewal if o.NextRenewalDate is set. The returned
+// subscription reflects the new renewal date. If the postponement fails, the
+// subscription remains reactivated and the postpone response is returned.
+func (s *subscriptionsImpl) ReactivateWithOptions(uuid string, o ReactivateOptions, opts ...RequestOption) (*Response, *Subscription, error) {
+	if err := o.Validate(); err != nil {
 		return nil, nil, err
 	}
 
-	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, dst, err := s.Reactivate(uuid, opts...)
+	if err != nil || resp.IsError() || o.NextRenewalDate.IsZero() {
+		return resp, dst, err
+	}
 
-	return resp, &dst, err
+	return s.Postpone(uuid, o.NextRenewalDate, o.Bulk, opts...)
 }
 
-// TerminateWithFullRefund will terminate the active subscription
+// TerminateWithPartialRefund will terminate the active subscription
 // immediately with a full refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
-func (s *subscriptionsImpl) TerminateWithFullRefund(uuid string) (*Response, *Subscription, error) {
-	action := fmt.Sprintf("subscriptions/%s/terminate", SanitizeUUID(uuid))
-	req, err := s.client.newRequest("PUT", action, Params{"refund_type": "full"}, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+func (s *subscriptionsImpl) TerminateWithPartialRefund(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
+	return s.terminate(uuid, RefundTypePartial, opts...)
+}
 
-	return resp, &dst, err
+// TerminateWithFullRefund will terminate the active subscription
+// immediately with a full refund.
+// https://docs.recurly.com/api/subscriptions#terminate-subscription
+func (s *subscriptionsImpl) TerminateWithFullRefund(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
+	return s.terminate(uuid, RefundTypeFull, opts...)
 }
 
 // TerminateWithoutRefund will terminate the active subscription
 // immediately with no refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
-func (s *subscriptionsImpl) TerminateWithoutRefund(uuid string) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) TerminateWithoutRefund(uuid string, opts ...RequestOption) (*Response, *Subscription, error) {
+	return s.terminate(uuid, RefundTypeNone, opts...)
+}
+
+// TerminateWithResult terminates the active subscription immediately with
+// the given refund type and reports the refund that was issued. Recurly
+// only returns the subscription from the terminate call, so for full and
+// partial refunds the account's refund transactions are listed, newest
+// first and page by page, and the newest one for the subscription is
+// returned. Refunds created well before the subscription expired, by
+// Recurly's clock, are from earlier changes and are not matched.
+//
+// The returned response is always the terminate response. If the refund
+// can't be listed, or a refund was requested but none was found, the result
+// holds the terminated subscription and the error, ErrRefundNotFound in the
+// latter case, is returned.
+// https://docs.recurly.com/api/subscriptions#terminate-subscription
+func (s *subscriptionsImpl) TerminateWithResult(uuid string, refundType string, opts ...RequestOption) (*Response, *TerminateResult, error) {
+	switch refundType {
+	case RefundTypeFull, RefundTypePartial, RefundTypeNone:
+	default:
+		return nil, nil, fmt.Errorf("recurly: invalid refund type %q", refundType)
+	}
+
+	resp, sub, err := s.terminate(uuid, refundType, opts...)
+	if err != nil || resp.IsError() {
+		return resp, nil, err
+	}
+
+	result := &TerminateResult{Subscription: sub}
+	if refundType == RefundTypeNone {
+		return resp, result, nil
+	}
+
+	// Use Recurly's time of termination rather than the local clock, which
+	// may not agree with Recurly's.
+	var after time.Time
+	if sub.ExpiresAt.Time != nil {
+		after = sub.ExpiresAt.Time.Add(-terminateRefundWindow)
+	} else if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		after = date.Add(-terminateRefundWindow)
+	}
+
+	subUUID := SanitizeUUID(uuid)
+	params := NewParams().Set("type", "refund").Sort("created_at", "desc").PerPage(200)
+	for {
+		tResp, transactions, err := s.client.Accounts.ListTransactions(sub.AccountCode, params)
+		if err != nil {
+			return resp, result, err
+		} else if tResp.IsError() {
+			return resp, result, fmt.Errorf("recurly: unable to list refund transactions: %s", tResp.Status)
+		}
+
+		for i := range transactions {
+			t := &transactions[i]
+			if t.SubscriptionUUID != subUUID || t.Action != "refund" {
+				continue
+			} else if t.CreatedAt.Time != nil && t.CreatedAt.Time.Before(after) {
+				// Refunds are newest first, so the rest are older too.
+				return resp, result, ErrRefundNotFound
+			}
+			result.RefundTransaction = t
+			result.RefundInCents = t.AmountInCents
+			return resp, result, nil
+		}
+
+		cursor := tResp.Next()
+		if cursor == "" {
+			break
+		}
+		params = params.Cursor(cursor)
+	}
+
+	return resp, result, ErrRefundNotFound
+}
+
+// terminateRefundWindow is how long before a subscription's termination its
+// refund may be created and still be matched by TerminateWithResult.
+const terminateRefundWindow = time.Minute
+
+// terminate terminates the subscription immediately with the given refund
+// type and returns the terminated subscription.
+func (s *subscriptionsImpl) terminate(uuid string, refundType string, opts ...RequestOption) (*Response, *Subscription, error) {
 	action := fmt.Sprintf("subscriptions/%s/terminate", SanitizeUUID(uuid))
-	req, err := s.client.newRequest("PUT", action, Params{"refund_type": "none"}, nil)
+	req, err := s.client.newRequest("PUT", action, Params{"refund_type": refundType}, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
@@ -238,7 +802,7 @@ func (s *subscriptionsImpl) TerminateWithoutRefund(uuid string) (*Response, *Sub
 // The subscription will not be prorated. For a subscription in a trial period,
 // modifying the renewal date will modify when the trial expires.
 // https://docs.recurly.com/api/subscriptions#postpone-subscription
-func (s *subscriptionsImpl) Postpone(uuid string, dt time.Time, bulk bool) (*Response, *Subscription, error) {
+func (s *subscriptionsImpl) Postpone(uuid string, dt time.Time, bulk bool, opts ...RequestOption) (*Response, *Subscription, error) {
 	action := fmt.Sprintf("subscriptions/%s/postpone", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, Params{
 		"bulk":              bulk,
@@ -249,7 +813,7 @@ func (s *subscriptionsImpl) Postpone(uuid string, dt time.Time, bulk bool) (*Res
 	}
 
 	var dst Subscription
-	resp, err := s.client.do(req, &dst)
+	resp, err := s.client.do(req, &dst, opts...)
 
 	return resp, &dst, err
 }
@@ -2,10 +2,14 @@ package recurly_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -38,6 +42,19 @@ func TestSubscriptions_NewSubscription_Encoding(t *testing.T) {
 			},
 			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code><billing_info><token_id>507c7f79bcf86cd7994f6c0e</token_id></billing_info></account><currency></currency></subscription>",
 		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Account: recurly.Account{
+					Code: "123",
+					BillingInfo: &recurly.Billing{
+						Token:                           "507c7f79bcf86cd7994f6c0e",
+						ThreeDSecureActionResultTokenID: "8a5Gs9uOHBDZ3OSpN3Ow",
+					},
+				},
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code><billing_info><token_id>507c7f79bcf86cd7994f6c0e</token_id><three_d_secure_action_result_token_id>8a5Gs9uOHBDZ3OSpN3Ow</three_d_secure_action_result_token_id></billing_info></account><currency></currency></subscription>",
+		},
 		{
 			v: recurly.NewSubscription{
 				PlanCode: "gold",
@@ -55,6 +72,30 @@ func TestSubscriptions_NewSubscription_Encoding(t *testing.T) {
 			},
 			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><subscription_add_ons><subscription_add_on><add_on_code>extra_users</add_on_code><unit_amount_in_cents>1000</unit_amount_in_cents><quantity>2</quantity></subscription_add_on></subscription_add_ons><currency>USD</currency></subscription>",
 		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Currency: "USD",
+				Account: recurly.Account{
+					Code: "123",
+				},
+				SubscriptionAddOns: &[]recurly.SubscriptionAddOn{
+					{
+						Code:              "extra_users",
+						UnitAmountInCents: 1000,
+						Quantity:          2,
+						AddOnSource:       recurly.AddOnSourcePlan,
+					},
+					{
+						Code:              "priority_support",
+						UnitAmountInCents: 500,
+						Quantity:          1,
+						AddOnSource:       recurly.AddOnSourceAccount,
+					},
+				},
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><subscription_add_ons><subscription_add_on><add_on_code>extra_users</add_on_code><unit_amount_in_cents>1000</unit_amount_in_cents><quantity>2</quantity><add_on_source>plan_add_on</add_on_source></subscription_add_on><subscription_add_on><add_on_code>priority_support</add_on_code><unit_amount_in_cents>500</unit_amount_in_cents><quantity>1</quantity><add_on_source>account_add_on</add_on_source></subscription_add_on></subscription_add_ons><currency>USD</currency></subscription>",
+		},
 		{
 			v: recurly.NewSubscription{
 				PlanCode: "gold",
@@ -243,6 +284,75 @@ func TestSubscriptions_NewSubscription_Encoding(t *testing.T) {
 			},
 			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><bank_account_authorized_at>2015-06-03T13:42:23Z</bank_account_authorized_at></subscription>",
 		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Currency: "EUR",
+				Account: recurly.Account{
+					Code: "123",
+				},
+				GatewayCode: "eu_gateway",
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>EUR</currency><gateway_code>eu_gateway</gateway_code></subscription>",
+		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Currency: "USD",
+				Account: recurly.Account{
+					Code: "123",
+				},
+				DunningCampaignID: "ocd3oqf4nexm",
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><dunning_campaign_id>ocd3oqf4nexm</dunning_campaign_id></subscription>",
+		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Currency: "USD",
+				Account: recurly.Account{
+					Code: "123",
+				},
+				StartsAt:         recurly.NewTime(ts),
+				TrialEndsAt:      recurly.NewTime(ts.AddDate(0, 0, 14)),
+				ImportedTrial:    true,
+				FirstRenewalDate: recurly.NewTime(ts.AddDate(0, 1, 0)),
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><trial_ends_at>2015-06-17T13:42:23Z</trial_ends_at><imported_trial>true</imported_trial><starts_at>2015-06-03T13:42:23Z</starts_at><first_renewal_date>2015-07-03T13:42:23Z</first_renewal_date></subscription>",
+		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Currency: "USD",
+				Account: recurly.Account{
+					Code: "123",
+				},
+				ShippingAddress: &recurly.ShippingAddress{
+					FirstName: "Verena",
+					LastName:  "Example",
+					Address:   "123 Main St.",
+					City:      "San Francisco",
+					State:     "CA",
+					Zip:       "94105",
+					Country:   "US",
+				},
+				ShippingMethodCode:    "fedex_ground",
+				ShippingAmountInCents: 599,
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><shipping_address><first_name>Verena</first_name><last_name>Example</last_name><address1>123 Main St.</address1><city>San Francisco</city><state>CA</state><zip>94105</zip><country>US</country></shipping_address><shipping_method_code>fedex_ground</shipping_method_code><shipping_amount_in_cents>599</shipping_amount_in_cents></subscription>",
+		},
+		{
+			v: recurly.NewSubscription{
+				PlanCode: "gold",
+				Currency: "USD",
+				Account: recurly.Account{
+					Code: "123",
+				},
+				ShippingAddressID:  recurly.NewInt(2438622711411416831),
+				ShippingMethodCode: "free_shipping",
+			},
+			expected: "<subscription><plan_code>gold</plan_code><account><account_code>123</account_code></account><currency>USD</currency><shipping_address_id>2438622711411416831</shipping_address_id><shipping_method_code>free_shipping</shipping_method_code></subscription>",
+		},
 	}
 
 	for i, tt := range tests {
@@ -255,6 +365,371 @@ func TestSubscriptions_NewSubscription_Encoding(t *testing.T) {
 	}
 }
 
+func TestSubscriptions_Decode_AddOns(t *testing.T) {
+	tests := []struct {
+		xml      string
+		expected []recurly.SubscriptionAddOn
+	}{
+		// Absent
+		{
+			xml:      "<subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>",
+			expected: nil,
+		},
+		// Present but empty
+		{
+			xml:      `<subscription><subscription_add_ons type="array"></subscription_add_ons></subscription>`,
+			expected: []recurly.SubscriptionAddOn{},
+		},
+		{
+			xml:      `<subscription><subscription_add_ons type="array"/></subscription>`,
+			expected: []recurly.SubscriptionAddOn{},
+		},
+		// Populated
+		{
+			xml: `<subscription><subscription_add_ons type="array"><subscription_add_on><add_on_code>extra_users</add_on_code><quantity type="integer">2</quantity></subscription_add_on></subscription_add_ons></subscription>`,
+			expected: []recurly.SubscriptionAddOn{
+				{
+					XMLName:  xml.Name{Local: "subscription_add_on"},
+					Code:     "extra_users",
+					Quantity: 2,
+				},
+			},
+		},
+		// Usage based with tiers
+		{
+			xml: `<subscription><subscription_add_ons type="array"><subscription_add_on>
+				<add_on_type>usage</add_on_type>
+				<add_on_code>api_calls</add_on_code>
+				<quantity type="integer">1</quantity>
+				<add_on_source>plan_add_on</add_on_source>
+				<usage_type>price</usage_type>
+				<usage_percentage nil="nil"></usage_percentage>
+				<measured_unit_id type="integer">394681687402874853</measured_unit_id>
+				<tier_type>tiered</tier_type>
+				<tiers type="array">
+					<tier><ending_quantity type="integer">1000</ending_quantity><unit_amount_in_cents type="integer">10</unit_amount_in_cents></tier>
+					<tier><ending_quantity nil="nil"></ending_quantity><unit_amount_in_cents type="integer">5</unit_amount_in_cents></tier>
+				</tiers>
+			</subscription_add_on></subscription_add_ons></subscription>`,
+			expected: []recurly.SubscriptionAddOn{
+				{
+					XMLName:        xml.Name{Local: "subscription_add_on"},
+					Type:           "usage",
+					Code:           "api_calls",
+					Quantity:       1,
+					AddOnSource:    "plan_add_on",
+					UsageType:      "price",
+					MeasuredUnitID: 394681687402874853,
+					TierType:       "tiered",
+					Tiers: &[]recurly.SubscriptionAddOnTier{
+						{XMLName: xml.Name{Local: "tier"}, EndingQuantity: 1000, UnitAmountInCents: 10},
+						{XMLName: xml.Name{Local: "tier"}, UnitAmountInCents: 5},
+					},
+				},
+			},
+		},
+		{
+			xml: `<subscription><subscription_add_ons type="array"><subscription_add_on><add_on_type>usage</add_on_type><add_on_code>processing</add_on_code><usage_type>percentage</usage_type><usage_percentage type="float">2.5</usage_percentage></subscription_add_on></subscription_add_ons></subscription>`,
+			expected: []recurly.SubscriptionAddOn{
+				{
+					XMLName:         xml.Name{Local: "subscription_add_on"},
+					Type:            "usage",
+					Code:            "processing",
+					UsageType:       "percentage",
+					UsagePercentage: 2.5,
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		var sub recurly.Subscription
+		if err := xml.Unmarshal([]byte(tt.xml), &sub); err != nil {
+			t.Fatalf("(%d) unexpected error: %v", i, err)
+		} else if (sub.SubscriptionAddOns == nil) != (tt.expected == nil) {
+			t.Fatalf("(%d) unexpected nil add ons: %#v", i, sub.SubscriptionAddOns)
+		} else if !reflect.DeepEqual(sub.SubscriptionAddOns, tt.expected) {
+			t.Fatalf("(%d) unexpected add ons: %#v", i, sub.SubscriptionAddOns)
+		}
+	}
+}
+
+func TestSubscriptions_Decode_PendingSubscription(t *testing.T) {
+	var sub recurly.Subscription
+	if err := xml.Unmarshal([]byte(`<subscription>
+		<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+		<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
+		<quantity type="integer">1</quantity>
+		<pending_subscription type="subscription">
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				<plan_code>gold</plan_code>
+				<name>Gold plan</name>
+			</plan>
+			<unit_amount_in_cents type="integer">1200</unit_amount_in_cents>
+			<quantity type="integer">3</quantity>
+			<total_amount_in_cents type="integer">3600</total_amount_in_cents>
+			<collection_method>manual</collection_method>
+			<coupon_code>spring</coupon_code>
+			<subscription_add_ons type="array"></subscription_add_ons>
+		</pending_subscription>
+	</subscription>`), &sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(sub.PendingSubscription, &recurly.PendingSubscription{
+		XMLName: xml.Name{Local: "pending_subscription"},
+		Plan: recurly.NestedPlan{
+			Code: "gold",
+			Name: "Gold plan",
+		},
+		Quantity:           recurly.NewInt(3),
+		Price:              recurly.NewInt(1200),
+		TotalAmountInCents: 3600,
+		CollectionMethod:   recurly.CollectionMethodManual,
+		CouponCode:         "spring",
+		SubscriptionAddOns: []recurly.SubscriptionAddOn{},
+	}) {
+		t.Fatalf("unexpected pending subscription: %#v", sub.PendingSubscription)
+	} else if !sub.HasPendingChange() {
+		t.Fatal("expected pending change")
+	} else if sub.NextPeriodAmount() != 3600 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+}
+
+func TestSubscriptions_DiscountedAmountInCents(t *testing.T) {
+	sub := recurly.Subscription{
+		Plan:              recurly.NestedPlan{Code: "gold"},
+		UnitAmountInCents: 1000,
+		Currency:          "USD",
+		Quantity:          2,
+		SubscriptionAddOns: []recurly.SubscriptionAddOn{
+			{Code: "support", UnitAmountInCents: 500, Quantity: 1},
+		},
+		CouponRedemptions: []recurly.Redemption{
+			{CouponCode: "tenoff", State: recurly.RedemptionStateActive},
+			{CouponCode: "fiveoff", State: recurly.RedemptionStateActive},
+			{CouponCode: "expired", State: recurly.RedemptionStateInactive},
+		},
+	}
+	percent := recurly.Coupon{Code: "tenoff", DiscountType: recurly.CouponDiscountTypePercent, DiscountPercent: 10}
+	dollars := recurly.Coupon{Code: "fiveoff", DiscountType: recurly.CouponDiscountTypeDollars, DiscountInCents: recurly.UnitAmount{USD: 500}}
+	expired := recurly.Coupon{Code: "expired", DiscountType: recurly.CouponDiscountTypePercent, DiscountPercent: 50}
+	trial := recurly.Coupon{Code: "tenoff", DiscountType: recurly.CouponDiscountTypeFreeTrial}
+	otherPlan := recurly.Coupon{
+		Code:              "tenoff",
+		DiscountType:      recurly.CouponDiscountTypePercent,
+		DiscountPercent:   10,
+		AppliesToAllPlans: recurly.NewBool(false),
+		PlanCodes:         &[]recurly.CouponPlanCode{{Code: "silver"}},
+	}
+	huge := recurly.Coupon{Code: "fiveoff", DiscountType: recurly.CouponDiscountTypeDollars, DiscountInCents: recurly.UnitAmount{USD: 10000}}
+
+	tests := []struct {
+		coupons  []recurly.Coupon
+		expected int
+	}{
+		{expected: 2500},
+		{coupons: []recurly.Coupon{percent}, expected: 2250},
+		{coupons: []recurly.Coupon{dollars}, expected: 2000},
+		{coupons: []recurly.Coupon{percent, dollars}, expected: 1750},
+		{coupons: []recurly.Coupon{expired}, expected: 2500},
+		{coupons: []recurly.Coupon{trial}, expected: 2500},
+		{coupons: []recurly.Coupon{otherPlan}, expected: 2500},
+		{coupons: []recurly.Coupon{huge}, expected: 0},
+	}
+	for i, tt := range tests {
+		if given := sub.DiscountedAmountInCents(tt.coupons...); given != tt.expected {
+			t.Fatalf("(%d) unexpected amount: %d", i, given)
+		}
+	}
+
+	// Without redemptions no coupon applies.
+	sub.CouponRedemptions = nil
+	if given := sub.DiscountedAmountInCents(percent); given != 2500 {
+		t.Fatalf("unexpected amount: %d", given)
+	}
+}
+
+func TestSubscriptions_NextPeriodAmount(t *testing.T) {
+	sub := recurly.Subscription{
+		UnitAmountInCents: 800,
+		Quantity:          2,
+		SubscriptionAddOns: []recurly.SubscriptionAddOn{
+			{Code: "support", UnitAmountInCents: 450, Quantity: 3},
+			{Code: "storage", UnitAmountInCents: 200, Quantity: 1},
+			{Code: "archive", UnitAmountInCents: 300},
+		},
+	}
+	if sub.HasPendingChange() {
+		t.Fatal("expected no pending change")
+	} else if sub.NextPeriodAmount() != 800*2+450*3+200 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+
+	// Pending price with unchanged quantity and add ons.
+	sub.PendingSubscription = &recurly.PendingSubscription{Price: recurly.NewInt(1000)}
+	if !sub.HasPendingChange() {
+		t.Fatal("expected pending change")
+	} else if sub.NextPeriodAmount() != 1000*2+450*3+200 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+
+	// Pending add ons replace the current ones.
+	sub.PendingSubscription.SubscriptionAddOns = []recurly.SubscriptionAddOn{
+		{Code: "support", UnitAmountInCents: 450, Quantity: 1},
+	}
+	if sub.NextPeriodAmount() != 1000*2+450 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+
+	// A downgrade to a free plan without add ons.
+	sub.PendingSubscription = &recurly.PendingSubscription{
+		Price:              recurly.NewInt(0),
+		SubscriptionAddOns: []recurly.SubscriptionAddOn{},
+	}
+	if sub.NextPeriodAmount() != 0 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+
+	// A pending quantity of zero.
+	sub.PendingSubscription = &recurly.PendingSubscription{Quantity: recurly.NewInt(0)}
+	if sub.NextPeriodAmount() != 450*3+200 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+}
+
+func TestSubscriptions_Decode_PendingSubscription_FreePlan(t *testing.T) {
+	var sub recurly.Subscription
+	if err := xml.Unmarshal([]byte(`<subscription>
+		<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+		<unit_amount_in_cents type="integer">1500</unit_amount_in_cents>
+		<quantity type="integer">2</quantity>
+		<pending_subscription type="subscription">
+			<plan href="https://your-subdomain.recurly.com/v2/plans/free">
+				<plan_code>free</plan_code>
+				<name>Free plan</name>
+			</plan>
+			<unit_amount_in_cents type="integer">0</unit_amount_in_cents>
+			<quantity type="integer">1</quantity>
+			<subscription_add_ons type="array"></subscription_add_ons>
+		</pending_subscription>
+	</subscription>`), &sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub.PendingSubscription == nil || sub.PendingSubscription.Price != recurly.NewInt(0) {
+		t.Fatalf("unexpected pending subscription: %#v", sub.PendingSubscription)
+	} else if sub.NextPeriodAmount() != 0 {
+		t.Fatalf("unexpected next period amount: %d", sub.NextPeriodAmount())
+	}
+}
+
+func TestSubscriptions_Decode_CouponRedemptions(t *testing.T) {
+	var sub recurly.Subscription
+	if err := xml.Unmarshal([]byte(`<subscription>
+		<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+		<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+		<coupon_redemptions type="array">
+			<coupon_redemption href="https://your-subdomain.recurly.com/v2/accounts/1/redemptions/374a1c75374bd81493a3f7425db0a2b8">
+				<coupon href="https://your-subdomain.recurly.com/v2/coupons/special"/>
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<single_use type="boolean">false</single_use>
+				<total_discounted_in_cents type="integer">0</total_discounted_in_cents>
+				<currency>USD</currency>
+				<state>active</state>
+				<created_at type="datetime">2016-07-11T18:23:22Z</created_at>
+			</coupon_redemption>
+		</coupon_redemptions>
+	</subscription>`), &sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(sub.CouponRedemptions, []recurly.Redemption{
+		{
+			CouponCode:  "special",
+			AccountCode: "1",
+			SingleUse:   recurly.NewBool(false),
+			Currency:    "USD",
+			State:       "active",
+			CreatedAt:   recurly.NewTimeFromString("2016-07-11T18:23:22Z"),
+		},
+	}) {
+		t.Fatalf("unexpected coupon redemptions: %#v", sub.CouponRedemptions)
+	}
+}
+
+func TestSubscriptions_Decode_ConvertedAt(t *testing.T) {
+	var sub recurly.Subscription
+	if err := xml.Unmarshal([]byte(`<subscription>
+		<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+		<state>active</state>
+		<trial_started_at type="datetime">2011-05-27T07:00:00Z</trial_started_at>
+		<trial_ends_at type="datetime">2011-06-10T07:00:00Z</trial_ends_at>
+		<converted_at type="datetime">2011-06-10T07:00:03Z</converted_at>
+	</subscription>`), &sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(sub, recurly.Subscription{
+		XMLName:        xml.Name{Local: "subscription"},
+		UUID:           "44f83d7cba354d5b84812419f923ea96",
+		State:          "active",
+		TrialStartedAt: recurly.NewTime(time.Date(2011, time.May, 27, 7, 0, 0, 0, time.UTC)),
+		TrialEndsAt:    recurly.NewTime(time.Date(2011, time.June, 10, 7, 0, 0, 0, time.UTC)),
+		ConvertedAt:    recurly.NewTime(time.Date(2011, time.June, 10, 7, 0, 3, 0, time.UTC)),
+	}) {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+}
+
+func TestSubscriptions_SubscriptionState(t *testing.T) {
+	tests := []struct {
+		state    string
+		active   bool
+		canceled bool
+		expired  bool
+		live     bool
+	}{
+		{state: recurly.SubscriptionStateActive, active: true, live: true},
+		{state: recurly.SubscriptionStateCanceled, canceled: true, live: true},
+		{state: recurly.SubscriptionStateExpired, expired: true},
+		{state: recurly.SubscriptionStateFuture, live: true},
+		{state: recurly.SubscriptionStatePaused, live: true},
+		// Filters are never the state of a subscription.
+		{state: recurly.SubscriptionStateLive},
+		{state: recurly.SubscriptionStateInTrial},
+		{state: ""},
+	}
+
+	for _, tt := range tests {
+		state := recurly.Subscription{State: tt.state}.StateEnum()
+		if state.IsActive() != tt.active {
+			t.Fatalf("unexpected IsActive for %q: %v", tt.state, state.IsActive())
+		} else if state.IsCanceled() != tt.canceled {
+			t.Fatalf("unexpected IsCanceled for %q: %v", tt.state, state.IsCanceled())
+		} else if state.IsExpired() != tt.expired {
+			t.Fatalf("unexpected IsExpired for %q: %v", tt.state, state.IsExpired())
+		} else if state.IsLive() != tt.live {
+			t.Fatalf("unexpected IsLive for %q: %v", tt.state, state.IsLive())
+		}
+	}
+}
+
+func TestSubscriptions_InTrial(t *testing.T) {
+	future := recurly.NewTime(time.Now().Add(24 * time.Hour))
+	past := recurly.NewTime(time.Now().Add(-24 * time.Hour))
+
+	tests := []struct {
+		v        recurly.Subscription
+		expected bool
+	}{
+		{v: recurly.Subscription{State: recurly.SubscriptionStateActive, TrialEndsAt: future}, expected: true},
+		{v: recurly.Subscription{State: recurly.SubscriptionStateCanceled, TrialEndsAt: future}, expected: true},
+		{v: recurly.Subscription{State: recurly.SubscriptionStateExpired, TrialEndsAt: future}},
+		{v: recurly.Subscription{State: recurly.SubscriptionStateActive, TrialEndsAt: past}},
+		{v: recurly.Subscription{State: recurly.SubscriptionStateActive}},
+	}
+
+	for i, tt := range tests {
+		if tt.v.InTrial() != tt.expected {
+			t.Fatalf("(%d) unexpected InTrial: %v", i, tt.v.InTrial())
+		}
+	}
+}
+
 func TestSubscriptions_UpdateSubscription_Encoding(t *testing.T) {
 	tests := []struct {
 		v        recurly.UpdateSubscription
@@ -267,6 +742,26 @@ func TestSubscriptions_UpdateSubscription_Encoding(t *testing.T) {
 			v:        recurly.UpdateSubscription{Timeframe: "renewal"},
 			expected: "<subscription><timeframe>renewal</timeframe></subscription>",
 		},
+		{
+			v:        recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeNow},
+			expected: "<subscription><timeframe>now</timeframe></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeBillDate},
+			expected: "<subscription><timeframe>bill_date</timeframe></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeTermEnd},
+			expected: "<subscription><timeframe>term_end</timeframe></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeNow, ChargeNow: recurly.NewBool(true)},
+			expected: "<subscription><timeframe>now</timeframe><charge_now>true</charge_now></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{RevenueScheduleType: recurly.RevenueScheduleTypeEvenly},
+			expected: "<subscription><revenue_schedule_type>evenly</revenue_schedule_type></subscription>",
+		},
 		{
 			v:        recurly.UpdateSubscription{PlanCode: "new-code"},
 			expected: "<subscription><plan_code>new-code</plan_code></subscription>",
@@ -291,6 +786,35 @@ func TestSubscriptions_UpdateSubscription_Encoding(t *testing.T) {
 			v:        recurly.UpdateSubscription{PONumber: "AB-NewPO"},
 			expected: "<subscription><po_number>AB-NewPO</po_number></subscription>",
 		},
+		{
+			v:        recurly.UpdateSubscription{GatewayCode: "eu_gateway"},
+			expected: "<subscription><gateway_code>eu_gateway</gateway_code></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{RenewalBillingCycles: recurly.NewInt(12)},
+			expected: "<subscription><renewal_billing_cycles>12</renewal_billing_cycles></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{DunningCampaignID: "ocd3oqf4nexm"},
+			expected: "<subscription><dunning_campaign_id>ocd3oqf4nexm</dunning_campaign_id></subscription>",
+		},
+		{
+			v:        recurly.UpdateSubscription{ShippingAddressID: recurly.NewInt(2438622711411416831)},
+			expected: "<subscription><shipping_address_id>2438622711411416831</shipping_address_id></subscription>",
+		},
+		{
+			v: recurly.UpdateSubscription{ShippingAddress: &recurly.ShippingAddress{
+				Nickname:  "Work",
+				FirstName: "Verena",
+				LastName:  "Example",
+				Address:   "123 Main St.",
+				City:      "San Francisco",
+				State:     "CA",
+				Zip:       "94105",
+				Country:   "US",
+			}},
+			expected: "<subscription><shipping_address><nickname>Work</nickname><first_name>Verena</first_name><last_name>Example</last_name><address1>123 Main St.</address1><city>San Francisco</city><state>CA</state><zip>94105</zip><country>US</country></shipping_address></subscription>",
+		},
 		{
 			v: recurly.UpdateSubscription{SubscriptionAddOns: &[]recurly.SubscriptionAddOn{
 				{
@@ -326,31 +850,360 @@ func TestSubscriptions_UpdateSubscription_Encoding(t *testing.T) {
 	}
 }
 
-func TestSubscriptions_List(t *testing.T) {
-	setup()
-	defer teardown()
+func TestSubscriptions_DiffUpdate(t *testing.T) {
+	original := recurly.Subscription{
+		Plan:              recurly.NestedPlan{Code: "gold", Name: "Gold plan"},
+		State:             "active",
+		UnitAmountInCents: 1000,
+		Quantity:          1,
+		PONumber:          "abc-123",
+		NetTerms:          recurly.NewInt(30),
+		CollectionMethod:  recurly.CollectionMethodAutomatic,
+		SubscriptionAddOns: []recurly.SubscriptionAddOn{
+			{Code: "extra_users", UnitAmountInCents: 1000, Quantity: 2},
+		},
+	}
 
-	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "GET" {
-			t.Fatalf("unexpected method: %s", r.Method)
+	tests := []struct {
+		modify   func(s *recurly.Subscription)
+		expected string
+	}{
+		{
+			modify:   func(s *recurly.Subscription) {},
+			expected: "<subscription></subscription>",
+		},
+		{
+			modify: func(s *recurly.Subscription) {
+				s.Quantity = 5
+				s.State = "canceled" // Not updatable
+			},
+			expected: "<subscription><quantity>5</quantity></subscription>",
+		},
+		{
+			modify: func(s *recurly.Subscription) {
+				s.SubscriptionAddOns[0].Quantity = 3
+				s.SubscriptionAddOns = append(s.SubscriptionAddOns, recurly.SubscriptionAddOn{Code: "support", UnitAmountInCents: 500, Quantity: 1})
+			},
+			expected: "<subscription><subscription_add_ons><subscription_add_on><add_on_code>extra_users</add_on_code><unit_amount_in_cents>1000</unit_amount_in_cents><quantity>3</quantity></subscription_add_on><subscription_add_on><add_on_code>support</add_on_code><unit_amount_in_cents>500</unit_amount_in_cents><quantity>1</quantity></subscription_add_on></subscription_add_ons></subscription>",
+		},
+		{
+			modify: func(s *recurly.Subscription) {
+				s.SubscriptionAddOns = nil
+			},
+			expected: "<subscription><subscription_add_ons></subscription_add_ons></subscription>",
+		},
+		{
+			modify: func(s *recurly.Subscription) {
+				s.Plan.Code = "platinum"
+				s.UnitAmountInCents = 2000
+				s.PONumber = "abc-456"
+				s.NetTerms = recurly.NewInt(45)
+				s.CollectionMethod = recurly.CollectionMethodManual
+			},
+			expected: "<subscription><plan_code>platinum</plan_code><unit_amount_in_cents>2000</unit_amount_in_cents><collection_method>manual</collection_method><net_terms>45</net_terms><po_number>abc-456</po_number></subscription>",
+		},
+	}
+
+	for i, tt := range tests {
+		modified := original
+		modified.SubscriptionAddOns = append([]recurly.SubscriptionAddOn{}, original.SubscriptionAddOns...)
+		tt.modify(&modified)
+
+		var given bytes.Buffer
+		if err := xml.NewEncoder(&given).Encode(original.DiffUpdate(modified)); err != nil {
+			t.Fatalf("(%d) unexpected encode error: %v", i, err)
+		} else if tt.expected != given.String() {
+			t.Fatalf("(%d) unexpected value: %s", i, given.String())
 		}
-		w.WriteHeader(200)
-		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
-		<subscriptions type="array">
-			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
-				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
-				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
-				<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
-				  <plan_code>gold</plan_code>
-				  <name>Gold plan</name>
-				</plan>
-				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
-				<state>active</state>
-				<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
-				<currency>EUR</currency>
-				<quantity type="integer">1</quantity>
-				<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
-				<canceled_at nil="nil"></canceled_at>
+	}
+
+	if original.SubscriptionAddOns[0].Quantity != 2 {
+		t.Fatalf("unexpected original add ons: %v", original.SubscriptionAddOns)
+	}
+}
+
+func TestSubscriptions_UpdateSubscription_AddOns(t *testing.T) {
+	sub := recurly.Subscription{
+		SubscriptionAddOns: []recurly.SubscriptionAddOn{
+			{Code: "extra_users", UnitAmountInCents: 1000, Quantity: 2},
+			{Code: "support", UnitAmountInCents: 500, Quantity: 1},
+		},
+	}
+
+	update := sub.MakeUpdate()
+	update.AddAddOn("support", 3, 450)
+	update.RemoveAddOn("extra_users")
+	update.AddAddOn("storage", 1, 200)
+
+	var given bytes.Buffer
+	if err := xml.NewEncoder(&given).Encode(update); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	} else if given.String() != "<subscription><subscription_add_ons><subscription_add_on><add_on_code>support</add_on_code><unit_amount_in_cents>450</unit_amount_in_cents><quantity>3</quantity></subscription_add_on><subscription_add_on><add_on_code>storage</add_on_code><unit_amount_in_cents>200</unit_amount_in_cents><quantity>1</quantity></subscription_add_on></subscription_add_ons></subscription>" {
+		t.Fatalf("unexpected value: %s", given.String())
+	}
+
+	// The original subscription's add ons should not be modified.
+	if !reflect.DeepEqual(sub.SubscriptionAddOns, []recurly.SubscriptionAddOn{
+		{Code: "extra_users", UnitAmountInCents: 1000, Quantity: 2},
+		{Code: "support", UnitAmountInCents: 500, Quantity: 1},
+	}) {
+		t.Fatalf("unexpected subscription add ons: %v", sub.SubscriptionAddOns)
+	}
+
+	// Helpers allocate the add ons when nil.
+	var added recurly.UpdateSubscription
+	added.AddAddOn("storage", 1, 200)
+	if !reflect.DeepEqual(added.SubscriptionAddOns, &[]recurly.SubscriptionAddOn{
+		{Code: "storage", UnitAmountInCents: 200, Quantity: 1},
+	}) {
+		t.Fatalf("unexpected add ons: %v", added.SubscriptionAddOns)
+	}
+
+	// Removing an add on from an update without add ons does nothing, so
+	// the subscription's other add ons are not removed.
+	var removed recurly.UpdateSubscription
+	removed.RemoveAddOn("storage")
+	given.Reset()
+	if err := xml.NewEncoder(&given).Encode(removed); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	} else if given.String() != "<subscription></subscription>" {
+		t.Fatalf("unexpected value: %s", given.String())
+	}
+
+	// Setting no add ons sends an empty set, removing all add ons.
+	var set recurly.UpdateSubscription
+	set.SetAddOns(nil)
+	given.Reset()
+	if err := xml.NewEncoder(&given).Encode(set); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	} else if given.String() != "<subscription><subscription_add_ons></subscription_add_ons></subscription>" {
+		t.Fatalf("unexpected value: %s", given.String())
+	}
+}
+
+func TestSubscriptions_UpdateSubscription_UsageAddOns(t *testing.T) {
+	var sub recurly.Subscription
+	if err := xml.Unmarshal([]byte(`<subscription><subscription_add_ons type="array"><subscription_add_on>
+		<add_on_type>usage</add_on_type>
+		<add_on_code>api_calls</add_on_code>
+		<unit_amount_in_cents type="integer">0</unit_amount_in_cents>
+		<quantity type="integer">1</quantity>
+		<usage_type>price</usage_type>
+		<usage_percentage type="float">2.5</usage_percentage>
+		<measured_unit_id type="integer">394681687402874853</measured_unit_id>
+		<tier_type>tiered</tier_type>
+		<tiers type="array">
+			<tier><ending_quantity type="integer">1000</ending_quantity><unit_amount_in_cents type="integer">10</unit_amount_in_cents></tier>
+		</tiers>
+	</subscription_add_on></subscription_add_ons></subscription>`), &sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if addOn := sub.SubscriptionAddOns[0]; addOn.UsageType != "price" || addOn.MeasuredUnitID != 394681687402874853 || addOn.Tiers == nil {
+		t.Fatalf("unexpected add on: %#v", addOn)
+	}
+
+	// The read only usage fields are not sent back on update.
+	update := sub.MakeUpdate()
+	(*update.SubscriptionAddOns)[0].Quantity = 2
+
+	var given bytes.Buffer
+	if err := xml.NewEncoder(&given).Encode(update); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	} else if given.String() != "<subscription><subscription_add_ons><subscription_add_on><add_on_type>usage</add_on_type><add_on_code>api_calls</add_on_code><unit_amount_in_cents>0</unit_amount_in_cents><quantity>2</quantity></subscription_add_on></subscription_add_ons></subscription>" {
+		t.Fatalf("unexpected value: %s", given.String())
+	}
+}
+
+func TestSubscriptions_UpdateSubscription_Collection(t *testing.T) {
+	manual := recurly.UpdateSubscription{NetTerms: recurly.NewInt(0)}
+	manual.SetManualCollection(30, "AB-NewPO")
+
+	automatic := recurly.UpdateSubscription{NetTerms: recurly.NewInt(30), PONumber: "AB-NewPO"}
+	automatic.SetAutomaticCollection()
+
+	tests := []struct {
+		v        recurly.UpdateSubscription
+		expected string
+	}{
+		{
+			v:        manual,
+			expected: "<subscription><collection_method>manual</collection_method><net_terms>30</net_terms><po_number>AB-NewPO</po_number></subscription>",
+		},
+		{
+			v:        automatic,
+			expected: "<subscription><collection_method>automatic</collection_method></subscription>",
+		},
+	}
+	for i, tt := range tests {
+		var given bytes.Buffer
+		if err := xml.NewEncoder(&given).Encode(tt.v); err != nil {
+			t.Fatalf("(%d) unexpected encode error: %v", i, err)
+		} else if tt.expected != given.String() {
+			t.Fatalf("(%d) unexpected value: %s", i, given.String())
+		}
+	}
+}
+
+func TestSubscriptions_UpdateSubscription_SetPreserveTrial(t *testing.T) {
+	trialEndsAt := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+	trialing := recurly.Subscription{State: "active", TrialEndsAt: recurly.NewTime(trialEndsAt)}
+	converted := recurly.Subscription{State: "active", TrialEndsAt: recurly.NewTime(trialEndsAt.AddDate(0, -1, 0))}
+
+	tests := []struct {
+		sub      recurly.Subscription
+		preserve bool
+		expected string
+	}{
+		{
+			sub:      trialing,
+			preserve: true,
+			expected: "<subscription><plan_code>platinum</plan_code><trial_ends_at>" + trialEndsAt.Format(recurly.DateTimeFormat) + "</trial_ends_at></subscription>",
+		},
+		{
+			sub:      trialing,
+			expected: "<subscription><plan_code>platinum</plan_code></subscription>",
+		},
+		// Not in a trial, so there is nothing to preserve.
+		{
+			sub:      converted,
+			preserve: true,
+			expected: "<subscription><plan_code>platinum</plan_code></subscription>",
+		},
+	}
+	for i, tt := range tests {
+		u := recurly.UpdateSubscription{PlanCode: "platinum", TrialEndsAt: recurly.NewTime(trialEndsAt)}
+		u.SetPreserveTrial(tt.sub, tt.preserve)
+
+		var given bytes.Buffer
+		if err := xml.NewEncoder(&given).Encode(u); err != nil {
+			t.Fatalf("(%d) unexpected encode error: %v", i, err)
+		} else if tt.expected != given.String() {
+			t.Fatalf("(%d) unexpected value: %s", i, given.String())
+		}
+	}
+}
+
+func TestSubscriptions_UpdateSubscription_SetShippingAddressID(t *testing.T) {
+	u := recurly.UpdateSubscription{ShippingAddress: &recurly.ShippingAddress{Nickname: "Old"}}
+	u.SetShippingAddressID(2438622711411416831)
+
+	var given bytes.Buffer
+	if err := xml.NewEncoder(&given).Encode(u); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	} else if given.String() != "<subscription><shipping_address_id>2438622711411416831</shipping_address_id></subscription>" {
+		t.Fatalf("unexpected value: %s", given.String())
+	}
+}
+
+func TestSubscriptions_UpdateSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		v     recurly.UpdateSubscription
+		valid bool
+	}{
+		{v: recurly.UpdateSubscription{}, valid: true},
+		{v: recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeNow}, valid: true},
+		{v: recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeRenewal}, valid: true},
+		{v: recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeNow, ChargeNow: recurly.NewBool(true)}, valid: true},
+		{v: recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeRenewal, ChargeNow: recurly.NewBool(false)}, valid: true},
+		{v: recurly.UpdateSubscription{Timeframe: "immediately"}, valid: false},
+		{v: recurly.UpdateSubscription{Timeframe: "Now"}, valid: false},
+		{v: recurly.UpdateSubscription{Timeframe: recurly.SubscriptionTimeframeRenewal, ChargeNow: recurly.NewBool(true)}, valid: false},
+		{v: recurly.UpdateSubscription{ChargeNow: recurly.NewBool(true)}, valid: false},
+		{v: recurly.UpdateSubscription{RevenueScheduleType: "sometimes"}, valid: false},
+		{v: recurly.UpdateSubscription{CollectionMethod: recurly.CollectionMethodManual}, valid: true},
+		{v: recurly.UpdateSubscription{CollectionMethod: "auto"}, valid: false},
+	}
+
+	for i, tt := range tests {
+		if err := tt.v.Validate(); tt.valid && err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if !tt.valid && err == nil {
+			t.Fatalf("(%d): expected error", i)
+		}
+	}
+}
+
+func TestSubscriptions_NewSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		v     recurly.NewSubscription
+		valid bool
+	}{
+		{v: recurly.NewSubscription{}, valid: true},
+		{v: recurly.NewSubscription{CollectionMethod: recurly.CollectionMethodAutomatic}, valid: true},
+		{v: recurly.NewSubscription{CollectionMethod: recurly.CollectionMethodManual}, valid: true},
+		{v: recurly.NewSubscription{CollectionMethod: "auto"}, valid: false},
+		{v: recurly.NewSubscription{CollectionMethod: "Manual"}, valid: false},
+		{v: recurly.NewSubscription{ShippingMethodCode: "fedex_ground", ShippingAmountInCents: 599}, valid: true},
+		{v: recurly.NewSubscription{ShippingAmountInCents: 599}, valid: false},
+		{v: recurly.NewSubscription{ShippingMethodCode: "fedex_ground", ShippingAmountInCents: -1}, valid: false},
+		{v: recurly.NewSubscription{ShippingAddressID: recurly.NewInt(1), ShippingAddress: &recurly.ShippingAddress{Zip: "94105"}}, valid: false},
+	}
+
+	for i, tt := range tests {
+		if err := tt.v.Validate(); tt.valid && err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if !tt.valid && err == nil {
+			t.Fatalf("(%d): expected error", i)
+		}
+	}
+}
+
+func TestSubscriptions_Create_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected API call")
+	})
+
+	if r, sub, err := client.Subscriptions.Create(recurly.NewSubscription{CollectionMethod: "auto"}); err == nil {
+		t.Fatal("expected error")
+	} else if err.Error() != `recurly: invalid collection method "auto", must be "automatic" or "manual"` {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r != nil || sub != nil {
+		t.Fatalf("unexpected result: %v %v", r, sub)
+	}
+}
+
+func TestSubscriptions_Update_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected API call")
+	})
+
+	if r, sub, err := client.Subscriptions.Update("44f83d7cba354d5b84812419f923ea96", recurly.UpdateSubscription{Timeframe: "later"}); err == nil {
+		t.Fatal("expected error")
+	} else if r != nil || sub != nil {
+		t.Fatalf("unexpected result: %v %v", r, sub)
+	}
+}
+
+func TestSubscriptions_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscriptions type="array">
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+				<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				  <plan_code>gold</plan_code>
+				  <name>Gold plan</name>
+				</plan>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>active</state>
+				<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
+				<currency>EUR</currency>
+				<quantity type="integer">1</quantity>
+				<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
+				<canceled_at nil="nil"></canceled_at>
 				<expires_at nil="nil"></expires_at>
 				<current_period_started_at type="datetime">2011-06-27T07:00:00Z</current_period_started_at>
 				<current_period_ends_at type="datetime">2010-07-27T07:00:00Z</current_period_ends_at>
@@ -427,189 +1280,368 @@ func TestSubscriptions_List(t *testing.T) {
 	}
 }
 
-func TestSubscriptions_ListAccount(t *testing.T) {
+func TestSubscriptions_ListWithInvoices(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/accounts/1/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
 			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("state") != "active" {
+			t.Fatalf("unexpected params: %s", r.URL.RawQuery)
 		}
 		w.WriteHeader(200)
 		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
 		<subscriptions type="array">
 			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
-				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
 				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
-				<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
-				  <plan_code>gold</plan_code>
-				  <name>Gold plan</name>
-				</plan>
 				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
-				<state>active</state>
-				<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
-				<currency>EUR</currency>
-				<quantity type="integer">1</quantity>
-				<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
-				<canceled_at nil="nil"></canceled_at>
-				<expires_at nil="nil"></expires_at>
-				<current_period_started_at type="datetime">2011-06-27T07:00:00Z</current_period_started_at>
-				<current_period_ends_at type="datetime">2010-07-27T07:00:00Z</current_period_ends_at>
-				<trial_started_at nil="nil"></trial_started_at>
-				<trial_ends_at nil="nil"></trial_ends_at>
-				<tax_in_cents type="integer">72</tax_in_cents>
-				<tax_type>usst</tax_type>
-				<tax_region>CA</tax_region>
-				<tax_rate type="float">0.0875</tax_rate>
-				<po_number nil="nil"></po_number>
-				<net_terms type="integer">0</net_terms>
-				<subscription_add_ons type="array">
-				</subscription_add_ons>
-				<a name="cancel" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel" method="put"/>
-				<a name="terminate" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate" method="put"/>
-				<a name="postpone" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone" method="put"/>
+			</subscription>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea97">
+				<uuid>44f83d7cba354d5b84812419f923ea97</uuid>
+			</subscription>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea98">
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1109"/>
+				<uuid>44f83d7cba354d5b84812419f923ea98</uuid>
+			</subscription>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea99">
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1110"/>
+				<uuid>44f83d7cba354d5b84812419f923ea99</uuid>
 			</subscription>
 		</subscriptions>`)
 	})
 
-	r, subscriptions, err := client.Subscriptions.ListAccount("1", recurly.Params{"per_page": 1})
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	for _, n := range []int{1108, 1109, 1110} {
+		n := n
+		mux.HandleFunc(fmt.Sprintf("/v2/invoices/%d", n), func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><invoice><invoice_number type="integer">%d</invoice_number><state>paid</state></invoice>`, n)
+		})
+	}
+
+	r, subs, err := client.Subscriptions.ListWithInvoices(context.Background(), recurly.ListWithInvoicesOptions{
+		Params:      recurly.Params{"state": "active"},
+		Concurrency: 2,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected list subcriptions to return OK")
-	} else if pp := r.Request.URL.Query().Get("per_page"); pp != "1" {
-		t.Fatalf("unexpected per_page: %s", pp)
+		t.Fatal("expected list subscriptions with invoices to return OK")
+	} else if len(subs) != 4 {
+		t.Fatalf("unexpected length: %d", len(subs))
+	} else if maxInFlight > 2 {
+		t.Fatalf("unexpected concurrent invoice lookups: %d", maxInFlight)
 	}
 
-	activated, _ := time.Parse(recurly.DateTimeFormat, "2011-05-27T07:00:00Z")
-	cpStartedAt, _ := time.Parse(recurly.DateTimeFormat, "2011-06-27T07:00:00Z")
-	cpEndsAt, _ := time.Parse(recurly.DateTimeFormat, "2010-07-27T07:00:00Z")
-
-	if !reflect.DeepEqual(subscriptions, []recurly.Subscription{
-		{
-			XMLName: xml.Name{Local: "subscription"},
-			Plan: recurly.NestedPlan{
-				Code: "gold",
-				Name: "Gold plan",
-			},
-			AccountCode:            "1",
-			InvoiceNumber:          1108,
-			UUID:                   "44f83d7cba354d5b84812419f923ea96",
-			State:                  "active",
-			UnitAmountInCents:      800,
-			Currency:               "EUR",
-			Quantity:               1,
-			ActivatedAt:            recurly.NewTime(activated),
-			CurrentPeriodStartedAt: recurly.NewTime(cpStartedAt),
-			CurrentPeriodEndsAt:    recurly.NewTime(cpEndsAt),
-			TaxInCents:             72,
-			TaxType:                "usst",
-			TaxRegion:              "CA",
-			TaxRate:                0.0875,
-			NetTerms:               recurly.NewInt(0),
-		},
-	}) {
-		t.Fatalf("unexpected subscriptions: %v", subscriptions)
+	for i, expected := range []int{1108, 0, 1109, 1110} {
+		if subs[i].Subscription.UUID == "" {
+			t.Fatalf("unexpected subscription: %#v", subs[i].Subscription)
+		} else if expected == 0 && subs[i].Invoice != nil {
+			t.Fatalf("unexpected invoice: %#v", subs[i].Invoice)
+		} else if expected != 0 && (subs[i].Invoice == nil || subs[i].Invoice.InvoiceNumber != expected || subs[i].Invoice.State != recurly.InvoiceStatePaid) {
+			t.Fatalf("unexpected invoice for %d: %#v", expected, subs[i].Invoice)
+		}
 	}
 }
 
-func TestSubscriptions_Get(t *testing.T) {
+func TestSubscriptions_ListWithInvoices_ErrorResponse(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "GET" {
-			t.Fatalf("unexpected method: %s", r.Method)
-		}
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
+		if r.URL.Query().Get("state") == "expired" {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription><invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/></subscription>
+			</subscriptions>`)
+			return
+		}
 		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
-		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
-			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
-			<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
-			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
-			  <plan_code>gold</plan_code>
-			  <name>Gold plan</name>
-			</plan>
-			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
-			<state>active</state>
-			<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
-			<currency>EUR</currency>
-			<quantity type="integer">1</quantity>
-			<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
-			<canceled_at nil="nil"></canceled_at>
-			<expires_at nil="nil"></expires_at>
-			<current_period_started_at type="datetime">2011-06-27T07:00:00Z</current_period_started_at>
-			<current_period_ends_at type="datetime">2011-07-27T07:00:00Z</current_period_ends_at>
-			<trial_started_at nil="nil"></trial_started_at>
-			<trial_ends_at nil="nil"></trial_ends_at>
-			<tax_in_cents type="integer">72</tax_in_cents>
-			<tax_type>usst</tax_type>
-			<tax_region>CA</tax_region>
-			<tax_rate type="float">0.0875</tax_rate>
-			<po_number nil="nil"></po_number>
-			<net_terms type="integer">0</net_terms>
-			<subscription_add_ons type="array">
-			</subscription_add_ons>
-			<a name="cancel" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel" method="put"/>
-			<a name="terminate" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate" method="put"/>
-			<a name="postpone" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone" method="put"/>
-		</subscription>`)
+		<subscriptions type="array">
+			<subscription><invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/></subscription>
+			<subscription><invoice href="https://your-subdomain.recurly.com/v2/invoices/1109"/></subscription>
+		</subscriptions>`)
+	})
+	mux.HandleFunc("/v2/invoices/1108", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+	mux.HandleFunc("/v2/invoices/1109", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
 	})
 
-	r, subscription, err := client.Subscriptions.Get("44f83d7cb-a354d5b848-12419f923ea96") // UUID has dashes
-	if err != nil {
+	// An invoice that isn't found is left nil.
+	if _, subs, err := client.Subscriptions.ListWithInvoices(context.Background(), recurly.ListWithInvoicesOptions{
+		Params: recurly.Params{"state": "expired"},
+	}); err != nil {
 		t.Fatalf("unexpected error: %v", err)
-	} else if r.IsError() {
-		t.Fatal("expected list subcriptions to return OK")
+	} else if len(subs) != 1 || subs[0].Invoice != nil {
+		t.Fatalf("unexpected subscriptions: %#v", subs)
 	}
 
-	if !reflect.DeepEqual(subscription, &recurly.Subscription{
-		XMLName: xml.Name{Local: "subscription"},
-		Plan: recurly.NestedPlan{
-			Code: "gold",
-			Name: "Gold plan",
-		},
-		AccountCode:            "1",
-		InvoiceNumber:          1108,
-		UUID:                   "44f83d7cba354d5b84812419f923ea96", // UUID has been sanitized
-		State:                  "active",
-		UnitAmountInCents:      800,
-		Currency:               "EUR",
-		Quantity:               1,
-		ActivatedAt:            recurly.NewTime(time.Date(2011, time.May, 27, 7, 0, 0, 0, time.UTC)),
-		CurrentPeriodStartedAt: recurly.NewTime(time.Date(2011, time.June, 27, 7, 0, 0, 0, time.UTC)),
-		CurrentPeriodEndsAt:    recurly.NewTime(time.Date(2011, time.July, 27, 7, 0, 0, 0, time.UTC)),
-		TaxInCents:             72,
-		TaxType:                "usst",
-		TaxRegion:              "CA",
-		TaxRate:                0.0875,
-		NetTerms:               recurly.NewInt(0),
+	// Other error responses fail the lookup.
+	if _, subs, err := client.Subscriptions.ListWithInvoices(context.Background(), recurly.ListWithInvoicesOptions{}); err == nil || !strings.Contains(err.Error(), "invoice 1109") {
+		t.Fatalf("unexpected error: %v", err)
+	} else if subs != nil {
+		t.Fatalf("unexpected subscriptions: %#v", subs)
+	}
+}
+
+func TestSubscriptions_ListWithInvoices_Canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscriptions type="array">
+			<subscription><invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/></subscription>
+			<subscription><invoice href="https://your-subdomain.recurly.com/v2/invoices/1109"/></subscription>
+		</subscriptions>`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var invoked int
+	mux.HandleFunc("/v2/invoices/", func(w http.ResponseWriter, r *http.Request) {
+		invoked++
+		cancel()
+		<-r.Context().Done()
+	})
+
+	if _, subs, err := client.Subscriptions.ListWithInvoices(ctx, recurly.ListWithInvoicesOptions{Concurrency: 1}); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	} else if subs != nil {
+		t.Fatalf("unexpected subscriptions: %#v", subs)
+	} else if invoked != 1 {
+		t.Fatalf("unexpected invoice lookups: %d", invoked)
+	}
+}
+
+func TestSubscriptions_Stream(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if state := r.URL.Query().Get("state"); state != "active" {
+			t.Fatalf("unexpected state: %s", state)
+		}
+
+		switch cursor := r.URL.Query().Get("cursor"); cursor {
+		case "":
+			w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/subscriptions?cursor=1304958672&state=active>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>
+				<subscription><uuid>55f83d7cba354d5b84812419f923ea96</uuid></subscription>
+			</subscriptions>`)
+		case "1304958672":
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription><uuid>66f83d7cba354d5b84812419f923ea96</uuid></subscription>
+			</subscriptions>`)
+		default:
+			t.Fatalf("unexpected cursor: %s", cursor)
+		}
+	})
+
+	subs, errs := client.Subscriptions.Stream(context.Background(), recurly.Params{"state": "active"})
+	var uuids []string
+	for sub := range subs {
+		uuids = append(uuids, sub.UUID)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(uuids, []string{
+		"44f83d7cba354d5b84812419f923ea96",
+		"55f83d7cba354d5b84812419f923ea96",
+		"66f83d7cba354d5b84812419f923ea96",
 	}) {
-		t.Fatalf("unexpected subscription: %v", subscription)
+		t.Fatalf("unexpected subscriptions: %v", uuids)
 	}
 }
 
-func TestSubscriptions_Get_ErrNotFound(t *testing.T) {
+func TestSubscriptions_Stream_Canceled(t *testing.T) {
 	setup()
 	defer teardown()
 
-	var invoked bool
-	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
-		invoked = true
-		w.WriteHeader(http.StatusNotFound)
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/subscriptions?cursor=1304958672>; rel="next"`)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscriptions type="array">
+			<subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>
+			<subscription><uuid>55f83d7cba354d5b84812419f923ea96</uuid></subscription>
+		</subscriptions>`)
 	})
 
-	_, subscription, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
-	if !invoked {
-		t.Fatal("handler not invoked")
-	} else if err != nil {
+	ctx, cancel := context.WithCancel(context.Background())
+	subs, errs := client.Subscriptions.Stream(ctx, nil)
+	if sub := <-subs; sub.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %s", sub.UUID)
+	}
+	cancel()
+
+	for range subs {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
 		t.Fatalf("unexpected error: %v", err)
-	} else if subscription != nil {
-		t.Fatalf("expected subscription to be nil: %#v", subscription)
 	}
 }
 
-func TestSubscriptions_Get_PendingSubscription(t *testing.T) {
+func TestSubscriptions_UpcomingRenewals(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Now()
+	endsAt := func(d time.Duration) string {
+		return fmt.Sprintf(`<current_period_ends_at type="datetime">%s</current_period_ends_at>`, now.Add(d).UTC().Format(recurly.DateTimeFormat))
+	}
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if state := r.URL.Query().Get("state"); state != "live" {
+			t.Fatalf("unexpected state: %s", state)
+		}
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/subscriptions?cursor=1304958672&state=live>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription><uuid>soon</uuid><state>active</state>%s</subscription>
+				<subscription><uuid>later</uuid><state>active</state>%s</subscription>
+				<subscription><uuid>past</uuid><state>active</state>%s</subscription>
+				<subscription><uuid>none</uuid><state>future</state><current_period_ends_at nil="nil"></current_period_ends_at></subscription>
+			</subscriptions>`, endsAt(time.Hour), endsAt(48*time.Hour), endsAt(-time.Hour))
+		default:
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription><uuid>canceled</uuid><state>canceled</state>%s</subscription>
+				<subscription><uuid>tomorrow</uuid><state>active</state>%s</subscription>
+			</subscriptions>`, endsAt(2*time.Hour), endsAt(23*time.Hour))
+		}
+	})
+
+	subs, err := client.Subscriptions.UpcomingRenewals(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var uuids []string
+	for _, sub := range subs {
+		uuids = append(uuids, sub.UUID)
+	}
+	if !reflect.DeepEqual(uuids, []string{"soon", "tomorrow"}) {
+		t.Fatalf("unexpected subscriptions: %v", uuids)
+	}
+}
+
+func TestSubscriptions_ListAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscriptions type="array">
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+				<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				  <plan_code>gold</plan_code>
+				  <name>Gold plan</name>
+				</plan>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>active</state>
+				<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
+				<currency>EUR</currency>
+				<quantity type="integer">1</quantity>
+				<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
+				<canceled_at nil="nil"></canceled_at>
+				<expires_at nil="nil"></expires_at>
+				<current_period_started_at type="datetime">2011-06-27T07:00:00Z</current_period_started_at>
+				<current_period_ends_at type="datetime">2010-07-27T07:00:00Z</current_period_ends_at>
+				<trial_started_at nil="nil"></trial_started_at>
+				<trial_ends_at nil="nil"></trial_ends_at>
+				<tax_in_cents type="integer">72</tax_in_cents>
+				<tax_type>usst</tax_type>
+				<tax_region>CA</tax_region>
+				<tax_rate type="float">0.0875</tax_rate>
+				<po_number nil="nil"></po_number>
+				<net_terms type="integer">0</net_terms>
+				<subscription_add_ons type="array">
+				</subscription_add_ons>
+				<a name="cancel" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel" method="put"/>
+				<a name="terminate" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate" method="put"/>
+				<a name="postpone" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone" method="put"/>
+			</subscription>
+		</subscriptions>`)
+	})
+
+	r, subscriptions, err := client.Subscriptions.ListAccount("1", recurly.Params{"per_page": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected list subcriptions to return OK")
+	} else if pp := r.Request.URL.Query().Get("per_page"); pp != "1" {
+		t.Fatalf("unexpected per_page: %s", pp)
+	}
+
+	activated, _ := time.Parse(recurly.DateTimeFormat, "2011-05-27T07:00:00Z")
+	cpStartedAt, _ := time.Parse(recurly.DateTimeFormat, "2011-06-27T07:00:00Z")
+	cpEndsAt, _ := time.Parse(recurly.DateTimeFormat, "2010-07-27T07:00:00Z")
+
+	if !reflect.DeepEqual(subscriptions, []recurly.Subscription{
+		{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "gold",
+				Name: "Gold plan",
+			},
+			AccountCode:            "1",
+			InvoiceNumber:          1108,
+			UUID:                   "44f83d7cba354d5b84812419f923ea96",
+			State:                  "active",
+			UnitAmountInCents:      800,
+			Currency:               "EUR",
+			Quantity:               1,
+			ActivatedAt:            recurly.NewTime(activated),
+			CurrentPeriodStartedAt: recurly.NewTime(cpStartedAt),
+			CurrentPeriodEndsAt:    recurly.NewTime(cpEndsAt),
+			TaxInCents:             72,
+			TaxType:                "usst",
+			TaxRegion:              "CA",
+			TaxRate:                0.0875,
+			NetTerms:               recurly.NewInt(0),
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
+		},
+	}) {
+		t.Fatalf("unexpected subscriptions: %v", subscriptions)
+	}
+}
+
+func TestSubscriptions_Get(t *testing.T) {
 	setup()
 	defer teardown()
 
@@ -644,37 +1676,16 @@ func TestSubscriptions_Get_PendingSubscription(t *testing.T) {
 			<tax_rate type="float">0.0875</tax_rate>
 			<po_number nil="nil"></po_number>
 			<net_terms type="integer">0</net_terms>
+			<collection_method>automatic</collection_method>
 			<subscription_add_ons type="array">
 			</subscription_add_ons>
-			<pending_subscription type="subscription">
-				<plan href="https://blacklighttest.recurly.com/v2/plans/integrationtestcode">
-					<plan_code>gold</plan_code>
-					<name>Gold plan</name>
-				</plan>
-				<unit_amount_in_cents type="integer">50000</unit_amount_in_cents>
-				<quantity type="integer">1</quantity>
-				<subscription_add_ons type="array">
-					<subscription_add_on>
-						<add_on_type>fixed</add_on_type>
-						<add_on_code>add-on-one</add_on_code>
-						<unit_amount_in_cents type="integer">1100</unit_amount_in_cents>
-						<quantity type="integer">1</quantity>
-					</subscription_add_on>
-					<subscription_add_on>
-						<add_on_type>fixed</add_on_type>
-						<add_on_code>add-on-two</add_on_code>
-						<unit_amount_in_cents type="integer">1300</unit_amount_in_cents>
-						<quantity type="integer">1</quantity>
-					</subscription_add_on>
-				</subscription_add_ons>
-			</pending_subscription>
 			<a name="cancel" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel" method="put"/>
 			<a name="terminate" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate" method="put"/>
 			<a name="postpone" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone" method="put"/>
 		</subscription>`)
 	})
 
-	r, subscription, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	r, subscription, err := client.Subscriptions.Get("44f83d7cb-a354d5b848-12419f923ea96") // UUID has dashes
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
@@ -689,7 +1700,7 @@ func TestSubscriptions_Get_PendingSubscription(t *testing.T) {
 		},
 		AccountCode:            "1",
 		InvoiceNumber:          1108,
-		UUID:                   "44f83d7cba354d5b84812419f923ea96",
+		UUID:                   "44f83d7cba354d5b84812419f923ea96", // UUID has been sanitized
 		State:                  "active",
 		UnitAmountInCents:      800,
 		Currency:               "EUR",
@@ -702,40 +1713,1052 @@ func TestSubscriptions_Get_PendingSubscription(t *testing.T) {
 		TaxRegion:              "CA",
 		TaxRate:                0.0875,
 		NetTerms:               recurly.NewInt(0),
-		PendingSubscription: &recurly.PendingSubscription{
-			XMLName: xml.Name{Local: "pending_subscription"},
-			Plan: recurly.NestedPlan{
-				Code: "gold",
-				Name: "Gold plan",
-			},
-			Quantity: 1,
-			SubscriptionAddOns: []recurly.SubscriptionAddOn{
-				{
-					XMLName:           xml.Name{Local: "subscription_add_on"},
-					Type:              "fixed",
-					Code:              "add-on-one",
-					UnitAmountInCents: 1100,
-					Quantity:          1,
-				},
-				{
-					XMLName:           xml.Name{Local: "subscription_add_on"},
-					Type:              "fixed",
-					Code:              "add-on-two",
-					UnitAmountInCents: 1300,
-					Quantity:          1,
-				},
-			},
-		},
+		CollectionMethod:       recurly.CollectionMethodAutomatic,
+		SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
 	}) {
-		t.Fatalf("unexpected subscription: %#v", subscription)
+		t.Fatalf("unexpected subscription: %v", subscription)
 	}
 }
 
-func TestSubscriptions_Create(t *testing.T) {
+func TestSubscriptions_GetWithAddOnNames(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				<plan_code>gold</plan_code>
+				<name>Gold plan</name>
+			</plan>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<state>active</state>
+			<subscription_add_ons type="array">
+				<subscription_add_on>
+					<add_on_code>ipaddresses</add_on_code>
+					<quantity type="integer">2</quantity>
+				</subscription_add_on>
+				<subscription_add_on>
+					<add_on_code>support</add_on_code>
+					<quantity type="integer">1</quantity>
+					<add_on_source>account_add_on</add_on_source>
+				</subscription_add_on>
+			</subscription_add_ons>
+			<pending_subscription type="subscription">
+				<quantity type="integer">1</quantity>
+				<subscription_add_ons type="array">
+					<subscription_add_on>
+						<add_on_code>backups</add_on_code>
+						<quantity type="integer">1</quantity>
+					</subscription_add_on>
+				</subscription_add_ons>
+			</pending_subscription>
+		</subscription>`)
+	})
+
+	var planInvoked, addOnsInvoked int
+	mux.HandleFunc("/v2/plans/gold", func(w http.ResponseWriter, r *http.Request) {
+		planInvoked++
+		if r.Header.Get("X-Test") != "1" {
+			t.Fatalf("unexpected header: %q", r.Header.Get("X-Test"))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><plan><plan_code>gold</plan_code></plan>`)
+	})
+	mux.HandleFunc("/v2/plans/gold/add_ons", func(w http.ResponseWriter, r *http.Request) {
+		addOnsInvoked++
+		if r.Header.Get("X-Test") != "1" {
+			t.Fatalf("unexpected header: %q", r.Header.Get("X-Test"))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<add_ons type="array">
+			<add_on><add_on_code>ipaddresses</add_on_code><name>IP Addresses</name></add_on>
+			<add_on><add_on_code>backups</add_on_code><name>Nightly Backups</name></add_on>
+			<add_on><add_on_code>support</add_on_code><name>Plan Support</name></add_on>
+		</add_ons>`)
+	})
+
+	// Options are applied to the plan lookups too.
+	header := func(req *http.Request) { req.Header.Set("X-Test", "1") }
+	_, sub, err := client.Subscriptions.GetWithAddOnNames("44f83d7cba354d5b84812419f923ea96", header)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if planInvoked != 1 || addOnsInvoked != 1 {
+		t.Fatalf("unexpected plan lookups: %d, %d", planInvoked, addOnsInvoked)
+	} else if name := sub.SubscriptionAddOns[0].Name; name != "IP Addresses" {
+		t.Fatalf("unexpected name: %q", name)
+	} else if name := sub.SubscriptionAddOns[1].Name; name != "" {
+		t.Fatalf("unexpected account add on name: %q", name)
+	} else if name := sub.PendingSubscription.SubscriptionAddOns[0].Name; name != "Nightly Backups" {
+		t.Fatalf("unexpected pending name: %q", name)
+	}
+
+	// Get doesn't look up plans.
+	if _, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if planInvoked != 1 {
+		t.Fatalf("unexpected plan lookups: %d", planInvoked)
+	} else if name := sub.SubscriptionAddOns[0].Name; name != "" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+}
+
+func TestSubscriptions_GetWithAddOnNames_PlanError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				<plan_code>gold</plan_code>
+			</plan>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<subscription_add_ons type="array">
+				<subscription_add_on>
+					<add_on_code>ipaddresses</add_on_code>
+					<quantity type="integer">2</quantity>
+				</subscription_add_on>
+			</subscription_add_ons>
+		</subscription>`)
+	})
+	mux.HandleFunc("/v2/plans/gold", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	r, sub, err := client.Subscriptions.GetWithAddOnNames("44f83d7cba354d5b84812419f923ea96")
+	if err == nil || !strings.Contains(err.Error(), `"gold"`) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.StatusCode != 200 {
+		t.Fatalf("unexpected status code: %d", r.StatusCode)
+	} else if sub == nil || sub.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+}
+
+func TestSubscriptions_Get_ErrNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, subscription, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	if !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if subscription != nil {
+		t.Fatalf("expected subscription to be nil: %#v", subscription)
+	}
+}
+
+func TestSubscriptions_Get_StartedWithGift(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<state>active</state>
+			<started_with_gift type="boolean">true</started_with_gift>
+			<remaining_gift_credit_in_cents type="integer">1500</remaining_gift_credit_in_cents>
+		</subscription>`)
+	})
+
+	_, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !sub.StartedWithGift {
+		t.Fatal("expected subscription to have started with a gift")
+	} else if sub.GiftCreditInCents != recurly.NewInt(1500) {
+		t.Fatalf("unexpected gift credit: %#v", sub.GiftCreditInCents)
+	}
+}
+
+func TestSubscriptions_Get_RenewalBillingCycles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<renewal_billing_cycles type="integer">6</renewal_billing_cycles>
+		</subscription>`)
+	})
+
+	_, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub.RenewalBillingCycles != recurly.NewInt(6) {
+		t.Fatalf("unexpected renewal billing cycles: %#v", sub.RenewalBillingCycles)
+	}
+}
+
+func TestSubscriptions_Get_PendingSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+			  <plan_code>gold</plan_code>
+			  <name>Gold plan</name>
+			</plan>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<state>active</state>
+			<unit_amount_in_cents type="integer">800</unit_amount_in_cents>
+			<currency>EUR</currency>
+			<quantity type="integer">1</quantity>
+			<activated_at type="datetime">2011-05-27T07:00:00Z</activated_at>
+			<canceled_at nil="nil"></canceled_at>
+			<expires_at nil="nil"></expires_at>
+			<current_period_started_at type="datetime">2011-06-27T07:00:00Z</current_period_started_at>
+			<current_period_ends_at type="datetime">2011-07-27T07:00:00Z</current_period_ends_at>
+			<trial_started_at nil="nil"></trial_started_at>
+			<trial_ends_at nil="nil"></trial_ends_at>
+			<tax_in_cents type="integer">72</tax_in_cents>
+			<tax_type>usst</tax_type>
+			<tax_region>CA</tax_region>
+			<tax_rate type="float">0.0875</tax_rate>
+			<po_number nil="nil"></po_number>
+			<net_terms type="integer">0</net_terms>
+			<subscription_add_ons type="array">
+			</subscription_add_ons>
+			<pending_subscription type="subscription">
+				<plan href="https://blacklighttest.recurly.com/v2/plans/integrationtestcode">
+					<plan_code>gold</plan_code>
+					<name>Gold plan</name>
+				</plan>
+				<unit_amount_in_cents type="integer">50000</unit_amount_in_cents>
+				<quantity type="integer">1</quantity>
+				<subscription_add_ons type="array">
+					<subscription_add_on>
+						<add_on_type>fixed</add_on_type>
+						<add_on_code>add-on-one</add_on_code>
+						<unit_amount_in_cents type="integer">1100</unit_amount_in_cents>
+						<quantity type="integer">1</quantity>
+					</subscription_add_on>
+					<subscription_add_on>
+						<add_on_type>fixed</add_on_type>
+						<add_on_code>add-on-two</add_on_code>
+						<unit_amount_in_cents type="integer">1300</unit_amount_in_cents>
+						<quantity type="integer">1</quantity>
+					</subscription_add_on>
+				</subscription_add_ons>
+			</pending_subscription>
+			<a name="cancel" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel" method="put"/>
+			<a name="terminate" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate" method="put"/>
+			<a name="postpone" href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone" method="put"/>
+		</subscription>`)
+	})
+
+	r, subscription, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected list subcriptions to return OK")
+	}
+
+	if !reflect.DeepEqual(subscription, &recurly.Subscription{
+		XMLName: xml.Name{Local: "subscription"},
+		Plan: recurly.NestedPlan{
+			Code: "gold",
+			Name: "Gold plan",
+		},
+		AccountCode:            "1",
+		InvoiceNumber:          1108,
+		UUID:                   "44f83d7cba354d5b84812419f923ea96",
+		State:                  "active",
+		UnitAmountInCents:      800,
+		Currency:               "EUR",
+		Quantity:               1,
+		ActivatedAt:            recurly.NewTime(time.Date(2011, time.May, 27, 7, 0, 0, 0, time.UTC)),
+		CurrentPeriodStartedAt: recurly.NewTime(time.Date(2011, time.June, 27, 7, 0, 0, 0, time.UTC)),
+		CurrentPeriodEndsAt:    recurly.NewTime(time.Date(2011, time.July, 27, 7, 0, 0, 0, time.UTC)),
+		TaxInCents:             72,
+		TaxType:                "usst",
+		TaxRegion:              "CA",
+		TaxRate:                0.0875,
+		NetTerms:               recurly.NewInt(0),
+		SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
+		PendingSubscription: &recurly.PendingSubscription{
+			XMLName: xml.Name{Local: "pending_subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "gold",
+				Name: "Gold plan",
+			},
+			Price:    recurly.NewInt(50000),
+			Quantity: recurly.NewInt(1),
+			SubscriptionAddOns: []recurly.SubscriptionAddOn{
+				{
+					XMLName:           xml.Name{Local: "subscription_add_on"},
+					Type:              "fixed",
+					Code:              "add-on-one",
+					UnitAmountInCents: 1100,
+					Quantity:          1,
+				},
+				{
+					XMLName:           xml.Name{Local: "subscription_add_on"},
+					Type:              "fixed",
+					Code:              "add-on-two",
+					UnitAmountInCents: 1300,
+					Quantity:          1,
+				},
+			},
+		},
+	}) {
+		t.Fatalf("unexpected subscription: %#v", subscription)
+	}
+}
+
+func TestSubscriptions_RequestOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var invoked bool
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		if r.Header.Get("Idempotency-Key") != "abc123" {
+			t.Fatalf("unexpected Idempotency-Key header: %s", r.Header.Get("Idempotency-Key"))
+		} else if r.Header.Get("X-Api-Version") != "2.10" {
+			t.Fatalf("unexpected X-Api-Version header: %s", r.Header.Get("X-Api-Version"))
+		} else if r.URL.Query().Get("foo") != "bar" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	if _, sub, err := client.Subscriptions.Get("44f83d7cba354d5b84812419f923ea96",
+		recurly.WithHeader("Idempotency-Key", "abc123"),
+		recurly.WithHeader("X-Api-Version", "2.10"),
+		recurly.WithQuery("foo", "bar"),
+	); !invoked {
+		t.Fatal("handler not invoked")
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+}
+
+func TestSubscriptions_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	r, _, err := client.Subscriptions.Create(recurly.NewSubscription{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected create subscription to return OK")
+	}
+}
+
+func TestSubscriptions_CreateForAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		expected := "<subscription><plan_code>gold</plan_code><account><account_code>1</account_code></account><currency>USD</currency></subscription>"
+		if expected != given.String() {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+	})
+
+	r, sub, err := client.Subscriptions.CreateForAccount("1", recurly.NewSubscription{
+		PlanCode: "gold",
+		Currency: "USD",
+		Account: recurly.Account{
+			Code:        "other",
+			Email:       "verena@example.com",
+			BillingInfo: &recurly.Billing{Token: "tok"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected create subscription for account to return OK")
+	} else if sub.Subscription == nil || sub.Subscription.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", sub.Subscription)
+	}
+}
+
+func TestSubscriptions_CreateWithTransaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if !strings.Contains(given.String(), "<number>4111111111111111</number>") || !strings.Contains(given.String(), "<verification_value>123</verification_value>") {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>active</state>
+			</subscription>`)
+	})
+
+	var lookups int
+	mux.HandleFunc("/v2/invoices/1108", func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108">
+				<invoice_number type="integer">1108</invoice_number>
+				<transactions type="array">
+					<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+						<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+						<action>purchase</action>
+						<amount_in_cents type="integer">1000</amount_in_cents>
+						<status>success</status>
+						<cvv_result code="M">Match</cvv_result>
+						<avs_result code="A">Street address matches, but 5-digit and 9-digit postal code do not match.</avs_result>
+					</transaction>
+				</transactions>
+			</invoice>`)
+	})
+
+	sub := recurly.NewSubscription{
+		PlanCode: "gold",
+		Currency: "USD",
+		Account: recurly.Account{
+			Code: "1",
+			BillingInfo: &recurly.Billing{
+				Number:            4111111111111111,
+				VerificationValue: 123,
+				Month:             10,
+				Year:              2030,
+			},
+		},
+	}
+
+	// Create doesn't look up the transaction.
+	if _, resp, err := client.Subscriptions.Create(sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.Transaction != nil || lookups != 0 {
+		t.Fatalf("unexpected transaction lookup: %#v", resp.Transaction)
+	}
+
+	r, resp, err := client.Subscriptions.CreateWithTransaction(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected create subscription to return OK")
+	} else if resp.Subscription == nil || resp.Subscription.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", resp.Subscription)
+	} else if resp.Transaction == nil {
+		t.Fatal("expected purchase transaction")
+	} else if !resp.Transaction.CVVResult.IsMatch() {
+		t.Fatalf("unexpected cvv result: %#v", resp.Transaction.CVVResult)
+	} else if resp.Transaction.AVSResult.Code != "A" {
+		t.Fatalf("unexpected avs result: %#v", resp.Transaction.AVSResult)
+	} else if lookups != 1 {
+		t.Fatalf("unexpected lookups: %d", lookups)
+	}
+}
+
+func TestSubscriptions_CreateWithTransaction_LookupError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>active</state>
+			</subscription>`)
+	})
+
+	mux.HandleFunc("/v2/invoices/1108", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	r, resp, err := client.Subscriptions.CreateWithTransaction(recurly.NewSubscription{
+		PlanCode: "gold",
+		Currency: "USD",
+		Account:  recurly.Account{Code: "1"},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	} else if r.StatusCode != 201 {
+		t.Fatalf("unexpected status code: %d", r.StatusCode)
+	} else if resp.Subscription == nil || resp.Subscription.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", resp.Subscription)
+	} else if resp.Transaction != nil {
+		t.Fatalf("unexpected transaction: %#v", resp.Transaction)
+	}
+}
+
+func TestSubscriptions_Create_TransactionError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+			  <transaction_error>
+			    <error_code>declined_saveable</error_code>
+			    <error_category>soft</error_category>
+			    <merchant_message>The transaction was declined without specific information.  Please contact your payment gateway for more details or ask the customer to contact their bank.</merchant_message>
+			    <customer_message>The transaction was declined. Please use a different card or contact your bank.</customer_message>
+			    <gateway_error_code nil="nil"/>
+			  </transaction_error>
+			  <error field="subscription.account.base" symbol="declined_saveable">The transaction was declined. Please use a different card or contact your bank.</error>
+			  <transaction href="https://your-subdomain.recurly.com/v2/transactions/3c42a3ecc46a7aa602602e4033b9c2e6" type="credit_card">
+			    <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			    <subscription href="https://your-subdomain.recurly.com/v2/subscriptions/3c42a3ebabdc022739d5a646408291a6"/>
+			    <uuid>3c42a3ecc46a7aa602602e4033b9c2e6</uuid>
+			    <action>purchase</action>
+			    <amount_in_cents type="integer">5274</amount_in_cents>
+			    <currency>USD</currency>
+			    <status>declined</status>
+			    <payment_method>credit_card</payment_method>
+			    <transaction_error>
+			      <error_code>declined_saveable</error_code>
+			      <error_category>soft</error_category>
+			      <merchant_message>The transaction was declined without specific information.  Please contact your payment gateway for more details or ask the customer to contact their bank.</merchant_message>
+			      <customer_message>The transaction was declined. Please use a different card or contact your bank.</customer_message>
+			      <gateway_error_code nil="nil"/>
+			    </transaction_error>
+				<details>
+			    </details>
+			  </transaction>
+			</errors>`)
+	})
+
+	r, newSubscription, err := client.Subscriptions.Create(recurly.NewSubscription{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !r.IsError() {
+		t.Fatal("expected create subscription to return OK")
+	} else if newSubscription.Transaction == nil {
+		t.Fatal("expected transaction to be set")
+	} else if !reflect.DeepEqual(newSubscription.Transaction, &recurly.Transaction{
+		UUID:             "3c42a3ecc46a7aa602602e4033b9c2e6",
+		SubscriptionUUID: "3c42a3ebabdc022739d5a646408291a6",
+		Action:           "purchase",
+		AmountInCents:    5274,
+		Currency:         "USD",
+		Status:           "declined",
+		PaymentMethod:    "credit_card",
+		TransactionError: &recurly.TransactionError{
+			XMLName:         xml.Name{Local: "transaction_error"},
+			ErrorCode:       "declined_saveable",
+			ErrorCategory:   "soft",
+			MerchantMessage: "The transaction was declined without specific information.  Please contact your payment gateway for more details or ask the customer to contact their bank.",
+			CustomerMessage: "The transaction was declined. Please use a different card or contact your bank.",
+		},
+	}) {
+		t.Fatalf("unexpected transaction error: %v", newSubscription.Transaction.TransactionError)
+	} else if newSubscription.Subscription != nil {
+		t.Fatalf("unexpected subscription: %v", newSubscription.Subscription)
+	}
+}
+
+func TestSubscriptions_Create_TransactionErrorDetails(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+			  <error field="subscription.account.base" symbol="declined">The transaction was declined.</error>
+			  <transaction href="https://your-subdomain.recurly.com/v2/transactions/3c42a3ecc46a7aa602602e4033b9c2e6" type="credit_card">
+			    <uuid>3c42a3ecc46a7aa602602e4033b9c2e6</uuid>
+			    <status>declined</status>
+			    <transaction_error>
+			      <error_code>declined</error_code>
+			      <error_category>soft</error_category>
+			    </transaction_error>
+			    <details>
+			      <account>
+			        <account_code>1</account_code>
+			      </account>
+			      <detail field="billing_info.zip" symbol="avs_mismatch">Postal code does not match</detail>
+			      <detail field="billing_info.verification_value" symbol="cvv_mismatch">Security code does not match</detail>
+			    </details>
+			  </transaction>
+			</errors>`)
+	})
+
+	_, resp, err := client.Subscriptions.Create(recurly.NewSubscription{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.Transaction == nil || resp.Transaction.TransactionError == nil {
+		t.Fatal("expected transaction error to be set")
+	} else if resp.Transaction.Account.Code != "1" {
+		t.Fatalf("unexpected account: %#v", resp.Transaction.Account)
+	} else if !reflect.DeepEqual(resp.Transaction.TransactionError.Details, []recurly.TransactionErrorDetail{
+		{
+			XMLName: xml.Name{Local: "detail"},
+			Field:   "billing_info.zip",
+			Symbol:  "avs_mismatch",
+			Message: "Postal code does not match",
+		},
+		{
+			XMLName: xml.Name{Local: "detail"},
+			Field:   "billing_info.verification_value",
+			Symbol:  "cvv_mismatch",
+			Message: "Security code does not match",
+		},
+	}) {
+		t.Fatalf("unexpected details: %#v", resp.Transaction.TransactionError.Details)
+	}
+}
+
+func TestSubscriptions_Create_ValidationError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+			  <error field="subscription.plan_code" symbol="blank">can't be blank</error>
+			  <error field="subscription.account.email" symbol="invalid_email">is not a valid email address</error>
+			</errors>`)
+	})
+
+	r, newSubscription, err := client.Subscriptions.Create(recurly.NewSubscription{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("unexpected status code: %d", r.StatusCode)
+	} else if newSubscription.Subscription != nil || newSubscription.Transaction != nil {
+		t.Fatalf("unexpected response: %#v", newSubscription)
+	} else if !reflect.DeepEqual(r.Errors, []recurly.Error{
+		{
+			XMLName: xml.Name{Local: "error"},
+			Field:   "subscription.plan_code",
+			Symbol:  "blank",
+			Message: "can't be blank",
+		},
+		{
+			XMLName: xml.Name{Local: "error"},
+			Field:   "subscription.account.email",
+			Symbol:  "invalid_email",
+			Message: "is not a valid email address",
+		},
+	}) {
+		t.Fatalf("unexpected errors: %#v", r.Errors)
+	}
+}
+
+func TestSubscriptions_Preview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/preview", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	r, _, err := client.Subscriptions.Preview(recurly.NewSubscription{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected preview subscription to return OK")
+	}
+}
+
+func TestSubscriptions_Preview_Invoice(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/preview", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription>
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				<plan_code>gold</plan_code>
+				<name>Gold plan</name>
+			</plan>
+			<state>active</state>
+			<unit_amount_in_cents type="integer">2000</unit_amount_in_cents>
+			<currency>USD</currency>
+			<quantity type="integer">1</quantity>
+			<invoice_collection>
+				<charge_invoice>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+					<state>pending</state>
+					<subtotal_in_cents type="integer">2000</subtotal_in_cents>
+					<tax_in_cents type="integer">158</tax_in_cents>
+					<total_in_cents type="integer">2158</total_in_cents>
+					<currency>USD</currency>
+					<tax_type>usst</tax_type>
+					<tax_region>CA</tax_region>
+					<tax_rate type="float">0.0875</tax_rate>
+					<line_items type="array">
+						<adjustment type="charge">
+							<description>Gold plan</description>
+							<origin>plan</origin>
+							<unit_amount_in_cents type="integer">2000</unit_amount_in_cents>
+							<quantity type="integer">1</quantity>
+							<discount_in_cents type="integer">200</discount_in_cents>
+							<tax_in_cents type="integer">158</tax_in_cents>
+							<total_in_cents type="integer">1958</total_in_cents>
+							<currency>USD</currency>
+						</adjustment>
+						<adjustment type="charge">
+							<description>Setup fee</description>
+							<origin>setup_fee</origin>
+							<unit_amount_in_cents type="integer">200</unit_amount_in_cents>
+							<quantity type="integer">1</quantity>
+							<discount_in_cents type="integer">0</discount_in_cents>
+							<tax_in_cents type="integer">0</tax_in_cents>
+							<total_in_cents type="integer">200</total_in_cents>
+							<currency>USD</currency>
+						</adjustment>
+					</line_items>
+					<transactions type="array">
+					</transactions>
+				</charge_invoice>
+				<credit_invoices type="array">
+				</credit_invoices>
+			</invoice_collection>
+		</subscription>`)
+	})
+
+	r, preview, err := client.Subscriptions.Preview(recurly.NewSubscription{
+		PlanCode:   "gold",
+		Currency:   "USD",
+		CouponCode: "10off",
+		Account:    recurly.Account{Code: "1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected preview subscription to return OK")
+	} else if preview.SubtotalInCents != 2000 || preview.DiscountInCents != 200 || preview.TaxInCents != 158 || preview.TotalInCents != 2158 {
+		t.Fatalf("unexpected totals: %+v", preview)
+	} else if !reflect.DeepEqual(preview.Subscription, recurly.Subscription{
+		XMLName:           xml.Name{Local: "subscription"},
+		Plan:              recurly.NestedPlan{Code: "gold", Name: "Gold plan"},
+		AccountCode:       "1",
+		State:             "active",
+		UnitAmountInCents: 2000,
+		Currency:          "USD",
+		Quantity:          1,
+	}) {
+		t.Fatalf("unexpected subscription: %#v", preview.Subscription)
+	} else if preview.Invoice == nil || preview.Invoice.State != "pending" || len(preview.Invoice.LineItems) != 2 {
+		t.Fatalf("unexpected invoice: %#v", preview.Invoice)
+	}
+}
+
+// Older API versions return the previewed invoice directly in <invoice>.
+func TestSubscriptions_Preview_LegacyInvoice(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/preview", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<invoice>
+				<subtotal_in_cents type="integer">1000</subtotal_in_cents>
+				<tax_in_cents type="integer">0</tax_in_cents>
+				<total_in_cents type="integer">1000</total_in_cents>
+				<currency>USD</currency>
+			</invoice>
+		</subscription>`)
+	})
+
+	_, preview, err := client.Subscriptions.Preview(recurly.NewSubscription{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if preview.Subscription.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", preview.Subscription)
+	} else if preview.Invoice == nil || preview.SubtotalInCents != 1000 || preview.TotalInCents != 1000 {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+}
+
+func TestSubscriptions_PreviewRenewal(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview_renewal", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<invoice_collection>
+			<charge_invoice href="">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<state>pending</state>
+				<type>charge</type>
+				<subtotal_in_cents type="integer">3450</subtotal_in_cents>
+				<tax_in_cents type="integer">0</tax_in_cents>
+				<total_in_cents type="integer">3450</total_in_cents>
+				<currency>USD</currency>
+				<line_items type="array">
+					<adjustment href="">
+						<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96"/>
+						<description>Gold plan</description>
+						<origin>plan</origin>
+						<unit_amount_in_cents type="integer">3000</unit_amount_in_cents>
+						<quantity type="integer">1</quantity>
+						<total_in_cents type="integer">3000</total_in_cents>
+						<currency>USD</currency>
+					</adjustment>
+					<adjustment href="">
+						<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96"/>
+						<description>API calls</description>
+						<origin>add_on</origin>
+						<unit_amount_in_cents type="integer">3</unit_amount_in_cents>
+						<quantity type="integer">150</quantity>
+						<total_in_cents type="integer">450</total_in_cents>
+						<currency>USD</currency>
+					</adjustment>
+				</line_items>
+			</charge_invoice>
+			<credit_invoices type="array">
+			</credit_invoices>
+		</invoice_collection>`)
+	})
+
+	resp, collection, err := client.Subscriptions.PreviewRenewal("44f83d7c-ba35-4d5b-8481-2419f923ea96")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected preview renewal to return OK")
+	} else if cents, currency := collection.Total(); cents != 3450 || currency != "USD" {
+		t.Fatalf("unexpected total: %d %s", cents, currency)
+	} else if len(collection.ChargeInvoice.LineItems) != 2 {
+		t.Fatalf("unexpected line items: %#v", collection.ChargeInvoice.LineItems)
+	} else if a := collection.ChargeInvoice.LineItems[1]; a.Description != "API calls" || a.Quantity != 150 || a.TotalInCents != 450 {
+		t.Fatalf("unexpected usage line item: %#v", a)
+	}
+}
+
+func TestSubscriptions_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	r, _, err := client.Subscriptions.Update("44f83d7cba-354d5b84812419-f923ea96", recurly.UpdateSubscription{}) // UUID has dashes and should be sanitized
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected update subscription to return OK")
+	}
+}
+
+func TestSubscriptions_IncrementAddOn(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+					<plan_code>gold</plan_code>
+					<name>Gold plan</name>
+				</plan>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<currency>USD</currency>
+				<net_terms type="integer">30</net_terms>
+				<subscription_add_ons type="array">
+					<subscription_add_on>
+						<add_on_code>extra_users</add_on_code>
+						<unit_amount_in_cents type="integer">1000</unit_amount_in_cents>
+						<quantity type="integer">2</quantity>
+					</subscription_add_on>
+					<subscription_add_on>
+						<add_on_code>support</add_on_code>
+						<unit_amount_in_cents type="integer">500</unit_amount_in_cents>
+						<quantity type="integer">1</quantity>
+					</subscription_add_on>
+				</subscription_add_ons>
+			</subscription>`)
+		case "PUT":
+			var given bytes.Buffer
+			given.ReadFrom(r.Body)
+			expected := "<subscription><net_terms>30</net_terms><subscription_add_ons><subscription_add_on><add_on_code>extra_users</add_on_code><unit_amount_in_cents>1000</unit_amount_in_cents><quantity>5</quantity></subscription_add_on><subscription_add_on><add_on_code>support</add_on_code><unit_amount_in_cents>500</unit_amount_in_cents><quantity>1</quantity></subscription_add_on></subscription_add_ons></subscription>"
+			if expected != given.String() {
+				t.Fatalf("unexpected input: %s", given.String())
+			}
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+		default:
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+	})
+
+	r, sub, err := client.Subscriptions.IncrementAddOn("44f83d7cba354d5b84812419f923ea96", "extra_users", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected increment add on to return OK")
+	} else if sub.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+}
+
+func TestSubscriptions_IncrementAddOn_New(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+					<plan_code>gold</plan_code>
+					<name>Gold plan</name>
+				</plan>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<currency>EUR</currency>
+				<net_terms type="integer">0</net_terms>
+				<subscription_add_ons type="array">
+					<subscription_add_on>
+						<add_on_code>support</add_on_code>
+						<unit_amount_in_cents type="integer">500</unit_amount_in_cents>
+						<quantity type="integer">1</quantity>
+					</subscription_add_on>
+				</subscription_add_ons>
+			</subscription>`)
+		case "PUT":
+			var given bytes.Buffer
+			given.ReadFrom(r.Body)
+			expected := "<subscription><net_terms>0</net_terms><subscription_add_ons><subscription_add_on><add_on_code>support</add_on_code><unit_amount_in_cents>500</unit_amount_in_cents><quantity>1</quantity></subscription_add_on><subscription_add_on><add_on_code>extra_users</add_on_code><unit_amount_in_cents>800</unit_amount_in_cents><quantity>2</quantity></subscription_add_on></subscription_add_ons></subscription>"
+			if expected != given.String() {
+				t.Fatalf("unexpected input: %s", given.String())
+			}
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid></subscription>`)
+		default:
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+	})
+
+	mux.HandleFunc("/v2/plans/gold/add_ons/extra_users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<add_on>
+			<add_on_code>extra_users</add_on_code>
+			<unit_amount_in_cents>
+				<USD type="integer">1000</USD>
+				<EUR type="integer">800</EUR>
+			</unit_amount_in_cents>
+		</add_on>`)
+	})
+
+	r, _, err := client.Subscriptions.IncrementAddOn("44f83d7cba354d5b84812419f923ea96", "extra_users", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected increment add on to return OK")
+	}
+
+	if _, _, err := client.Subscriptions.IncrementAddOn("44f83d7cba354d5b84812419f923ea96", "unknown", -1); err == nil {
+		t.Fatal("expected error decrementing missing add on")
+	}
+}
+
+func TestSubscriptions_Notes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/notes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	r, _, err := client.Subscriptions.UpdateNotes("44f83d7cba354d5-b8481241-9f923ea96", recurly.SubscriptionNotes{}) // UUID has dashes and should be sanitized
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected update subscription notes to return OK")
+	}
+}
+
+func TestSubscriptions_Change(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
@@ -743,109 +2766,180 @@ func TestSubscriptions_Create(t *testing.T) {
 		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.Create(recurly.NewSubscription{})
+	r, _, err := client.Subscriptions.PreviewChange("44f83d7cba-354d5b84812-419f923ea96", recurly.UpdateSubscription{}) // UUID has dashes and should be sanitized
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected create subscription to return OK")
+		t.Fatal("expected preview subscription change to return OK")
 	}
 }
 
-func TestSubscriptions_Create_TransactionError(t *testing.T) {
+func TestSubscriptions_PreviewChangeDetails_AddOnCharges(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
-		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.WriteHeader(200)
 		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
-			<errors>
-			  <transaction_error>
-			    <error_code>declined_saveable</error_code>
-			    <error_category>soft</error_category>
-			    <merchant_message>The transaction was declined without specific information.  Please contact your payment gateway for more details or ask the customer to contact their bank.</merchant_message>
-			    <customer_message>The transaction was declined. Please use a different card or contact your bank.</customer_message>
-			    <gateway_error_code nil="nil"/>
-			  </transaction_error>
-			  <error field="subscription.account.base" symbol="declined_saveable">The transaction was declined. Please use a different card or contact your bank.</error>
-			  <transaction href="https://your-subdomain.recurly.com/v2/transactions/3c42a3ecc46a7aa602602e4033b9c2e6" type="credit_card">
-			    <account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
-			    <subscription href="https://your-subdomain.recurly.com/v2/subscriptions/3c42a3ebabdc022739d5a646408291a6"/>
-			    <uuid>3c42a3ecc46a7aa602602e4033b9c2e6</uuid>
-			    <action>purchase</action>
-			    <amount_in_cents type="integer">5274</amount_in_cents>
-			    <currency>USD</currency>
-			    <status>declined</status>
-			    <payment_method>credit_card</payment_method>
-			    <transaction_error>
-			      <error_code>declined_saveable</error_code>
-			      <error_category>soft</error_category>
-			      <merchant_message>The transaction was declined without specific information.  Please contact your payment gateway for more details or ask the customer to contact their bank.</merchant_message>
-			      <customer_message>The transaction was declined. Please use a different card or contact your bank.</customer_message>
-			      <gateway_error_code nil="nil"/>
-			    </transaction_error>
-				<details>
-			    </details>
-			  </transaction>
-			</errors>`)
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>active</state>
+				<invoice_collection>
+					<charge_invoice>
+						<state>pending</state>
+						<currency>USD</currency>
+						<total_in_cents type="integer">1750</total_in_cents>
+						<line_items type="array">
+							<adjustment>
+								<origin>add_on</origin>
+								<product_code>ipaddresses</product_code>
+								<total_in_cents type="integer">500</total_in_cents>
+								<currency>USD</currency>
+							</adjustment>
+							<adjustment>
+								<origin>add_on</origin>
+								<product_code>backups</product_code>
+								<total_in_cents type="integer">1000</total_in_cents>
+								<currency>USD</currency>
+							</adjustment>
+							<adjustment>
+								<origin>add_on</origin>
+								<product_code>ipaddresses</product_code>
+								<total_in_cents type="integer">250</total_in_cents>
+								<currency>USD</currency>
+							</adjustment>
+							<adjustment>
+								<origin>plan</origin>
+								<product_code>gold</product_code>
+								<total_in_cents type="integer">0</total_in_cents>
+								<currency>USD</currency>
+							</adjustment>
+						</line_items>
+					</charge_invoice>
+				</invoice_collection>
+			</subscription>`)
 	})
 
-	r, newSubscription, err := client.Subscriptions.Create(recurly.NewSubscription{})
+	sub := recurly.UpdateSubscription{
+		SubscriptionAddOns: &[]recurly.SubscriptionAddOn{
+			{Code: "ipaddresses", Quantity: 3},
+			{Code: "backups", Quantity: 1},
+		},
+	}
+	r, p, err := client.Subscriptions.PreviewChangeDetails("44f83d7cba354d5b84812419f923ea96", sub)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
-	} else if !r.IsError() {
-		t.Fatal("expected create subscription to return OK")
-	} else if newSubscription.Transaction == nil {
-		t.Fatal("expected transaction to be set")
-	} else if !reflect.DeepEqual(newSubscription.Transaction, &recurly.Transaction{
-		UUID:             "3c42a3ecc46a7aa602602e4033b9c2e6",
-		SubscriptionUUID: "3c42a3ebabdc022739d5a646408291a6",
-		Action:           "purchase",
-		AmountInCents:    5274,
-		Currency:         "USD",
-		Status:           "declined",
-		PaymentMethod:    "credit_card",
-		TransactionError: &recurly.TransactionError{
-			XMLName:         xml.Name{Local: "transaction_error"},
-			ErrorCode:       "declined_saveable",
-			ErrorCategory:   "soft",
-			MerchantMessage: "The transaction was declined without specific information.  Please contact your payment gateway for more details or ask the customer to contact their bank.",
-			CustomerMessage: "The transaction was declined. Please use a different card or contact your bank.",
-		},
+	} else if r.IsError() {
+		t.Fatal("expected preview subscription change to return OK")
+	} else if p.Subscription.UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscription: %#v", p.Subscription)
+	} else if p.Invoice == nil || p.Invoice.TotalInCents != 1750 {
+		t.Fatalf("unexpected invoice: %#v", p.Invoice)
+	}
+
+	if charges := p.AddOnCharges(); !reflect.DeepEqual(charges, map[string]int{
+		"ipaddresses": 750,
+		"backups":     1000,
 	}) {
-		t.Fatalf("unexpected transaction error: %v", newSubscription.Transaction.TransactionError)
-	} else if newSubscription.Subscription != nil {
-		t.Fatalf("unexpected subscription: %v", newSubscription.Subscription)
+		t.Fatalf("unexpected charges: %#v", charges)
+	}
+
+	if charges := (recurly.SubscriptionChangePreview{}).AddOnCharges(); charges != nil {
+		t.Fatalf("unexpected charges: %#v", charges)
 	}
 }
 
-func TestSubscriptions_Preview(t *testing.T) {
+// previewCache is an in-memory recurly.PreviewCache used for testing.
+type previewCache map[string]*recurly.SubscriptionChangePreview
+
+func (c previewCache) Get(key string) (*recurly.SubscriptionChangePreview, bool) {
+	p, ok := c[key]
+	return p, ok
+}
+
+func (c previewCache) Set(key string, p *recurly.SubscriptionChangePreview) {
+	c[key] = p
+}
+
+func TestSubscriptions_Change_Cache(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions/preview", func(w http.ResponseWriter, r *http.Request) {
+	var invoked int
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
+		invoked++
 		w.WriteHeader(201)
-		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><quantity>2</quantity></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.Preview(recurly.NewSubscription{})
+	cache := previewCache{}
+	sub := recurly.UpdateSubscription{Quantity: 2}
+	r, first, err := client.Subscriptions.PreviewChangeWithCache("44f83d7cba354d5b84812419f923ea96", sub, cache)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected preview subscription to return OK")
+		t.Fatal("expected preview subscription change to return OK")
+	} else if invoked != 1 {
+		t.Fatalf("unexpected invocations: %d", invoked)
+	} else if len(cache) != 1 {
+		t.Fatalf("unexpected cache size: %d", len(cache))
+	}
+
+	// An identical preview should be served from the cache, even if the uuid
+	// is formatted differently.
+	r, second, err := client.Subscriptions.PreviewChangeWithCache("44f83d7cba-354d5b84812-419f923ea96", sub, cache)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected preview subscription change to return OK")
+	} else if invoked != 1 {
+		t.Fatalf("expected cache hit, invocations: %d", invoked)
+	} else if r.Attempts != 0 {
+		t.Fatalf("unexpected attempts: %d", r.Attempts)
+	} else if second == first || !reflect.DeepEqual(second, first) {
+		t.Fatalf("unexpected subscription: %#v", second)
+	}
+
+	// Modifying a returned preview should not affect later hits.
+	second.Quantity = 10
+	if _, third, err := client.Subscriptions.PreviewChangeWithCache("44f83d7cba354d5b84812419f923ea96", sub, cache); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if invoked != 1 {
+		t.Fatalf("expected cache hit, invocations: %d", invoked)
+	} else if third.Quantity != 2 {
+		t.Fatalf("unexpected quantity: %d", third.Quantity)
+	}
+
+	// A different change should miss the cache.
+	if _, _, err := client.Subscriptions.PreviewChangeWithCache("44f83d7cba354d5b84812419f923ea96", recurly.UpdateSubscription{Quantity: 3}, cache); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if invoked != 2 {
+		t.Fatalf("expected cache miss, invocations: %d", invoked)
+	} else if len(cache) != 2 {
+		t.Fatalf("unexpected cache size: %d", len(cache))
+	}
+
+	// Without a cache every preview is requested.
+	if r, sub, err := client.Subscriptions.PreviewChangeWithCache("44f83d7cba354d5b84812419f923ea96", sub, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.StatusCode != 201 || sub.Quantity != 2 {
+		t.Fatalf("unexpected preview: %d %#v", r.StatusCode, sub)
+	} else if invoked != 3 {
+		t.Fatalf("expected request, invocations: %d", invoked)
 	}
 }
 
-func TestSubscriptions_Update(t *testing.T) {
+func TestSubscriptions_Cancel(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PUT" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
@@ -853,59 +2947,111 @@ func TestSubscriptions_Update(t *testing.T) {
 		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.Update("44f83d7cba-354d5b84812419-f923ea96", recurly.UpdateSubscription{}) // UUID has dashes and should be sanitized
+	r, _, err := client.Subscriptions.Cancel("44f83d7cba-354d5b848124-19f923ea96") // UUID has dashes and should be sanitized
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected update subscription to return OK")
+		t.Fatal("expected cancel subscription change to return OK")
 	}
 }
 
-func TestSubscriptions_Notes(t *testing.T) {
+func TestSubscriptions_CancelIfActive(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/notes", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PUT" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
 		w.WriteHeader(200)
-		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><state>canceled</state></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.UpdateNotes("44f83d7cba354d5-b8481241-9f923ea96", recurly.SubscriptionNotes{}) // UUID has dashes and should be sanitized
+	r, sub, err := client.Subscriptions.CancelIfActive("44f83d7cba354d5b84812419f923ea96")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected update subscription notes to return OK")
+		t.Fatal("expected cancel subscription to return OK")
+	} else if sub.State != recurly.SubscriptionStateCanceled {
+		t.Fatalf("unexpected state: %s", sub.State)
 	}
 }
 
-func TestSubscriptions_Change(t *testing.T) {
+func TestSubscriptions_CancelIfActive_AlreadyCanceled(t *testing.T) {
 	setup()
 	defer teardown()
 
-	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/preview", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "POST" {
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="subscription.state" symbol="invalid_transition">is invalid</error></errors>`)
+	})
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
-		w.WriteHeader(201)
-		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>expired</state></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.PreviewChange("44f83d7cba-354d5b84812-419f923ea96", recurly.UpdateSubscription{}) // UUID has dashes and should be sanitized
+	r, sub, err := client.Subscriptions.CancelIfActive("44f83d7cba354d5b84812419f923ea96")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected preview subscription change to return OK")
+		t.Fatal("expected already canceled subscription to return OK")
+	} else if sub.State != recurly.SubscriptionStateExpired {
+		t.Fatalf("unexpected state: %s", sub.State)
 	}
 }
 
-func TestSubscriptions_Cancel(t *testing.T) {
+func TestSubscriptions_CancelIfActive_Error(t *testing.T) {
 	setup()
 	defer teardown()
 
+	var symbol string
+	var invoked bool
 	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/cancel", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><errors><error field="subscription.state" symbol="%s">is invalid</error></errors>`, symbol)
+	})
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>future</state></subscription>`)
+	})
+
+	// Other errors are returned without looking up the subscription.
+	symbol = "not_a_number"
+	r, _, err := client.Subscriptions.CancelIfActive("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !r.IsError() {
+		t.Fatal("expected cancel subscription to return an error")
+	} else if r.Errors[0].Symbol != "not_a_number" {
+		t.Fatalf("unexpected errors: %#v", r.Errors)
+	} else if invoked {
+		t.Fatal("expected subscription not to be looked up")
+	}
+
+	// An invalid transition is an error unless the subscription is
+	// already canceled or expired.
+	symbol = "invalid_transition"
+	r, _, err = client.Subscriptions.CancelIfActive("44f83d7cba354d5b84812419f923ea96")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !invoked {
+		t.Fatal("expected subscription to be looked up")
+	} else if r.StatusCode != http.StatusUnprocessableEntity || r.Errors[0].Symbol != "invalid_transition" {
+		t.Fatalf("unexpected response: %d %#v", r.StatusCode, r.Errors)
+	}
+}
+
+func TestSubscriptions_Reactivate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/reactivate", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PUT" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
@@ -913,31 +3059,69 @@ func TestSubscriptions_Cancel(t *testing.T) {
 		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.Cancel("44f83d7cba-354d5b848124-19f923ea96") // UUID has dashes and should be sanitized
+	r, _, err := client.Subscriptions.Reactivate("44f83d7cba35-4d5b8481241-9f923ea96") // UUID has dashes and should be sanitized
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected cancel subscription change to return OK")
+		t.Fatal("expected reactivate subscription change to return OK")
 	}
 }
 
-func TestSubscriptions_Reactivate(t *testing.T) {
+func TestSubscriptions_ReactivateWithOptions(t *testing.T) {
 	setup()
 	defer teardown()
 
+	var reactivated bool
 	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/reactivate", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PUT" {
 			t.Fatalf("unexpected method: %s", r.Method)
 		}
+		reactivated = true
 		w.WriteHeader(200)
-		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><state>active</state><current_period_ends_at type="datetime">2015-06-01T07:00:00Z</current_period_ends_at></subscription>`)
 	})
 
-	r, _, err := client.Subscriptions.Reactivate("44f83d7cba35-4d5b8481241-9f923ea96") // UUID has dashes and should be sanitized
+	next := time.Now().UTC().AddDate(0, 1, 0).Truncate(time.Second)
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/postpone", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if !reactivated {
+			t.Fatal("expected subscription to be reactivated before it is postponed")
+		} else if nrd := r.URL.Query().Get("next_renewal_date"); nrd != next.Format(time.RFC3339) {
+			t.Fatalf("unexpected input for next_renewal_date: %s", nrd)
+		} else if bulk := r.URL.Query().Get("bulk"); bulk != "true" {
+			t.Fatalf("unexpected input for bulk: %s", bulk)
+		}
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><state>active</state><current_period_ends_at type="datetime">%s</current_period_ends_at></subscription>`, next.Format(recurly.DateTimeFormat))
+	})
+
+	r, sub, err := client.Subscriptions.ReactivateWithOptions("44f83d7cba354d5b84812419f923ea96", recurly.ReactivateOptions{
+		NextRenewalDate: next,
+		Bulk:            true,
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if r.IsError() {
-		t.Fatal("expected reactivate subscription change to return OK")
+		t.Fatal("expected reactivate subscription to return OK")
+	} else if !sub.CurrentPeriodEndsAt.Time.Equal(next) {
+		t.Fatalf("unexpected current period ends at: %v", sub.CurrentPeriodEndsAt)
+	}
+
+	// Without a renewal date only the reactivation is sent.
+	reactivated = false
+	if _, sub, err := client.Subscriptions.ReactivateWithOptions("44f83d7cba354d5b84812419f923ea96", recurly.ReactivateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reactivated || sub.State != "active" {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+
+	reactivated = false
+	past := time.Now().AddDate(0, 0, -1)
+	if _, _, err := client.Subscriptions.ReactivateWithOptions("44f83d7cba354d5b84812419f923ea96", recurly.ReactivateOptions{NextRenewalDate: past}); err == nil {
+		t.Fatal("expected error for a renewal date in the past")
+	} else if reactivated {
+		t.Fatal("expected invalid options not to be sent")
 	}
 }
 
@@ -1007,6 +3191,164 @@ func TestSubscriptions_Terminate_WithoutRefund(t *testing.T) {
 	}
 }
 
+func TestSubscriptions_TerminateWithResult(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if refundType := r.URL.Query().Get("refund_type"); refundType != "partial" {
+			t.Fatalf("unexpected input for refund_type: %s", refundType)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>expired</state>
+				<expires_at type="datetime">2019-06-10T15:25:06Z</expires_at>
+			</subscription>`)
+	})
+
+	mux.HandleFunc("/v2/accounts/1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if typ := r.URL.Query().Get("type"); typ != "refund" {
+			t.Fatalf("unexpected type: %s", typ)
+		} else if sort, order := r.URL.Query().Get("sort"), r.URL.Query().Get("order"); sort != "created_at" || order != "desc" {
+			t.Fatalf("unexpected sort: %s %s", sort, order)
+		}
+
+		// The first page holds a newer refund for another subscription.
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/accounts/1/transactions?cursor=1304958672>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+				<transactions type="array">
+					<transaction href="https://your-subdomain.recurly.com/v2/transactions/b23acd8fe4294916b79aec87b7ea441f" type="credit_card">
+						<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/17caaca1716f33572edc8146e0aaefde"/>
+						<uuid>b23acd8fe4294916b79aec87b7ea441f</uuid>
+						<action>refund</action>
+						<amount_in_cents type="integer">500</amount_in_cents>
+						<created_at type="datetime">2019-06-10T15:30:00Z</created_at>
+					</transaction>
+				</transactions>`)
+			return
+		}
+
+		// The refund was created just before expires_at, and is followed by
+		// an older refund for the subscription.
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transactions type="array">
+				<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+					<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96"/>
+					<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+					<action>refund</action>
+					<amount_in_cents type="integer">1250</amount_in_cents>
+					<currency>USD</currency>
+					<created_at type="datetime">2019-06-10T15:25:05Z</created_at>
+				</transaction>
+				<transaction href="https://your-subdomain.recurly.com/v2/transactions/c33acd8fe4294916b79aec87b7ea441f" type="credit_card">
+					<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96"/>
+					<uuid>c33acd8fe4294916b79aec87b7ea441f</uuid>
+					<action>refund</action>
+					<amount_in_cents type="integer">300</amount_in_cents>
+					<created_at type="datetime">2015-06-10T15:25:06Z</created_at>
+				</transaction>
+			</transactions>`)
+	})
+
+	r, result, err := client.Subscriptions.TerminateWithResult("44f83d7c-ba354d5b84812419f923ea96", recurly.RefundTypePartial)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected terminate subscription with result to return OK")
+	} else if result.Subscription == nil || result.Subscription.State != "expired" {
+		t.Fatalf("unexpected subscription: %#v", result.Subscription)
+	} else if result.RefundInCents != 1250 {
+		t.Fatalf("unexpected refund: %d", result.RefundInCents)
+	} else if result.RefundTransaction == nil || result.RefundTransaction.UUID != "a13acd8fe4294916b79aec87b7ea441f" {
+		t.Fatalf("unexpected refund transaction: %#v", result.RefundTransaction)
+	}
+
+	if _, _, err := client.Subscriptions.TerminateWithResult("44f83d7cba354d5b84812419f923ea96", "bogus"); err == nil {
+		t.Fatal("expected error for invalid refund type")
+	}
+}
+
+func TestSubscriptions_TerminateWithResult_RefundNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>expired</state>
+				<expires_at type="datetime">2019-06-10T15:25:06Z</expires_at>
+			</subscription>`)
+	})
+
+	// Only an older refund exists for the subscription.
+	mux.HandleFunc("/v2/accounts/1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transactions type="array">
+				<transaction href="https://your-subdomain.recurly.com/v2/transactions/c33acd8fe4294916b79aec87b7ea441f" type="credit_card">
+					<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96"/>
+					<uuid>c33acd8fe4294916b79aec87b7ea441f</uuid>
+					<action>refund</action>
+					<amount_in_cents type="integer">300</amount_in_cents>
+					<created_at type="datetime">2015-06-10T15:25:06Z</created_at>
+				</transaction>
+			</transactions>`)
+	})
+
+	r, result, err := client.Subscriptions.TerminateWithResult("44f83d7cba354d5b84812419f923ea96", recurly.RefundTypeFull)
+	if err != recurly.ErrRefundNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.StatusCode != 200 {
+		t.Fatalf("unexpected status code: %d", r.StatusCode)
+	} else if result.Subscription == nil || result.RefundTransaction != nil {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+}
+
+func TestSubscriptions_TerminateWithResult_ListError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/terminate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<state>expired</state>
+			</subscription>`)
+	})
+
+	mux.HandleFunc("/v2/accounts/1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	// The terminate response is returned even though listing the refund failed.
+	r, result, err := client.Subscriptions.TerminateWithResult("44f83d7cba354d5b84812419f923ea96", recurly.RefundTypeFull)
+	if err == nil {
+		t.Fatal("expected error")
+	} else if r == nil || r.StatusCode != 200 || r.Request.Method != "PUT" {
+		t.Fatalf("unexpected response: %#v", r)
+	} else if result == nil || result.Subscription == nil || result.Subscription.State != "expired" {
+		t.Fatalf("unexpected result: %#v", result)
+	} else if result.RefundTransaction != nil {
+		t.Fatalf("unexpected refund transaction: %#v", result.RefundTransaction)
+	}
+}
+
 func TestSubscriptions_Postpone(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1031,3 +3373,37 @@ func TestSubscriptions_Postpone(t *testing.T) {
 		t.Fatal("expected postpone subscription change to return OK")
 	}
 }
+
+func TestSubscriptions_Bulk(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, action := range []string{"cancel", "reactivate", "terminate"} {
+		mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/"+action, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "PUT" {
+				t.Fatalf("unexpected method: %s", r.Method)
+			} else if bulk := r.URL.Query().Get("bulk"); bulk != "true" {
+				t.Fatalf("unexpected input for bulk: %s", bulk)
+			} else if strings.HasSuffix(r.URL.Path, "/terminate") && r.URL.Query().Get("refund_type") == "" {
+				t.Fatal("expected refund_type to be retained")
+			}
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+		})
+	}
+
+	uuid := "44f83d7cba354d5b84812419f923ea96"
+	for i, fn := range []func(uuid string, opts ...recurly.RequestOption) (*recurly.Response, *recurly.Subscription, error){
+		client.Subscriptions.Cancel,
+		client.Subscriptions.Reactivate,
+		client.Subscriptions.TerminateWithPartialRefund,
+		client.Subscriptions.TerminateWithFullRefund,
+		client.Subscriptions.TerminateWithoutRefund,
+	} {
+		if r, _, err := fn(uuid, recurly.WithBulk(true)); err != nil {
+			t.Fatalf("(%d) unexpected error: %v", i, err)
+		} else if r.IsError() {
+			t.Fatalf("(%d) expected bulk request to return OK", i)
+		}
+	}
+}
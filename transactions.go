@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"encoding/xml"
+	"errors"
 	"net"
 )
 
@@ -14,6 +15,10 @@ const (
 
 	// TransactionStatusVoid is the status for a voided transaction.
 	TransactionStatusVoid = "void"
+
+	// TransactionErrorCategoryThreeDSecure is the error category for
+	// transactions that require a 3D Secure challenge to be completed.
+	TransactionErrorCategoryThreeDSecure = "three_d_secure_action_required"
 )
 
 // Transaction represents an individual transaction.
@@ -41,8 +46,39 @@ type Transaction struct {
 	AVSResult        AVSResult         // Read only
 	AVSResultStreet  string            // Read only
 	AVSResultPostal  string            // Read only
+	Origin           string            // Read only
 	CreatedAt        NullTime          // Read only
+	CollectedAt      NullTime          // Read only
+	SettledAt        NullTime          // Read only
 	Account          Account
+
+	// 3D Secure
+	ThreeDSecureActionResultTokenID string
+	GatewayResponseTime             float64 // Read only
+	GatewayResponseCode             string  // Read only
+
+	// Extra holds the text of response elements not mapped to a field.
+	// It is only populated when the client's KeepUnknownXML is set, or when
+	// decoding into a Transaction whose Extra is already non-nil.
+	Extra map[string]string // Read only
+}
+
+// FetchInvoice retrieves the invoice the transaction belongs to using the
+// invoice number parsed from the transaction's invoice href.
+func (t Transaction) FetchInvoice(client *Client) (*Response, *Invoice, error) {
+	if t.InvoiceNumber == 0 {
+		return nil, nil, errors.New("recurly: transaction has no invoice")
+	}
+	return client.Invoices.Get(t.InvoiceNumber)
+}
+
+// FetchSubscription retrieves the subscription the transaction belongs to
+// using the UUID parsed from the transaction's subscription href.
+func (t Transaction) FetchSubscription(client *Client) (*Response, *Subscription, error) {
+	if t.SubscriptionUUID == "" {
+		return nil, nil, errors.New("recurly: transaction has no subscription")
+	}
+	return client.Subscriptions.Get(t.SubscriptionUUID)
 }
 
 // TransactionError is an error encounted from your payment gateway that
@@ -55,6 +91,31 @@ type TransactionError struct {
 	MerchantMessage  string   `xml:"merchant_message,omitempty"`
 	CustomerMessage  string   `xml:"customer_message,omitempty"`
 	GatewayErrorCode string   `xml:"gateway_error_code,omitempty"`
+
+	// ThreeDSecureActionTokenID is returned when the error category is
+	// TransactionErrorCategoryThreeDSecure. Pass it to recurly.js to complete
+	// the challenge, then retry with the resulting action result token.
+	ThreeDSecureActionTokenID string `xml:"three_d_secure_action_token_id,omitempty"`
+
+	// Details holds the gateway's per-field reasons for the error. They are
+	// read from the transaction_error element or, if it has none, from the
+	// details element of the transaction it belongs to.
+	Details []TransactionErrorDetail `xml:"details>detail,omitempty"`
+}
+
+// TransactionErrorDetail is an individual reason for a transaction error,
+// such as a mismatched postal code.
+type TransactionErrorDetail struct {
+	XMLName xml.Name `xml:"detail"`
+	Field   string   `xml:"field,attr,omitempty"`
+	Symbol  string   `xml:"symbol,attr,omitempty"`
+	Message string   `xml:",chardata"`
+}
+
+// IsThreeDSecureActionRequired returns true if the transaction requires a
+// 3D Secure challenge to be completed.
+func (e TransactionError) IsThreeDSecureActionRequired() bool {
+	return e.ErrorCategory == TransactionErrorCategoryThreeDSecure
 }
 
 // MarshalXML marshals a transaction sending only the fields recurly allows for writes.
@@ -79,6 +140,8 @@ func (t Transaction) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		Refundable    NullBool `xml:"refundable,omitempty"`
 		IPAddress     net.IP   `xml:"ip_address,omitempty"`
 		Account       Account  `xml:"account"`
+
+		ThreeDSecureActionResultTokenID string `xml:"three_d_secure_action_result_token_id,omitempty"`
 	}{
 		Action:        t.Action,
 		AmountInCents: t.AmountInCents,
@@ -96,6 +159,8 @@ func (t Transaction) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		Refundable:    t.Refundable,
 		IPAddress:     t.IPAddress,
 		Account:       t.Account,
+
+		ThreeDSecureActionResultTokenID: t.ThreeDSecureActionResultTokenID,
 	}
 	e.Encode(dst)
 	return nil
@@ -104,6 +169,7 @@ func (t Transaction) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 // UnmarshalXML unmarshals transactions and handles intermediary state during unmarshaling
 // for types like href.
 func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	keep := t.Extra != nil
 	var v struct {
 		XMLName          xml.Name          `xml:"transaction"`
 		InvoiceNumber    hrefInt           `xml:"invoice"`      // use hrefInt for parsing
@@ -122,14 +188,25 @@ func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		Test             bool              `xml:"test,omitempty"`
 		Voidable         NullBool          `xml:"voidable,omitempty"`
 		Refundable       NullBool          `xml:"refundable,omitempty"`
-		IPAddress        net.IP            `xml:"ip_address,omitempty"`
+		IPAddress        ipAddress         `xml:"ip_address,omitempty"`
 		TransactionError *TransactionError `xml:"transaction_error,omitempty"`
 		CVVResult        CVVResult         `xml:"cvv_result"`
 		AVSResult        AVSResult         `xml:"avs_result"`
 		AVSResultStreet  string            `xml:"avs_result_street,omitempty"`
 		AVSResultPostal  string            `xml:"avs_result_postal,omitempty"`
+		Origin           string            `xml:"origin,omitempty"`
 		CreatedAt        NullTime          `xml:"created_at,omitempty"`
+		CollectedAt      NullTime          `xml:"collected_at,omitempty"`
+		SettledAt        NullTime          `xml:"settled_at,omitempty"`
 		Account          Account           `xml:"details>account"`
+
+		ErrorDetails []TransactionErrorDetail `xml:"details>detail"`
+
+		ThreeDSecureActionResultTokenID string  `xml:"three_d_secure_action_result_token_id,omitempty"`
+		GatewayResponseTime             float64 `xml:"gateway_response_time,omitempty"`
+		GatewayResponseCode             string  `xml:"gateway_response_code,omitempty"`
+
+		Extra []extraElement `xml:",any"`
 	}
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
@@ -151,17 +228,29 @@ func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		Test:             v.Test,
 		Voidable:         v.Voidable,
 		Refundable:       v.Refundable,
-		IPAddress:        v.IPAddress,
+		IPAddress:        net.IP(v.IPAddress),
 		CVVResult:        v.CVVResult,
 		AVSResult:        v.AVSResult,
 		AVSResultStreet:  v.AVSResultStreet,
 		AVSResultPostal:  v.AVSResultPostal,
+		Origin:           v.Origin,
 		CreatedAt:        v.CreatedAt,
+		CollectedAt:      v.CollectedAt,
+		SettledAt:        v.SettledAt,
 		Account:          v.Account,
+
+		ThreeDSecureActionResultTokenID: v.ThreeDSecureActionResultTokenID,
+		GatewayResponseTime:             v.GatewayResponseTime,
+		GatewayResponseCode:             v.GatewayResponseCode,
+
+		Extra: extraMap(keep, v.Extra),
 	}
 
 	if v.TransactionError != nil {
 		t.TransactionError = v.TransactionError
+		if t.TransactionError.Details == nil {
+			t.TransactionError.Details = v.ErrorDetails
+		}
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -22,13 +23,7 @@ func (s *transactionsImpl) List(params Params) (*Response, []Transaction, error)
 		return nil, nil, err
 	}
 
-	var v struct {
-		XMLName      xml.Name      `xml:"transactions"`
-		Transactions []Transaction `xml:"transaction"`
-	}
-	resp, err := s.client.do(req, &v)
-
-	return resp, v.Transactions, err
+	return doList[Transaction](s.client, req, "transactions", "transaction")
 }
 
 // ListAccount returns a list of transactions for an account
@@ -40,13 +35,47 @@ func (s *transactionsImpl) ListAccount(accountCode string, params Params) (*Resp
 		return nil, nil, err
 	}
 
-	var v struct {
-		XMLName      xml.Name      `xml:"transactions"`
-		Transactions []Transaction `xml:"transaction"`
+	return doList[Transaction](s.client, req, "transactions", "transaction")
+}
+
+// ListForSubscription returns the transactions for a subscription.
+// Recurly does not provide a subscription transactions endpoint, so the
+// subscription is looked up to find its account. Every page of the account's
+// transactions is then fetched, following the cursor, and filtered to those
+// belonging to the subscription. Params are passed through when listing the
+// account's transactions; the returned Response is from the last page.
+func (s *transactionsImpl) ListForSubscription(subUUID string, params Params) (*Response, []Transaction, error) {
+	resp, sub, err := s.client.Subscriptions.Get(subUUID)
+	if err != nil || sub == nil {
+		return resp, nil, err
 	}
-	resp, err := s.client.do(req, &v)
 
-	return resp, v.Transactions, err
+	// Copy params so that paging doesn't modify the caller's map.
+	p := Params{}
+	for k, v := range params {
+		p[k] = v
+	}
+
+	var dst []Transaction
+	uuid := SanitizeUUID(subUUID)
+	for {
+		resp, transactions, err := s.ListAccount(sub.AccountCode, p)
+		if err != nil || resp.IsError() {
+			return resp, nil, err
+		}
+
+		for _, t := range transactions {
+			if t.SubscriptionUUID == uuid {
+				dst = append(dst, t)
+			}
+		}
+
+		cursor := resp.Next()
+		if cursor == "" {
+			return resp, dst, nil
+		}
+		p.Cursor(cursor)
+	}
 }
 
 // Get returns account and billing information at the time the transaction was
@@ -74,11 +103,13 @@ func (s *transactionsImpl) Get(uuid string) (*Response, *Transaction, error) {
 // creating an invoice, charge, and optionally account, and processing the
 // payment immediately. When creating an account all of the required account
 // attributes must be supplied. When charging an existing account only the
-// account_code must be supplied.
+// account_code must be supplied. If t.Currency is empty, the client's
+// DefaultCurrency is used.
 //
 // See the documentation and Transaction.MarshalXML function for a detailed field list.
 // https://dev.recurly.com/docs/create-transaction
 func (s *transactionsImpl) Create(t Transaction) (*Response, *Transaction, error) {
+	t.Currency = s.client.currency(t.Currency)
 	req, err := s.client.newRequest("POST", "transactions", nil, t)
 	if err != nil {
 		return nil, nil, err
@@ -89,7 +120,69 @@ func (s *transactionsImpl) Create(t Transaction) (*Response, *Transaction, error
 
 	// If there is an error set the response transaction as the returned transaction
 	// so that the caller has access to TransactionError.
-	if resp.IsError() {
+	if resp != nil && resp.IsError() {
+		if resp.transaction != nil {
+			dst = *resp.transaction
+		}
+	}
+
+	return resp, &dst, err
+}
+
+// CreateForAccount creates a one-off transaction like Create, charging an
+// existing account's billing info without a subscription. t.Account is
+// replaced with only the account code, so other account fields set on t are
+// not sent. If the charge is declined, the declined transaction is returned
+// with its TransactionError.
+// https://dev.recurly.com/docs/create-transaction
+func (s *transactionsImpl) CreateForAccount(accountCode string, t Transaction) (*Response, *Transaction, error) {
+	t.Account = Account{Code: accountCode}
+	return s.Create(t)
+}
+
+// Capture captures amountInCents of an authorized purchase. If
+// amountInCents is zero, the full authorized amount is captured. Recurly
+// rejects captures that exceed the authorized amount.
+// https://dev.recurly.com/docs/capture-purchase
+func (s *transactionsImpl) Capture(uuid string, amountInCents int) (*Response, *Transaction, error) {
+	if amountInCents < 0 {
+		return nil, nil, errors.New("recurly: capture amount cannot be negative")
+	}
+
+	var body interface{}
+	if amountInCents > 0 {
+		body = struct {
+			XMLName       xml.Name `xml:"transaction"`
+			AmountInCents int      `xml:"amount_in_cents"`
+		}{AmountInCents: amountInCents}
+	}
+
+	action := fmt.Sprintf("purchases/transaction-uuid-%s/capture", SanitizeUUID(uuid))
+	return s.updateAuthorization(action, body)
+}
+
+// Cancel cancels an authorized purchase, releasing the authorized amount
+// without capturing it.
+// https://dev.recurly.com/docs/cancel-purchase
+func (s *transactionsImpl) Cancel(uuid string) (*Response, *Transaction, error) {
+	action := fmt.Sprintf("purchases/transaction-uuid-%s/cancel", SanitizeUUID(uuid))
+	return s.updateAuthorization(action, nil)
+}
+
+// updateAuthorization sends a capture or cancel request for an authorized
+// transaction.
+func (s *transactionsImpl) updateAuthorization(action string, body interface{}) (*Response, *Transaction, error) {
+	req, err := s.client.newRequest("PUT", action, nil, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst Transaction
+	resp, err := s.client.do(req, &dst)
+
+	// If there is an error set the response transaction as the returned transaction
+	// so that the caller has access to TransactionError.
+	if resp != nil && resp.IsError() {
 		if resp.transaction != nil {
 			dst = *resp.transaction
 		}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +28,21 @@ func TestTransactions_Encoding(t *testing.T) {
 	if string(buf) != "<transaction><amount_in_cents>0</amount_in_cents><currency></currency><account></account></transaction>" {
 		t.Fatalf("unexpected encoding: %s", string(buf))
 	}
+
+	// Valid false values should be sent rather than omitted.
+	transaction = recurly.Transaction{
+		Recurring:  recurly.NewBool(false),
+		Voidable:   recurly.NewBool(false),
+		Refundable: recurly.NewBool(false),
+	}
+	buf, err = xml.Marshal(transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(buf) != "<transaction><amount_in_cents>0</amount_in_cents><currency></currency><recurring>false</recurring><voidable>false</voidable><refundable>false</refundable><account></account></transaction>" {
+		t.Fatalf("unexpected encoding: %s", string(buf))
+	}
 }
 
 func TestTransactions_List(t *testing.T) {
@@ -295,6 +311,112 @@ func TestTransactions_ListAccount(t *testing.T) {
 	}
 }
 
+func TestTransactions_ListForSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/17caaca1716f33572edc8146e0aaefde", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/17caaca1716f33572edc8146e0aaefde">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<uuid>17caaca1716f33572edc8146e0aaefde</uuid>
+			<state>active</state>
+		</subscription>`)
+	})
+
+	mux.HandleFunc("/v2/accounts/1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if pp := r.URL.Query().Get("per_page"); pp != "2" {
+			t.Fatalf("unexpected per_page: %s", pp)
+		}
+
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/accounts/1/transactions?cursor=1304958672>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+        <transactions type="array">
+        	<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+        		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/17caaca1716f33572edc8146e0aaefde"/>
+        		<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+        		<amount_in_cents type="integer">1000</amount_in_cents>
+        	</transaction>
+        	<transaction href="https://your-subdomain.recurly.com/v2/transactions/b23acd8fe4294916b79aec87b7ea441f" type="credit_card">
+        		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/27caaca1716f33572edc8146e0aaefde"/>
+        		<uuid>b23acd8fe4294916b79aec87b7ea441f</uuid>
+        		<amount_in_cents type="integer">2000</amount_in_cents>
+        	</transaction>
+        </transactions>`)
+			return
+		} else if cursor := r.URL.Query().Get("cursor"); cursor != "1304958672" {
+			t.Fatalf("unexpected cursor: %s", cursor)
+		}
+
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+        <transactions type="array">
+        	<transaction href="https://your-subdomain.recurly.com/v2/transactions/c33acd8fe4294916b79aec87b7ea441f" type="credit_card">
+        		<uuid>c33acd8fe4294916b79aec87b7ea441f</uuid>
+        		<amount_in_cents type="integer">3000</amount_in_cents>
+        	</transaction>
+        	<transaction href="https://your-subdomain.recurly.com/v2/transactions/d43acd8fe4294916b79aec87b7ea441f" type="credit_card">
+        		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/17caaca1716f33572edc8146e0aaefde"/>
+        		<uuid>d43acd8fe4294916b79aec87b7ea441f</uuid>
+        		<amount_in_cents type="integer">4000</amount_in_cents>
+        	</transaction>
+        </transactions>`)
+	})
+
+	params := recurly.Params{"per_page": 2}
+	r, transactions, err := client.Transactions.ListForSubscription("17caaca1-716f33572edc8146-e0aaefde", params) // UUID has dashes
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected list for subscription transactions to return OK")
+	} else if cursor := r.Request.URL.Query().Get("cursor"); cursor != "1304958672" {
+		t.Fatalf("expected response from the last page, cursor: %s", cursor)
+	} else if _, ok := params["cursor"]; ok {
+		t.Fatalf("unexpected cursor in params: %v", params)
+	}
+
+	if !reflect.DeepEqual(transactions, []recurly.Transaction{
+		{
+			SubscriptionUUID: "17caaca1716f33572edc8146e0aaefde",
+			UUID:             "a13acd8fe4294916b79aec87b7ea441f",
+			AmountInCents:    1000,
+		},
+		{
+			SubscriptionUUID: "17caaca1716f33572edc8146e0aaefde",
+			UUID:             "d43acd8fe4294916b79aec87b7ea441f",
+			AmountInCents:    4000,
+		},
+	}) {
+		t.Fatalf("unexpected transactions: %#v", transactions)
+	}
+}
+
+func TestTransactions_ListForSubscription_ErrNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/17caaca1716f33572edc8146e0aaefde", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+
+	r, transactions, err := client.Transactions.ListForSubscription("17caaca1716f33572edc8146e0aaefde", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.StatusCode != 404 {
+		t.Fatalf("unexpected status code: %d", r.StatusCode)
+	} else if transactions != nil {
+		t.Fatalf("unexpected transactions: %v", transactions)
+	}
+}
+
 func TestTransactions_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -444,6 +566,82 @@ func TestTransactions_Get_ErrNotFound(t *testing.T) {
 	}
 }
 
+func TestTransactions_FetchInvoiceAndSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+				<invoice href="https://your-subdomain.recurly.com/v2/invoices/1108"/>
+				<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/17caaca1716f33572edc8146e0aaefde"/>
+				<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+				<action>purchase</action>
+				<amount_in_cents type="integer">1000</amount_in_cents>
+				<currency>USD</currency>
+				<status>success</status>
+			</transaction>`)
+	})
+	mux.HandleFunc("/v2/invoices/1108", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><invoice><invoice_number type="integer">1108</invoice_number></invoice>`)
+	})
+	mux.HandleFunc("/v2/subscriptions/17caaca1716f33572edc8146e0aaefde", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>17caaca1716f33572edc8146e0aaefde</uuid></subscription>`)
+	})
+
+	_, tx, err := client.Transactions.Get("a13acd8fe4294916b79aec87b7ea441f")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp, invoice, err := tx.FetchInvoice(client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected fetch invoice to return OK")
+	} else if invoice.InvoiceNumber != 1108 {
+		t.Fatalf("unexpected invoice number: %d", invoice.InvoiceNumber)
+	}
+
+	if resp, sub, err := tx.FetchSubscription(client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected fetch subscription to return OK")
+	} else if sub.UUID != "17caaca1716f33572edc8146e0aaefde" {
+		t.Fatalf("unexpected subscription uuid: %s", sub.UUID)
+	}
+}
+
+func TestTransactions_FetchInvoiceAndSubscription_Missing(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var tx recurly.Transaction
+	if _, invoice, err := tx.FetchInvoice(client); err == nil {
+		t.Fatal("expected error")
+	} else if invoice != nil {
+		t.Fatalf("unexpected invoice: %#v", invoice)
+	}
+
+	if _, sub, err := tx.FetchSubscription(client); err == nil {
+		t.Fatal("expected error")
+	} else if sub != nil {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	}
+}
+
 func TestTransactions_New(t *testing.T) {
 	setup()
 	defer teardown()
@@ -481,6 +679,199 @@ func TestTransactions_New(t *testing.T) {
 	}
 }
 
+func TestTransactions_CreateForAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		expected := `<transaction><amount_in_cents>500</amount_in_cents><currency>USD</currency><description>Replacement cable</description><account><account_code>25</account_code></account></transaction>`
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected != given.String() {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+
+		w.WriteHeader(201)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+				<account href="https://your-subdomain.recurly.com/v2/accounts/25"/>
+				<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+				<action>purchase</action>
+				<amount_in_cents type="integer">500</amount_in_cents>
+				<currency>USD</currency>
+				<status>success</status>
+			</transaction>`)
+	})
+
+	r, tx, err := client.Transactions.CreateForAccount("25", recurly.Transaction{
+		AmountInCents: 500,
+		Currency:      "USD",
+		Description:   "Replacement cable",
+		Account:       recurly.Account{Code: "ignored", Email: "verena@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected create transaction to return OK")
+	} else if tx.UUID != "a13acd8fe4294916b79aec87b7ea441f" || tx.Status != recurly.TransactionStatusSuccess {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	}
+}
+
+func TestTransactions_CreateForAccount_Declined(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+			  <transaction_error>
+			    <error_code>insufficient_funds</error_code>
+			    <error_category>soft</error_category>
+			    <merchant_message>The card has insufficient funds to cover the cost of the transaction.</merchant_message>
+			    <customer_message>The payment method does not have sufficient funds to complete the transaction.</customer_message>
+			  </transaction_error>
+			  <error field="transaction.account.base" symbol="insufficient_funds">The payment method does not have sufficient funds to complete the transaction.</error>
+			  <transaction href="https://your-subdomain.recurly.com/v2/transactions/3054a79e4c3ab4699f95be455f8653bb" type="credit_card">
+			    <account href="https://your-subdomain.recurly.com/v2/accounts/25"/>
+			    <uuid>3054a79e4c3ab4699f95be455f8653bb</uuid>
+			    <action>purchase</action>
+			    <amount_in_cents type="integer">500</amount_in_cents>
+			    <currency>USD</currency>
+			    <status>declined</status>
+			    <transaction_error>
+			      <error_code>insufficient_funds</error_code>
+			      <error_category>soft</error_category>
+			    </transaction_error>
+			  </transaction>
+			</errors>`)
+	})
+
+	r, tx, err := client.Transactions.CreateForAccount("25", recurly.Transaction{AmountInCents: 500, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsOK() {
+		t.Fatal("expected declined transaction to return an error status")
+	} else if tx.UUID != "3054a79e4c3ab4699f95be455f8653bb" || tx.Status != "declined" {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	} else if tx.TransactionError == nil || tx.TransactionError.ErrorCode != "insufficient_funds" {
+		t.Fatalf("unexpected transaction error: %#v", tx.TransactionError)
+	}
+}
+
+func TestTransactions_Capture(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/purchases/transaction-uuid-a13acd8fe4294916b79aec87b7ea441f/capture", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if given.Len() != 0 {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><transaction><uuid>a13acd8fe4294916b79aec87b7ea441f</uuid><action>purchase</action><amount_in_cents type="integer">1000</amount_in_cents><status>success</status></transaction>`)
+	})
+
+	r, tx, err := client.Transactions.Capture("a13acd8f-e4294916b79aec87b-7ea441f", 0) // UUID has dashes and should be sanitized
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected capture transaction to return OK")
+	} else if tx.AmountInCents != 1000 || tx.Status != recurly.TransactionStatusSuccess {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	}
+}
+
+func TestTransactions_Capture_Partial(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/purchases/transaction-uuid-a13acd8fe4294916b79aec87b7ea441f/capture", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		expected := "<transaction><amount_in_cents>600</amount_in_cents></transaction>"
+		var given bytes.Buffer
+		given.ReadFrom(r.Body)
+		if expected != given.String() {
+			t.Fatalf("unexpected input: %s", given.String())
+		}
+
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><transaction><uuid>a13acd8fe4294916b79aec87b7ea441f</uuid><action>purchase</action><amount_in_cents type="integer">600</amount_in_cents><status>success</status></transaction>`)
+	})
+
+	r, tx, err := client.Transactions.Capture("a13acd8fe4294916b79aec87b7ea441f", 600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected capture transaction to return OK")
+	} else if tx.AmountInCents != 600 {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	}
+
+	if _, _, err := client.Transactions.Capture("a13acd8fe4294916b79aec87b7ea441f", -1); err == nil {
+		t.Fatal("expected error capturing a negative amount")
+	}
+}
+
+func TestTransactions_Cancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/purchases/transaction-uuid-a13acd8fe4294916b79aec87b7ea441f/cancel", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><transaction><uuid>a13acd8fe4294916b79aec87b7ea441f</uuid><action>authorization</action><amount_in_cents type="integer">1000</amount_in_cents><status>void</status></transaction>`)
+	})
+
+	r, tx, err := client.Transactions.Cancel("a13acd8fe4294916b79aec87b7ea441f")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if r.IsError() {
+		t.Fatal("expected cancel transaction to return OK")
+	} else if tx.Status != recurly.TransactionStatusVoid {
+		t.Fatalf("unexpected transaction: %#v", tx)
+	}
+}
+
+// TestTransactions_NetworkError ensures methods return the error instead of
+// panicking when there is no response.
+func TestTransactions_NetworkError(t *testing.T) {
+	setup()
+	defer teardown()
+	server.Close()
+
+	if resp, tx, err := client.Transactions.Create(recurly.Transaction{AmountInCents: 100, Currency: "USD"}); err == nil {
+		t.Fatal("expected create error")
+	} else if resp != nil || tx.TransactionError != nil {
+		t.Fatalf("unexpected result: %#v, %#v", resp, tx)
+	}
+
+	if resp, _, err := client.Transactions.Capture("a13acd8fe4294916b79aec87b7ea441f", 0); err == nil {
+		t.Fatal("expected capture error")
+	} else if resp != nil {
+		t.Fatalf("unexpected response: %#v", resp)
+	}
+
+	if resp, _, err := client.Transactions.Cancel("a13acd8fe4294916b79aec87b7ea441f"); err == nil {
+		t.Fatal("expected cancel error")
+	} else if resp != nil {
+		t.Fatalf("unexpected response: %#v", resp)
+	}
+}
+
 func TestTransactions_Err_FraudCard(t *testing.T) {
 	setup()
 	defer teardown()
@@ -560,6 +951,169 @@ func TestTransactions_Err_FraudCard(t *testing.T) {
 	}
 }
 
+func TestTransactions_Err_ThreeDSecure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+
+		w.WriteHeader(422)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<errors>
+			  <transaction_error>
+			    <error_code>three_d_secure_action_required</error_code>
+			    <error_category>three_d_secure_action_required</error_category>
+			    <merchant_message>Your payment gateway is requesting a 3D Secure challenge.</merchant_message>
+			    <customer_message>Your card must be authenticated with 3D Secure before continuing.</customer_message>
+			    <three_d_secure_action_token_id>7z4YpLXb2jTsAxxjkq9oBk</three_d_secure_action_token_id>
+			  </transaction_error>
+			  <error field="transaction.account.base" symbol="three_d_secure_action_required">Your card must be authenticated with 3D Secure before continuing.</error>
+			  <transaction href="https://your-subdomain.recurly.com/v2/transactions/3054a79e4c3ab4699f95be455f8653bb" type="credit_card">
+			    <uuid>3054a79e4c3ab4699f95be455f8653bb</uuid>
+			    <action>purchase</action>
+			    <amount_in_cents type="integer">100</amount_in_cents>
+			    <currency>USD</currency>
+			    <status>declined</status>
+			    <payment_method>credit_card</payment_method>
+			    <gateway_response_time type="decimal">0.254</gateway_response_time>
+			    <gateway_response_code>authentication_required</gateway_response_code>
+			    <transaction_error>
+			      <error_code>three_d_secure_action_required</error_code>
+			      <error_category>three_d_secure_action_required</error_category>
+			      <merchant_message>Your payment gateway is requesting a 3D Secure challenge.</merchant_message>
+			      <customer_message>Your card must be authenticated with 3D Secure before continuing.</customer_message>
+			      <three_d_secure_action_token_id>7z4YpLXb2jTsAxxjkq9oBk</three_d_secure_action_token_id>
+			    </transaction_error>
+			    <details>
+			    </details>
+			  </transaction>
+			</errors>`)
+	})
+
+	r, transaction, err := client.Transactions.Create(recurly.Transaction{
+		AmountInCents: 100,
+		Currency:      "USD",
+		Account: recurly.Account{
+			Code: "25",
+			BillingInfo: &recurly.Billing{
+				Token: "507c7f79bcf86cd7994f6c0e",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error occurred making API call. Err: %s", err)
+	} else if r.IsOK() {
+		t.Fatal("expected create 3D Secure transaction to return error")
+	} else if !reflect.DeepEqual(transaction, &recurly.Transaction{
+		UUID:          "3054a79e4c3ab4699f95be455f8653bb",
+		Action:        "purchase",
+		AmountInCents: 100,
+		Currency:      "USD",
+		Status:        "declined",
+		PaymentMethod: "credit_card",
+		TransactionError: &recurly.TransactionError{
+			XMLName:                   xml.Name{Local: "transaction_error"},
+			ErrorCode:                 "three_d_secure_action_required",
+			ErrorCategory:             "three_d_secure_action_required",
+			MerchantMessage:           "Your payment gateway is requesting a 3D Secure challenge.",
+			CustomerMessage:           "Your card must be authenticated with 3D Secure before continuing.",
+			ThreeDSecureActionTokenID: "7z4YpLXb2jTsAxxjkq9oBk",
+		},
+		GatewayResponseTime: 0.254,
+		GatewayResponseCode: "authentication_required",
+	}) {
+		t.Fatalf("unexpected transaction: %#v", transaction)
+	} else if !transaction.TransactionError.IsThreeDSecureActionRequired() {
+		t.Fatal("expected 3D Secure action to be required")
+	}
+}
+
+func TestTransactions_Get_InvalidIPAddress(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transactions/a13acd8fe4294916b79aec87b7ea441f", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transaction>
+				<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+				<ip_address>not-an-ip</ip_address>
+			</transaction>`)
+	})
+
+	_, _, err := client.Transactions.Get("a13acd8fe4294916b79aec87b7ea441f")
+	if decodeErr, ok := err.(*recurly.DecodeError); !ok {
+		t.Fatalf("unexpected error: %#v", err)
+	} else if decodeErr.Element != "ip_address" {
+		t.Fatalf("unexpected element: %s", decodeErr.Element)
+	} else if !strings.Contains(err.Error(), `invalid IP address "not-an-ip"`) {
+		t.Fatalf("unexpected error message: %s", err.Error())
+	}
+}
+
+func TestTransactions_Decode_Settled(t *testing.T) {
+	var tx recurly.Transaction
+	if err := xml.Unmarshal([]byte(`<transaction href="https://your-subdomain.recurly.com/v2/transactions/a13acd8fe4294916b79aec87b7ea441f" type="credit_card">
+		<uuid>a13acd8fe4294916b79aec87b7ea441f</uuid>
+		<action>purchase</action>
+		<amount_in_cents type="integer">1000</amount_in_cents>
+		<currency>USD</currency>
+		<status>success</status>
+		<origin>recurly</origin>
+		<created_at type="datetime">2015-06-10T15:25:06Z</created_at>
+		<collected_at type="datetime">2015-06-10T15:25:07Z</collected_at>
+		<settled_at type="datetime">2015-06-12T00:00:00Z</settled_at>
+	</transaction>`), &tx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if tx.Origin != "recurly" {
+		t.Fatalf("unexpected origin: %s", tx.Origin)
+	} else if !tx.CollectedAt.Time.Equal(time.Date(2015, time.June, 10, 15, 25, 7, 0, time.UTC)) {
+		t.Fatalf("unexpected collected at: %v", tx.CollectedAt)
+	} else if !tx.SettledAt.Time.Equal(time.Date(2015, time.June, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected settled at: %v", tx.SettledAt)
+	}
+
+	// Read only fields should never be sent.
+	if buf, err := xml.Marshal(recurly.Transaction{
+		AmountInCents: 100,
+		Currency:      "USD",
+		Origin:        tx.Origin,
+		CollectedAt:   tx.CollectedAt,
+		SettledAt:     tx.SettledAt,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if string(buf) != "<transaction><amount_in_cents>100</amount_in_cents><currency>USD</currency><account></account></transaction>" {
+		t.Fatalf("unexpected encoding: %s", string(buf))
+	}
+}
+
+func TestTransactions_ThreeDSecure_Encoding(t *testing.T) {
+	transaction := recurly.Transaction{
+		AmountInCents: 100,
+		Currency:      "USD",
+		Account: recurly.Account{
+			Code: "25",
+			BillingInfo: &recurly.Billing{
+				Token:                           "507c7f79bcf86cd7994f6c0e",
+				ThreeDSecureActionResultTokenID: "8a5Gs9uOHBDZ3OSpN3Ow",
+			},
+		},
+		ThreeDSecureActionResultTokenID: "8a5Gs9uOHBDZ3OSpN3Ow",
+		GatewayResponseCode:             "read_only",
+	}
+	buf, err := xml.Marshal(transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(buf) != "<transaction><amount_in_cents>100</amount_in_cents><currency>USD</currency><account><account_code>25</account_code><billing_info><token_id>507c7f79bcf86cd7994f6c0e</token_id><three_d_secure_action_result_token_id>8a5Gs9uOHBDZ3OSpN3Ow</three_d_secure_action_result_token_id></billing_info></account><three_d_secure_action_result_token_id>8a5Gs9uOHBDZ3OSpN3Ow</three_d_secure_action_result_token_id></transaction>" {
+		t.Fatalf("unexpected encoding: %s", string(buf))
+	}
+}
+
 func TestCVV(t *testing.T) {
 	c := recurly.CVVResult{recurly.TransactionResult{Code: "M"}}
 	if !c.IsMatch() {
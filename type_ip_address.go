@@ -0,0 +1,34 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ipAddress unmarshals IP addresses. Surrounding whitespace is ignored, an
+// empty element is a nil IP, and a malformed address is an error rather
+// than a garbage value.
+type ipAddress net.IP
+
+// UnmarshalXML unmarshals an IPv4 or IPv6 address.
+func (ip *ipAddress) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v string
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	v = strings.TrimSpace(v)
+	if v == "" {
+		*ip = nil
+		return nil
+	}
+
+	parsed := net.ParseIP(v)
+	if parsed == nil {
+		return fmt.Errorf("recurly: invalid IP address %q", v)
+	}
+	*ip = ipAddress(parsed)
+	return nil
+}
@@ -0,0 +1,43 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestTypeIPAddressUnmarshal(t *testing.T) {
+	type s struct {
+		XMLName   xml.Name  `xml:"s"`
+		IPAddress ipAddress `xml:"ip_address"`
+	}
+
+	tests := []struct {
+		xml      string
+		expected net.IP
+	}{
+		{xml: "<s><ip_address></ip_address></s>", expected: nil},
+		{xml: `<s><ip_address nil="nil"/></s>`, expected: nil},
+		{xml: "<s></s>", expected: nil},
+		{xml: "<s><ip_address>127.0.0.1</ip_address></s>", expected: net.ParseIP("127.0.0.1")},
+		{xml: "<s><ip_address>\n\t127.0.0.1\n</ip_address></s>", expected: net.ParseIP("127.0.0.1")},
+		{xml: "<s><ip_address>2001:db8::68</ip_address></s>", expected: net.ParseIP("2001:db8::68")},
+	}
+
+	for i, tt := range tests {
+		var given s
+		if err := xml.Unmarshal([]byte(tt.xml), &given); err != nil {
+			t.Fatalf("(%d) unexpected error: %v", i, err)
+		} else if !reflect.DeepEqual(net.IP(given.IPAddress), tt.expected) {
+			t.Fatalf("(%d) unexpected ip address: %#v", i, given.IPAddress)
+		}
+	}
+
+	var given s
+	if err := xml.Unmarshal([]byte("<s><ip_address>127.0.0</ip_address></s>"), &given); err == nil {
+		t.Fatalf("expected error, got %v", net.IP(given.IPAddress))
+	} else if err.Error() != `recurly: invalid IP address "127.0.0"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -38,11 +38,11 @@ func (n *NullBool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
-// MarshalXML marshals NullBools to XML. Otherwise nothing is
-// marshaled.
+// MarshalXML marshals valid NullBools to XML, including false values which
+// are sent as <field>false</field>. Otherwise nothing is marshaled.
 func (n NullBool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	if n.Valid {
-		e.EncodeElement(n.Bool, start)
+		return e.EncodeElement(n.Bool, start)
 	}
 
 	return nil
@@ -51,7 +51,7 @@ func (n NullBool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 // MarshalJSON
 func (n NullBool) MarshalJSON() ([]byte, error) {
 	if !n.Valid {
-		return []byte(`nil`), nil
+		return []byte(`null`), nil
 	}
 	if n.Bool {
 		return []byte("true"), nil
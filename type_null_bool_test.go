@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"reflect"
 	"testing"
@@ -62,3 +63,22 @@ func TestNullBool(t *testing.T) {
 		}
 	}
 }
+
+func TestNullBool_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		v        NullBool
+		expected string
+	}{
+		{v: NewBool(true), expected: "true"},
+		{v: NewBool(false), expected: "false"},
+		{v: NullBool{}, expected: "null"},
+	}
+
+	for i, tt := range tests {
+		if given, err := json.Marshal(tt.v); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if string(given) != tt.expected {
+			t.Fatalf("(%d): unexpected value: %s", i, given)
+		}
+	}
+}
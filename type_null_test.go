@@ -0,0 +1,63 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestNullTypes ensures the null types behave the same way: null values are
+// omitted from XML and encoded as null in JSON, and valid values, including
+// zero values, round trip through XML.
+func TestNullTypes(t *testing.T) {
+	ts := time.Date(2015, time.June, 3, 13, 42, 23, 0, time.UTC)
+	tests := []struct {
+		null  interface{}
+		valid interface{}
+		xml   string
+		json  string
+	}{
+		{null: &NullInt{}, valid: &NullInt{Int: 0, Valid: true}, xml: "<v>0</v>", json: "0"},
+		{null: &NullInt{}, valid: &NullInt{Int: 12, Valid: true}, xml: "<v>12</v>", json: "12"},
+		{null: &NullBool{}, valid: &NullBool{Bool: false, Valid: true}, xml: "<v>false</v>", json: "false"},
+		{null: &NullBool{}, valid: &NullBool{Bool: true, Valid: true}, xml: "<v>true</v>", json: "true"},
+		{null: &NullTime{}, valid: &NullTime{Time: &ts}, xml: "<v>2015-06-03T13:42:23Z</v>", json: `"2015-06-03T13:42:23Z"`},
+	}
+
+	for i, tt := range tests {
+		start := xml.StartElement{Name: xml.Name{Local: "v"}}
+		if b := marshalNull(t, tt.null, start); b != "" {
+			t.Fatalf("(%d): unexpected null xml: %s", i, b)
+		} else if b, err := json.Marshal(tt.null); err != nil || string(b) != "null" {
+			t.Fatalf("(%d): unexpected null json: %s, %v", i, b, err)
+		}
+
+		if b := marshalNull(t, tt.valid, start); b != tt.xml {
+			t.Fatalf("(%d): unexpected xml: %s", i, b)
+		} else if b, err := json.Marshal(tt.valid); err != nil || string(b) != tt.json {
+			t.Fatalf("(%d): unexpected json: %s, %v", i, b, err)
+		}
+
+		dst := reflect.New(reflect.TypeOf(tt.valid).Elem()).Interface()
+		if err := xml.Unmarshal([]byte(tt.xml), dst); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if b := marshalNull(t, dst, start); b != tt.xml {
+			t.Fatalf("(%d): unexpected round trip: %s", i, b)
+		}
+	}
+}
+
+// marshalNull encodes v as the element start.
+func marshalNull(t *testing.T, v interface{}, start xml.StartElement) string {
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	if err := e.EncodeElement(v, start); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	} else if err := e.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	return buf.String()
+}
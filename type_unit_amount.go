@@ -35,3 +35,14 @@ func (u UnitAmount) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 	return nil
 }
+
+// Amount returns the amount in currency, or 0 if currency is not supported.
+func (u UnitAmount) Amount(currency string) int {
+	switch currency {
+	case "USD":
+		return u.USD
+	case "EUR":
+		return u.EUR
+	}
+	return 0
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sync"
 
 	"github.com/portofinolabs/recurly"
 )
@@ -24,6 +25,8 @@ const (
 	ExpiredSubscription     = "expired_subscription_notification"
 	CanceledSubscription    = "canceled_subscription_notification"
 	ReactivatedSubscription = "reactivated_subcription_notification"
+	PausedSubscription      = "paused_subscription_notification"
+	ResumedSubscription     = "resumed_subscription_notification"
 
 	// Invoice notifications.
 	NewInvoice        = "new_invoice_notification"
@@ -41,9 +44,17 @@ const (
 	FailedPayment     = "failed_payment_notification"
 	VoidPayment       = "void_payment_notification"
 	SuccessfulRefund  = "successful_refund_notification"
+	ScheduledPayment  = "scheduled_payment_notification"
 
 	// Dunning Event notifications.
 	NewDunningEvent = "new_dunning_event_notification"
+
+	// Credit Payment notifications.
+	NewCreditPayment  = "new_credit_payment_notification"
+	VoidCreditPayment = "void_credit_payment_notification"
+
+	// Gift Card notifications.
+	LowBalanceGiftCard = "low_balance_gift_card_notification"
 )
 
 type notificationName struct {
@@ -89,12 +100,13 @@ type Invoice struct {
 	State               string           `xml:"state,omitempty" json:"state"`
 	InvoiceNumberPrefix string           `xml:"invoice_number_prefix,omitempty" json:"invoice_number_prefix"`
 	InvoiceNumber       int              `xml:"invoice_number,omitempty" json:"invoice_number"`
-	PONumber            string           `xml:"po_number,omitempty" json:"vat_number"`
+	PONumber            string           `xml:"po_number,omitempty" json:"po_number"`
 	VATNumber           string           `xml:"vat_number,omitempty" json:"vat_number"`
 	TotalInCents        int              `xml:"total_in_cents,omitempty" json:"total_in_cents"`
 	Currency            string           `xml:"currency,omitempty" json:"currency"`
 	CreatedAt           recurly.NullTime `xml:"date,omitempty" json:"created_at"`
 	ClosedAt            recurly.NullTime `xml:"closed_at,omitempty" json:"closed_at"`
+	DueOn               recurly.NullTime `xml:"due_on,omitempty" json:"due_on"`
 	NetTerms            recurly.NullInt  `xml:"net_terms,omitempty" json:"net_terms"`
 	CollectionMethod    string           `xml:"collection_method,omitempty" json:"collection_method"`
 }
@@ -121,6 +133,39 @@ type ShippingAdddress struct {
 type DunningEvent struct {
 }
 
+// CreditPayment represents the credit payment object sent in webhooks.
+type CreditPayment struct {
+	XMLName                   xml.Name         `xml:"credit_payment,omitempty" json:"-"`
+	UUID                      string           `xml:"uuid,omitempty" json:"uuid"`
+	Action                    string           `xml:"action,omitempty" json:"action"`
+	Currency                  string           `xml:"currency,omitempty" json:"currency"`
+	AmountInCents             int              `xml:"amount_in_cents,omitempty" json:"amount_in_cents"`
+	OriginalInvoiceNumber     int              `xml:"original_invoice_number,omitempty" json:"original_invoice_number"`
+	AppliedToInvoiceNumber    int              `xml:"applied_to_invoice_number,omitempty" json:"applied_to_invoice_number"`
+	OriginalCreditPaymentUUID string           `xml:"original_credit_payment_uuid,omitempty" json:"original_credit_payment_uuid,omitempty"`
+	RefundTransactionUUID     string           `xml:"refund_transaction_uuid,omitempty" json:"refund_transaction_uuid,omitempty"`
+	CreatedAt                 recurly.NullTime `xml:"created_at,omitempty" json:"created_at"`
+	VoidedAt                  recurly.NullTime `xml:"voided_at,omitempty" json:"voided_at"`
+}
+
+// GiftCard represents the gift card object sent in webhooks.
+type GiftCard struct {
+	XMLName              xml.Name         `xml:"gift_card,omitempty" json:"-"`
+	ID                   int64            `xml:"id,omitempty" json:"id"`
+	RedemptionCode       string           `xml:"redemption_code,omitempty" json:"redemption_code"`
+	ProductCode          string           `xml:"product_code,omitempty" json:"product_code"`
+	UnitAmountInCents    int              `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents"`
+	BalanceInCents       int              `xml:"balance_in_cents,omitempty" json:"balance_in_cents"`
+	Currency             string           `xml:"currency,omitempty" json:"currency"`
+	GifterAccountCode    string           `xml:"gifter_account_code,omitempty" json:"gifter_account_code"`
+	RecipientAccountCode string           `xml:"recipient_account_code,omitempty" json:"recipient_account_code"`
+	InvoiceNumber        int              `xml:"invoice_number,omitempty" json:"invoice_number"`
+	CreatedAt            recurly.NullTime `xml:"created_at,omitempty" json:"created_at"`
+	DeliveredAt          recurly.NullTime `xml:"delivered_at,omitempty" json:"delivered_at"`
+	RedeemedAt           recurly.NullTime `xml:"redeemed_at,omitempty" json:"redeemed_at"`
+	CanceledAt           recurly.NullTime `xml:"canceled_at,omitempty" json:"canceled_at"`
+}
+
 // Transaction constants.
 const (
 	TransactionFailureTypeDeclined  = "declined"
@@ -183,6 +228,20 @@ type (
 		Account      Account              `xml:"account" json:"account"`
 		Subscription recurly.Subscription `xml:"subscription" json:"subscription"`
 	}
+
+	// PausedSubscriptionNotification is sent when a subscription is paused.
+	// https://dev.recurly.com/page/webhooks#section-paused-subscription
+	PausedSubscriptionNotification struct {
+		Account      Account              `xml:"account" json:"account"`
+		Subscription recurly.Subscription `xml:"subscription" json:"subscription"`
+	}
+
+	// ResumedSubscriptionNotification is sent when a paused subscription is resumed.
+	// https://dev.recurly.com/page/webhooks#section-resumed-subscription
+	ResumedSubscriptionNotification struct {
+		Account      Account              `xml:"account" json:"account"`
+		Subscription recurly.Subscription `xml:"subscription" json:"subscription"`
+	}
 )
 
 // Invoice types.
@@ -241,6 +300,14 @@ type (
 		Account     Account     `xml:"account" json:"account"`
 		Transaction Transaction `xml:"transaction" json:"transaction"`
 	}
+
+	// ScheduledPaymentNotification is sent when a payment is scheduled for
+	// an asynchronous payment method.
+	// https://dev.recurly.com/page/webhooks#section-scheduled-payment
+	ScheduledPaymentNotification struct {
+		Account     Account     `xml:"account" json:"account"`
+		Transaction Transaction `xml:"transaction" json:"transaction"`
+	}
 )
 
 // Shipping Address types.
@@ -261,6 +328,32 @@ type (
 	}
 )
 
+// Credit Payment types.
+type (
+	// NewCreditPaymentNotification is sent when a credit payment is applied
+	// to an invoice.
+	// https://dev.recurly.com/page/webhooks#section-new-credit-payment
+	NewCreditPaymentNotification struct {
+		Account       Account       `xml:"account" json:"account"`
+		CreditPayment CreditPayment `xml:"credit_payment" json:"credit_payment"`
+	}
+
+	// VoidCreditPaymentNotification is sent when a credit payment is voided.
+	// https://dev.recurly.com/page/webhooks#section-voided-credit-payment
+	VoidCreditPaymentNotification struct {
+		Account       Account       `xml:"account" json:"account"`
+		CreditPayment CreditPayment `xml:"credit_payment" json:"credit_payment"`
+	}
+)
+
+// LowBalanceGiftCardNotification is sent when a redeemed gift card's
+// remaining balance is running low.
+// https://dev.recurly.com/page/webhooks#section-low-balance-gift-card
+type LowBalanceGiftCardNotification struct {
+	Account  Account  `xml:"account" json:"account"`
+	GiftCard GiftCard `xml:"gift_card" json:"gift_card"`
+}
+
 type NewDunningEventNotification struct {
 	Account     Account              `xml:"account" json:"account"`
 	Invoice     Invoice              `xml:"invoice" json:"invoice"`
@@ -284,20 +377,82 @@ func (e ErrUnknownNotification) Name() string {
 	return e.name
 }
 
+// ErrNotificationTooLarge is used when the incoming webhook is larger than
+// the limit passed to ParseLimit. It implements the error interface.
+type ErrNotificationTooLarge struct {
+	limit int64
+}
+
+// Error implements the error interface.
+func (e ErrNotificationTooLarge) Error() string {
+	return fmt.Sprintf("notification exceeds %d bytes", e.limit)
+}
+
+// Limit returns the size limit in bytes that was exceeded.
+func (e ErrNotificationTooLarge) Limit() int64 {
+	return e.limit
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func() interface{})
+)
+
+// RegisterNotification registers factory for notifications named name, such
+// as a notification Recurly added after this package was released. Parse
+// unmarshals matching notifications into the value factory returns, which
+// must be a pointer, and returns it as the ParseResponse's Data. Registered
+// names take precedence over the built-in notification types, so
+// registering a built-in name replaces its type. RegisterNotification
+// panics if name is empty or factory is nil.
+func RegisterNotification(name string, factory func() interface{}) {
+	if name == "" {
+		panic("webhooks: RegisterNotification with empty name")
+	} else if factory == nil {
+		panic("webhooks: RegisterNotification with nil factory for " + name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// registered returns the factory registered for name, if any.
+func registered(name string) (func() interface{}, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// DefaultMaxBytes is the largest notification Parse will read.
+const DefaultMaxBytes = 10 << 20
+
 type ParseResponse struct {
 	Message string
 	Data    interface{}
 }
 
 // Parse parses an incoming webhook and returns the notification.
+// Notifications larger than DefaultMaxBytes are rejected with
+// ErrNotificationTooLarge.
 func Parse(r io.Reader) (*ParseResponse, error) {
+	return ParseLimit(r, DefaultMaxBytes)
+}
+
+// ParseLimit parses an incoming webhook like Parse, but rejects notifications
+// larger than maxBytes with ErrNotificationTooLarge instead of reading them
+// into memory.
+func ParseLimit(r io.Reader, maxBytes int64) (*ParseResponse, error) {
 	if closer, ok := r.(io.Closer); ok {
 		defer closer.Close()
 	}
 
-	notification, err := ioutil.ReadAll(r)
+	notification, err := ioutil.ReadAll(io.LimitReader(r, maxBytes+1))
 	if err != nil {
 		return nil, err
+	} else if int64(len(notification)) > maxBytes {
+		return nil, ErrNotificationTooLarge{limit: maxBytes}
 	}
 
 	var n notificationName
@@ -306,7 +461,58 @@ func Parse(r io.Reader) (*ParseResponse, error) {
 	}
 
 	var dst interface{}
-	switch n.XMLName.Local {
+	if factory, ok := registered(n.XMLName.Local); ok {
+		dst = factory()
+	} else {
+		dst = builtinNotification(n.XMLName.Local)
+	}
+	if dst == nil {
+		return nil, ErrUnknownNotification{name: n.XMLName.Local}
+	}
+
+	if err := xml.Unmarshal(notification, dst); err != nil {
+		return nil, err
+	}
+
+	response := &ParseResponse{
+		Message: n.XMLName.Local,
+		Data:    dst,
+	}
+	return response, nil
+}
+
+// Detect returns the name of the notification in r, such as
+// NewSubscription, without unmarshaling it. Only the start of the body is
+// read, so a health check endpoint can cheaply confirm that a notification,
+// such as one sent by Recurly's test webhook feature, is recognized. If the
+// name is neither built in nor registered with RegisterNotification, the
+// name is returned along with ErrUnknownNotification.
+func Detect(r io.Reader) (string, error) {
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		name := start.Name.Local
+		if _, ok := registered(name); !ok && builtinNotification(name) == nil {
+			return name, ErrUnknownNotification{name: name}
+		}
+		return name, nil
+	}
+}
+
+// builtinNotification returns a new notification of the type built into
+// the package for name, or nil if there is none.
+func builtinNotification(name string) interface{} {
+	var dst interface{}
+	switch name {
 	case NewAccount:
 		dst = &NewAccountNotification{}
 	case UpdatedAccount:
@@ -325,6 +531,10 @@ func Parse(r io.Reader) (*ParseResponse, error) {
 		dst = &ExpiredSubscriptionNotification{}
 	case CanceledSubscription:
 		dst = &CanceledSubscriptionNotification{}
+	case PausedSubscription:
+		dst = &PausedSubscriptionNotification{}
+	case ResumedSubscription:
+		dst = &ResumedSubscriptionNotification{}
 	case NewInvoice:
 		dst = &NewInvoiceNotification{}
 	case PastDueInvoice:
@@ -341,6 +551,8 @@ func Parse(r io.Reader) (*ParseResponse, error) {
 		dst = &VoidPaymentNotification{}
 	case SuccessfulRefund:
 		dst = &SuccessfulRefundNotification{}
+	case ScheduledPayment:
+		dst = &ScheduledPaymentNotification{}
 	case NewShippingAddress:
 		dst = &NewShippingAddressNotification{}
 	case UpdatedShippingAddress:
@@ -349,17 +561,12 @@ func Parse(r io.Reader) (*ParseResponse, error) {
 		dst = &DeletedShippingAddressNotification{}
 	case NewDunningEvent:
 		dst = &NewDunningEventNotification{}
-	default:
-		return nil, ErrUnknownNotification{name: n.XMLName.Local}
-	}
-
-	if err := xml.Unmarshal(notification, dst); err != nil {
-		return nil, err
+	case NewCreditPayment:
+		dst = &NewCreditPaymentNotification{}
+	case VoidCreditPayment:
+		dst = &VoidCreditPaymentNotification{}
+	case LowBalanceGiftCard:
+		dst = &LowBalanceGiftCardNotification{}
 	}
-
-	response := &ParseResponse{
-		Message: n.XMLName.Local,
-		Data:    dst,
-	}
-	return response, nil
+	return dst
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,8 +17,8 @@ func TestParse_BillingInfoUpdatedNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.BillingInfoUpdatedNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.BillingInfoUpdatedNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.BillingInfoUpdatedNotification{
 		Account: webhooks.Account{
 			XMLName:   xml.Name{Local: "account"},
@@ -42,8 +43,8 @@ func TestParse_NewSubscriptionNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.NewSubscriptionNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.NewSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.NewSubscriptionNotification{
 		Account: webhooks.Account{
 			XMLName:   xml.Name{Local: "account"},
@@ -67,6 +68,8 @@ func TestParse_NewSubscriptionNotification(t *testing.T) {
 			ExpiresAt:              recurly.NewTime(expiresTs),
 			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
 			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
 		},
 	}) {
 		t.Fatalf("unexpected notification: %#v", n)
@@ -84,8 +87,8 @@ func TestParse_UpdatedSubscriptionNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.UpdatedSubscriptionNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.UpdatedSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.UpdatedSubscriptionNotification{
 		Account: webhooks.Account{
 			XMLName:   xml.Name{Local: "account"},
@@ -109,6 +112,8 @@ func TestParse_UpdatedSubscriptionNotification(t *testing.T) {
 			ExpiresAt:              recurly.NewTime(expiresTs),
 			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
 			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
 		},
 	}) {
 		t.Fatalf("unexpected notification: %#v", n)
@@ -124,8 +129,8 @@ func TestParse_RenewedSubscriptionNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.RenewedSubscriptionNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.RenewedSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.RenewedSubscriptionNotification{
 		Account: webhooks.Account{
 			XMLName:     xml.Name{Local: "account"},
@@ -148,6 +153,8 @@ func TestParse_RenewedSubscriptionNotification(t *testing.T) {
 			ActivatedAt:            recurly.NewTime(activatedTs),
 			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
 			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
 		},
 	}) {
 		t.Fatalf("unexpected notification: %#v", n)
@@ -165,8 +172,8 @@ func TestParse_ExpiredSubscriptionNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.ExpiredSubscriptionNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.ExpiredSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.ExpiredSubscriptionNotification{
 		Account: webhooks.Account{
 			XMLName:   xml.Name{Local: "account"},
@@ -190,6 +197,8 @@ func TestParse_ExpiredSubscriptionNotification(t *testing.T) {
 			ExpiresAt:              recurly.NewTime(expiresTs),
 			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
 			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
 		},
 	}) {
 		t.Fatalf("unexpected notification: %#v", n)
@@ -207,8 +216,8 @@ func TestParse_CanceledSubscriptionNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.CanceledSubscriptionNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.CanceledSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.CanceledSubscriptionNotification{
 		Account: webhooks.Account{
 			XMLName:   xml.Name{Local: "account"},
@@ -232,6 +241,96 @@ func TestParse_CanceledSubscriptionNotification(t *testing.T) {
 			ExpiresAt:              recurly.NewTime(expiresTs),
 			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
 			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_PausedSubscriptionNotification(t *testing.T) {
+	activatedTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-23T22:05:03Z")
+	expiresTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-24T22:05:03Z")
+	startedTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-23T22:05:03Z")
+	endsTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-24T22:05:03Z")
+
+	xmlFile := MustOpenFile("testdata/paused_subscription_notification.xml")
+	result, err := webhooks.Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if result.Message != webhooks.PausedSubscription {
+		t.Fatalf("unexpected message: %s", result.Message)
+	} else if n, ok := result.Data.(*webhooks.PausedSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if !reflect.DeepEqual(n, &webhooks.PausedSubscriptionNotification{
+		Account: webhooks.Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Subscription: recurly.Subscription{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "1dpt",
+				Name: "Subscription One",
+			},
+			UUID:                   "dccd742f4710e78515714d275839f891",
+			State:                  "paused",
+			Quantity:               1,
+			TotalAmountInCents:     200,
+			ActivatedAt:            recurly.NewTime(activatedTs),
+			ExpiresAt:              recurly.NewTime(expiresTs),
+			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
+			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_ResumedSubscriptionNotification(t *testing.T) {
+	activatedTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-23T22:05:03Z")
+	expiresTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-24T22:05:03Z")
+	startedTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-23T22:05:03Z")
+	endsTs, _ := time.Parse(recurly.DateTimeFormat, "2010-09-24T22:05:03Z")
+
+	xmlFile := MustOpenFile("testdata/resumed_subscription_notification.xml")
+	result, err := webhooks.Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if result.Message != webhooks.ResumedSubscription {
+		t.Fatalf("unexpected message: %s", result.Message)
+	} else if n, ok := result.Data.(*webhooks.ResumedSubscriptionNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if !reflect.DeepEqual(n, &webhooks.ResumedSubscriptionNotification{
+		Account: webhooks.Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Subscription: recurly.Subscription{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "1dpt",
+				Name: "Subscription One",
+			},
+			UUID:                   "dccd742f4710e78515714d275839f891",
+			State:                  "active",
+			Quantity:               1,
+			TotalAmountInCents:     200,
+			ActivatedAt:            recurly.NewTime(activatedTs),
+			ExpiresAt:              recurly.NewTime(expiresTs),
+			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
+			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+			SubscriptionAddOns:     []recurly.SubscriptionAddOn{},
 		},
 	}) {
 		t.Fatalf("unexpected notification: %#v", n)
@@ -244,8 +343,8 @@ func TestParse_NewInvoiceNotification(t *testing.T) {
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.NewInvoiceNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.NewInvoiceNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.NewInvoiceNotification{
 		Account: webhooks.Account{
 			XMLName:   xml.Name{Local: "account"},
@@ -273,11 +372,12 @@ func TestParse_NewInvoiceNotification(t *testing.T) {
 func TestParse_PastDueInvoiceNotification(t *testing.T) {
 	xmlFile := MustOpenFile("testdata/past_due_invoice_notification.xml")
 	createdAt := time.Date(2014, 1, 1, 20, 21, 44, 0, time.UTC)
+	dueOn := time.Date(2014, 1, 31, 20, 21, 44, 0, time.UTC)
 	result, err := webhooks.Parse(xmlFile)
 	if err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.PastDueInvoiceNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.PastDueInvoiceNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.PastDueInvoiceNotification{
 		Account: webhooks.Account{
 			XMLName:     xml.Name{Local: "account"},
@@ -293,6 +393,7 @@ func TestParse_PastDueInvoiceNotification(t *testing.T) {
 			UUID:          "ffc64d71d4b5404e93f13aac9c63b007",
 			State:         "past_due",
 			CreatedAt:     recurly.NullTime{Time: &createdAt},
+			DueOn:         recurly.NullTime{Time: &dueOn},
 			InvoiceNumber: 1000,
 			TotalInCents:  1100,
 		},
@@ -305,8 +406,8 @@ func TestParse_SuccessfulPaymentNotification(t *testing.T) {
 	xmlFile := MustOpenFile("testdata/successful_payment_notification.xml")
 	if result, err := webhooks.Parse(xmlFile); err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.SuccessfulPaymentNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.SuccessfulPaymentNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.SuccessfulPaymentNotification{
 		Account: webhooks.Account{
 			XMLName:     xml.Name{Local: "account"},
@@ -340,8 +441,8 @@ func TestParse_FailedPaymentNotification(t *testing.T) {
 	xmlFile := MustOpenFile("testdata/failed_payment_notification.xml")
 	if result, err := webhooks.Parse(xmlFile); err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.FailedPaymentNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.FailedPaymentNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.FailedPaymentNotification{
 		Account: webhooks.Account{
 			XMLName:     xml.Name{Local: "account"},
@@ -377,8 +478,8 @@ func TestParse_VoidPaymentNotification(t *testing.T) {
 	xmlFile := MustOpenFile("testdata/void_payment_notification.xml")
 	if result, err := webhooks.Parse(xmlFile); err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.VoidPaymentNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.VoidPaymentNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.VoidPaymentNotification{
 		Account: webhooks.Account{
 			XMLName:     xml.Name{Local: "account"},
@@ -413,8 +514,8 @@ func TestParse_SuccessfulRefundNotification(t *testing.T) {
 	xmlFile := MustOpenFile("testdata/successful_refund_notification.xml")
 	if result, err := webhooks.Parse(xmlFile); err != nil {
 		t.Fatal(err)
-	} else if n, ok := result.(*webhooks.SuccessfulRefundNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+	} else if n, ok := result.Data.(*webhooks.SuccessfulRefundNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
 	} else if !reflect.DeepEqual(n, &webhooks.SuccessfulRefundNotification{
 		Account: webhooks.Account{
 			XMLName:     xml.Name{Local: "account"},
@@ -445,13 +546,157 @@ func TestParse_SuccessfulRefundNotification(t *testing.T) {
 	}
 }
 
+func TestParse_ScheduledPaymentNotification(t *testing.T) {
+	xmlFile := MustOpenFile("testdata/scheduled_payment_notification.xml")
+	if result, err := webhooks.Parse(xmlFile); err != nil {
+		t.Fatal(err)
+	} else if result.Message != webhooks.ScheduledPayment {
+		t.Fatalf("unexpected message: %s", result.Message)
+	} else if n, ok := result.Data.(*webhooks.ScheduledPaymentNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if !reflect.DeepEqual(n, &webhooks.ScheduledPaymentNotification{
+		Account: webhooks.Account{
+			XMLName:     xml.Name{Local: "account"},
+			Code:        "1",
+			Username:    "verena",
+			Email:       "verena@example.com",
+			FirstName:   "Verena",
+			LastName:    "Example",
+			CompanyName: "Company, Inc.",
+		},
+		Transaction: webhooks.Transaction{
+			XMLName:       xml.Name{Local: "transaction"},
+			UUID:          "a5143c1d3a6f4a8287d0e2cc1d4c0427",
+			InvoiceNumber: 2059,
+			Action:        "purchase",
+			AmountInCents: 1000,
+			Status:        "scheduled",
+			Message:       "Payment scheduled",
+			Reference:     "reference",
+			Source:        "subscription",
+			Test:          recurly.NullBool{Valid: true, Bool: true},
+			Voidable:      recurly.NullBool{Valid: true, Bool: false},
+			Refundable:    recurly.NullBool{Valid: true, Bool: false},
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_NewCreditPaymentNotification(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:56:42Z")
+
+	xmlFile := MustOpenFile("testdata/new_credit_payment_notification.xml")
+	if result, err := webhooks.Parse(xmlFile); err != nil {
+		t.Fatal(err)
+	} else if result.Message != webhooks.NewCreditPayment {
+		t.Fatalf("unexpected message: %s", result.Message)
+	} else if n, ok := result.Data.(*webhooks.NewCreditPaymentNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if !reflect.DeepEqual(n, &webhooks.NewCreditPaymentNotification{
+		Account: webhooks.Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		CreditPayment: webhooks.CreditPayment{
+			XMLName:                xml.Name{Local: "credit_payment"},
+			UUID:                   "4059af0cdd3cbad4f1a9c2406b6b9b87",
+			Action:                 "payment",
+			Currency:               "USD",
+			AmountInCents:          3579,
+			OriginalInvoiceNumber:  1313,
+			AppliedToInvoiceNumber: 1314,
+			CreatedAt:              recurly.NewTime(createdTs),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_VoidCreditPaymentNotification(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:56:42Z")
+	voidedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-02T19:26:43Z")
+
+	xmlFile := MustOpenFile("testdata/void_credit_payment_notification.xml")
+	if result, err := webhooks.Parse(xmlFile); err != nil {
+		t.Fatal(err)
+	} else if result.Message != webhooks.VoidCreditPayment {
+		t.Fatalf("unexpected message: %s", result.Message)
+	} else if n, ok := result.Data.(*webhooks.VoidCreditPaymentNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if !reflect.DeepEqual(n, &webhooks.VoidCreditPaymentNotification{
+		Account: webhooks.Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		CreditPayment: webhooks.CreditPayment{
+			XMLName:                xml.Name{Local: "credit_payment"},
+			UUID:                   "4059af0cdd3cbad4f1a9c2406b6b9b87",
+			Action:                 "payment",
+			Currency:               "USD",
+			AmountInCents:          3579,
+			OriginalInvoiceNumber:  1313,
+			AppliedToInvoiceNumber: 1314,
+			CreatedAt:              recurly.NewTime(createdTs),
+			VoidedAt:               recurly.NewTime(voidedTs),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_LowBalanceGiftCardNotification(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2016-06-27T16:36:35Z")
+	redeemedTs, _ := time.Parse(recurly.DateTimeFormat, "2016-06-27T16:37:11Z")
+
+	xmlFile := MustOpenFile("testdata/low_balance_gift_card_notification.xml")
+	if result, err := webhooks.Parse(xmlFile); err != nil {
+		t.Fatal(err)
+	} else if result.Message != webhooks.LowBalanceGiftCard {
+		t.Fatalf("unexpected message: %s", result.Message)
+	} else if n, ok := result.Data.(*webhooks.LowBalanceGiftCardNotification); !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if !reflect.DeepEqual(n, &webhooks.LowBalanceGiftCardNotification{
+		Account: webhooks.Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		GiftCard: webhooks.GiftCard{
+			XMLName:              xml.Name{Local: "gift_card"},
+			ID:                   2008976331180115114, // Overflows int on 32-bit platforms.
+			RedemptionCode:       "8EA8BFCD0FBD6C94",
+			ProductCode:          "gift_card",
+			UnitAmountInCents:    1000,
+			BalanceInCents:       200,
+			Currency:             "USD",
+			GifterAccountCode:    "5",
+			RecipientAccountCode: "1",
+			InvoiceNumber:        1212,
+			CreatedAt:            recurly.NewTime(createdTs),
+			DeliveredAt:          recurly.NewTime(createdTs),
+			RedeemedAt:           recurly.NewTime(redeemedTs),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
 func TestParse_ErrUnknownNotification(t *testing.T) {
 	xmlFile := MustOpenFile("testdata/unknown_notification.xml")
 	result, err := webhooks.Parse(xmlFile)
 	if result != nil {
 		t.Fatalf("unexpected notification: %#v", result)
 	} else if e, ok := err.(webhooks.ErrUnknownNotification); !ok {
-		t.Fatalf("unexpected type: %T, result")
+		t.Fatalf("unexpected error type: %T", err)
 	} else if err.Error() != "unknown notification: unknown_notification" {
 		t.Fatalf("unexpected error string: %s", err.Error())
 	} else if e.Name() != "unknown_notification" {
@@ -459,6 +704,100 @@ func TestParse_ErrUnknownNotification(t *testing.T) {
 	}
 }
 
+// usageNotification is a notification type registered by a caller.
+type usageNotification struct {
+	Account struct {
+		Code string `xml:"account_code"`
+	} `xml:"account"`
+	Usage struct {
+		ID     int `xml:"id"`
+		Amount int `xml:"amount"`
+	} `xml:"usage"`
+}
+
+func TestParse_RegisterNotification(t *testing.T) {
+	webhooks.RegisterNotification("new_usage_notification", func() interface{} {
+		return &usageNotification{}
+	})
+
+	result, err := webhooks.Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+		<new_usage_notification>
+			<account>
+				<account_code>1</account_code>
+			</account>
+			<usage>
+				<id type="integer">394729929104688227</id>
+				<amount type="integer">25</amount>
+			</usage>
+		</new_usage_notification>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if result.Message != "new_usage_notification" {
+		t.Fatalf("unexpected message: %s", result.Message)
+	}
+
+	n, ok := result.Data.(*usageNotification)
+	if !ok {
+		t.Fatalf("unexpected type: %T", result.Data)
+	} else if n.Account.Code != "1" || n.Usage.ID != 394729929104688227 || n.Usage.Amount != 25 {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, name := range []string{
+		webhooks.NewAccount,
+		webhooks.NewSubscription,
+		webhooks.CanceledSubscription,
+		webhooks.SuccessfulPayment,
+		webhooks.LowBalanceGiftCard,
+	} {
+		body := `<?xml version="1.0" encoding="UTF-8"?><` + name + `><account><account_code>1</account_code></account></` + name + `>`
+		if detected, err := webhooks.Detect(strings.NewReader(body)); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		} else if detected != name {
+			t.Fatalf("%s: unexpected name: %s", name, detected)
+		}
+	}
+
+	// Only the start of the body is read, so malformed content after the
+	// root element is not an error.
+	if name, err := webhooks.Detect(strings.NewReader(`<new_invoice_notification><invoice>`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if name != webhooks.NewInvoice {
+		t.Fatalf("unexpected name: %s", name)
+	}
+
+	name, err := webhooks.Detect(MustOpenFile("testdata/unknown_notification.xml"))
+	if e, ok := err.(webhooks.ErrUnknownNotification); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if name != "unknown_notification" || e.Name() != "unknown_notification" {
+		t.Fatalf("unexpected name: %s, %s", name, e.Name())
+	}
+
+	if _, err := webhooks.Detect(strings.NewReader("")); err == nil {
+		t.Fatal("expected error for empty body")
+	}
+}
+
+func TestParseLimit_ErrNotificationTooLarge(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?><new_account_notification><account><account_code>` + strings.Repeat("1", 1024) + `</account_code></account></new_account_notification>`
+	result, err := webhooks.ParseLimit(strings.NewReader(body), 512)
+	if result != nil {
+		t.Fatalf("unexpected notification: %#v", result)
+	} else if e, ok := err.(webhooks.ErrNotificationTooLarge); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err.Error() != "notification exceeds 512 bytes" {
+		t.Fatalf("unexpected error string: %s", err.Error())
+	} else if e.Limit() != 512 {
+		t.Fatalf("unexpected limit: %d", e.Limit())
+	}
+
+	if _, err := webhooks.ParseLimit(strings.NewReader(body), int64(len(body))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func MustOpenFile(name string) *os.File {
 	file, err := os.Open(name)
 	if err != nil {